@@ -22,7 +22,9 @@ import (
 	"github.com/rknizzle/rkmesh/auth"
 	"github.com/rknizzle/rkmesh/domain"
 	"github.com/rknizzle/rkmesh/filestore"
+	"github.com/rknizzle/rkmesh/material"
 	"github.com/rknizzle/rkmesh/model"
+	"github.com/rknizzle/rkmesh/printer"
 )
 
 func init() {
@@ -92,6 +94,32 @@ func main() {
 	modelRoutes.Use(middleware.JWT([]byte(os.Getenv("JWT_SECRET_KEY"))))
 	model.NewModelHandler(modelRoutes, s)
 
+	plateRoutes := e.Group("/plates")
+	plateRoutes.Use(middleware.JWT([]byte(os.Getenv("JWT_SECRET_KEY"))))
+	model.NewPlateHandler(plateRoutes, s)
+
+	nestRoutes := e.Group("/nests")
+	nestRoutes.Use(middleware.JWT([]byte(os.Getenv("JWT_SECRET_KEY"))))
+	model.NewNestHandler(nestRoutes, s)
+
+	// materials handling
+	mr := material.NewPostgresMaterialRepository(dbConn)
+	materialService := material.NewMaterialService(mr, m, modelFileStorage, timeoutContext)
+
+	materialRoutes := e.Group("/materials")
+	materialRoutes.Use(middleware.JWT([]byte(os.Getenv("JWT_SECRET_KEY"))))
+	material.NewMaterialHandler(materialRoutes, materialService)
+	material.NewQuoteHandler(modelRoutes, materialService)
+
+	// printers handling
+	pr := printer.NewPostgresPrinterRepository(dbConn)
+	printerService := printer.NewPrinterService(pr, mr, m, modelFileStorage, timeoutContext)
+
+	printerRoutes := e.Group("/printers")
+	printerRoutes.Use(middleware.JWT([]byte(os.Getenv("JWT_SECRET_KEY"))))
+	printer.NewPrinterHandler(printerRoutes, printerService)
+	printer.NewFitHandler(modelRoutes, printerService)
+
 	log.Fatal(e.Start(":" + os.Getenv("PORT")))
 }
 
@@ -125,7 +153,10 @@ func runDatabaseMigrations(dbConn *sql.DB) error {
 		return err
 	}
 
-	mig.Steps(2)
+	err = mig.Up()
+	if err != nil && err != migrate.ErrNoChange {
+		return err
+	}
 	return nil
 }
 
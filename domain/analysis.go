@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// MeshAnalysis is a report on whether the mesh of a model is printable
+type MeshAnalysis struct {
+	ModelID                  int64     `json:"model_id"`
+	NonManifoldEdges         int64     `json:"non_manifold_edges"`
+	BoundaryEdges            int64     `json:"boundary_edges"`
+	Holes                    int64     `json:"holes"`
+	InconsistentlyWoundFaces int64     `json:"inconsistently_wound_faces"`
+	DegenerateTriangles      int64     `json:"degenerate_triangles"`
+	DuplicateTriangles       int64     `json:"duplicate_triangles"`
+	Shells                   int64     `json:"shells"`
+	Watertight               bool      `json:"watertight"`
+	CreatedAt                time.Time `json:"created_at"`
+}
+
+// SupportAnalysis describes the support that a model needs when it is built up along a direction
+type SupportAnalysis struct {
+	ModelID   int64 `json:"model_id"`
+	Direction Point `json:"direction"`
+	// OverhangAngle is the steepest angle in degrees from vertical that prints without support
+	OverhangAngle float64 `json:"overhang_angle"`
+	OverhangArea  float64 `json:"overhang_area"`
+	SupportVolume float64 `json:"support_volume"`
+	// ContactArea is the area where support touches the model
+	ContactArea   float64 `json:"contact_area"`
+	OverhangFaces int64   `json:"overhang_faces"`
+	// Mask marks the faces that need support, in the order of the triangles of the content endpoint
+	Mask []bool `json:"mask"`
+}
+
+// ThicknessAnalysis reports the walls of a model that are too thin to print. Lengths are in mm.
+type ThicknessAnalysis struct {
+	ModelID int64  `json:"model_id"`
+	Process string `json:"process"`
+	// MinThickness is the thinnest wall that the process can print
+	MinThickness float64 `json:"min_thickness"`
+	// Thinnest is the thinnest wall of the model, or null when no wall could be measured
+	Thinnest *float64     `json:"thinnest"`
+	ThinArea float64      `json:"thin_area"`
+	Regions  []ThinRegion `json:"regions"`
+	// Thickness is the thickness of the wall at every vertex in the order of the vertices of the
+	// content endpoint, or null where it could not be measured
+	Thickness []*float64 `json:"thickness"`
+}
+
+// ThinRegion is a connected area of a model whose wall is thinner than the minimum
+type ThinRegion struct {
+	Triangles   int64       `json:"triangles"`
+	Area        float64     `json:"area"`
+	Thinnest    float64     `json:"thinnest"`
+	BoundingBox BoundingBox `json:"bounding_box"`
+}
+
+// Diff is the deviation between the surfaces of two models. Distances are in mm.
+type Diff struct {
+	ModelID int64 `json:"model_id"`
+	OtherID int64 `json:"other_id"`
+	// Aligned is set when the model was fitted onto the other model before they were compared
+	Aligned bool `json:"aligned"`
+	// Matrix moves the model from its own coordinates onto the other model in mm, in the form that
+	// the transform endpoint accepts
+	Matrix [][]float64 `json:"matrix"`
+	// Hausdorff is the largest distance from either surface to the other
+	Hausdorff float64 `json:"hausdorff"`
+	Mean      float64 `json:"mean"`
+	RMS       float64 `json:"rms"`
+	// Deviation is the distance from every vertex to the surface of the other model in the order of
+	// the vertices of the content endpoint. It is positive outside of the other model and negative
+	// inside of it.
+	Deviation []float64 `json:"deviation"`
+}
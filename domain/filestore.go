@@ -7,5 +7,10 @@ import (
 
 type Filestore interface {
 	Upload(ctx context.Context, file io.Reader, filename string) (string, error)
+	// UploadWithID stores a file under a known id, replacing any existing file with that id. It is
+	// used for files that are derived from a model and can be found again from the model
+	UploadWithID(ctx context.Context, file io.Reader, id string) error
+	// Download returns the content of a file. ErrNotFound is returned if no file has the id
+	Download(ctx context.Context, id string) (io.ReadCloser, error)
 	GetDirectDownloadURL(id string) (string, error)
 }
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Material is a material that models can be printed in
+type Material struct {
+	ID int64 `json:"id"`
+	// UserID is the user that added the material. Materials of the catalog do not have one and can be
+	// used by every user.
+	UserID *int64 `json:"user_id"`
+	Name   string `json:"name" validate:"required"`
+	// Process is the printing technology that the material is used with, like fdm, sla or sls
+	Process string `json:"process" validate:"required"`
+	// Density in g/cm³
+	Density   float64   `json:"density" validate:"gt=0"`
+	CostPerKg float64   `json:"cost_per_kg" validate:"gte=0"`
+	Colour    string    `json:"colour"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MachineRate is what it costs to run the machines of a printing process
+type MachineRate struct {
+	Process string `json:"process"`
+	// HourlyRate is the cost of an hour of machine time
+	HourlyRate float64 `json:"hourly_rate"`
+	// BuildRate is the volume in cm³ that a machine prints per hour, which estimates the machine time
+	// of models that do not have a toolpath
+	BuildRate float64 `json:"build_rate"`
+	// SupportDensity is the fraction of the support volume that is filled with material
+	SupportDensity float64 `json:"support_density"`
+	// SetupFee is charged once per job
+	SetupFee float64 `json:"setup_fee"`
+	// Markup is the fraction that is added on top of the costs
+	Markup float64 `json:"markup"`
+}
+
+// QuoteRequest selects what a model is quoted in
+type QuoteRequest struct {
+	MaterialID int64 `json:"material_id" validate:"required"`
+	Quantity   int64 `json:"quantity" validate:"gte=1"`
+}
+
+// Quote is the price of printing a model, broken down into line items
+type Quote struct {
+	ModelID    int64 `json:"model_id"`
+	MaterialID int64 `json:"material_id"`
+	Quantity   int64 `json:"quantity"`
+	// Weight is the grams of material that a single part uses, including its support
+	Weight float64 `json:"weight"`
+	// MachineTime is the hours that it takes to print a single part
+	MachineTime float64     `json:"machine_time"`
+	LineItems   []QuoteLine `json:"line_items"`
+	Total       float64     `json:"total"`
+}
+
+// QuoteLine is a single line item of a quote
+type QuoteLine struct {
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+// MaterialService represent the materials business logic
+type MaterialService interface {
+	GetAll(ctx context.Context, userID int64) ([]Material, error)
+	Store(ctx context.Context, m *Material) error
+	Quote(ctx context.Context, modelID int64, userID int64, req QuoteRequest) (Quote, error)
+}
+
+// MaterialRepository represent the materials repository contract
+type MaterialRepository interface {
+	GetAll(ctx context.Context, userID int64) ([]Material, error)
+	GetByID(ctx context.Context, id int64, userID int64) (Material, error)
+	Store(ctx context.Context, m *Material) error
+	GetMachineRate(ctx context.Context, process string) (MachineRate, error)
+}
@@ -15,6 +15,50 @@ type Filestore struct {
 	mock.Mock
 }
 
+// Download provides a mock function with given fields: ctx, id
+func (_m *Filestore) Download(ctx context.Context, id string) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 io.ReadCloser
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadCloser); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDirectDownloadURL provides a mock function with given fields: id
+func (_m *Filestore) GetDirectDownloadURL(id string) (string, error) {
+	ret := _m.Called(id)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Upload provides a mock function with given fields: ctx, file, filename
 func (_m *Filestore) Upload(ctx context.Context, file io.Reader, filename string) (string, error) {
 	ret := _m.Called(ctx, file, filename)
@@ -35,3 +79,17 @@ func (_m *Filestore) Upload(ctx context.Context, file io.Reader, filename string
 
 	return r0, r1
 }
+
+// UploadWithID provides a mock function with given fields: ctx, file, id
+func (_m *Filestore) UploadWithID(ctx context.Context, file io.Reader, id string) error {
+	ret := _m.Called(ctx, file, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string) error); ok {
+		r0 = rf(ctx, file, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
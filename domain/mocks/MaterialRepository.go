@@ -0,0 +1,94 @@
+// Code generated by mockery v2.3.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/rknizzle/rkmesh/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MaterialRepository is an autogenerated mock type for the MaterialRepository type
+type MaterialRepository struct {
+	mock.Mock
+}
+
+// GetAll provides a mock function with given fields: ctx, userID
+func (_m *MaterialRepository) GetAll(ctx context.Context, userID int64) ([]domain.Material, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []domain.Material
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.Material); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Material)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id, userID
+func (_m *MaterialRepository) GetByID(ctx context.Context, id int64, userID int64) (domain.Material, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 domain.Material
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Material); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Material)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMachineRate provides a mock function with given fields: ctx, process
+func (_m *MaterialRepository) GetMachineRate(ctx context.Context, process string) (domain.MachineRate, error) {
+	ret := _m.Called(ctx, process)
+
+	var r0 domain.MachineRate
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.MachineRate); ok {
+		r0 = rf(ctx, process)
+	} else {
+		r0 = ret.Get(0).(domain.MachineRate)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, process)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: ctx, m
+func (_m *MaterialRepository) Store(ctx context.Context, m *domain.Material) error {
+	ret := _m.Called(ctx, m)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Material) error); ok {
+		r0 = rf(ctx, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
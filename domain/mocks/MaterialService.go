@@ -0,0 +1,73 @@
+// Code generated by mockery v2.3.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/rknizzle/rkmesh/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MaterialService is an autogenerated mock type for the MaterialService type
+type MaterialService struct {
+	mock.Mock
+}
+
+// GetAll provides a mock function with given fields: ctx, userID
+func (_m *MaterialService) GetAll(ctx context.Context, userID int64) ([]domain.Material, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []domain.Material
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.Material); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Material)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Quote provides a mock function with given fields: ctx, modelID, userID, req
+func (_m *MaterialService) Quote(ctx context.Context, modelID int64, userID int64, req domain.QuoteRequest) (domain.Quote, error) {
+	ret := _m.Called(ctx, modelID, userID, req)
+
+	var r0 domain.Quote
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, domain.QuoteRequest) domain.Quote); ok {
+		r0 = rf(ctx, modelID, userID, req)
+	} else {
+		r0 = ret.Get(0).(domain.Quote)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, domain.QuoteRequest) error); ok {
+		r1 = rf(ctx, modelID, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: ctx, m
+func (_m *MaterialService) Store(ctx context.Context, m *domain.Material) error {
+	ret := _m.Called(ctx, m)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Material) error); ok {
+		r0 = rf(ctx, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -51,6 +51,27 @@ func (_m *ModelRepository) GetAllUserModels(ctx context.Context, userID int64) (
 	return r0, r1
 }
 
+// GetAnalysis provides a mock function with given fields: ctx, modelID
+func (_m *ModelRepository) GetAnalysis(ctx context.Context, modelID int64) (domain.MeshAnalysis, error) {
+	ret := _m.Called(ctx, modelID)
+
+	var r0 domain.MeshAnalysis
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.MeshAnalysis); ok {
+		r0 = rf(ctx, modelID)
+	} else {
+		r0 = ret.Get(0).(domain.MeshAnalysis)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, modelID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id, userID
 func (_m *ModelRepository) GetByID(ctx context.Context, id int64, userID int64) (domain.Model, error) {
 	ret := _m.Called(ctx, id, userID)
@@ -106,3 +127,31 @@ func (_m *ModelRepository) Store(ctx context.Context, m *domain.Model) error {
 
 	return r0
 }
+
+// StoreAnalysis provides a mock function with given fields: ctx, a
+func (_m *ModelRepository) StoreAnalysis(ctx context.Context, a *domain.MeshAnalysis) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.MeshAnalysis) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreSupportVolume provides a mock function with given fields: ctx, id, volume
+func (_m *ModelRepository) StoreSupportVolume(ctx context.Context, id int64, volume float64) error {
+	ret := _m.Called(ctx, id, volume)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, float64) error); ok {
+		r0 = rf(ctx, id, volume)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -16,6 +16,48 @@ type ModelService struct {
 	mock.Mock
 }
 
+// ArrangePlate provides a mock function with given fields: ctx, userID, req
+func (_m *ModelService) ArrangePlate(ctx context.Context, userID int64, req domain.PlateRequest) (domain.Plate, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	var r0 domain.Plate
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.PlateRequest) domain.Plate); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(domain.Plate)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.PlateRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Boolean provides a mock function with given fields: ctx, userID, req
+func (_m *ModelService) Boolean(ctx context.Context, userID int64, req domain.BooleanRequest) (domain.Model, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	var r0 domain.Model
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.BooleanRequest) domain.Model); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(domain.Model)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.BooleanRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, id, userID
 func (_m *ModelService) Delete(ctx context.Context, id int64, userID int64) error {
 	ret := _m.Called(ctx, id, userID)
@@ -53,6 +95,27 @@ func (_m *ModelService) GetAllUserModels(ctx context.Context, userID int64) ([]d
 	return r0, r1
 }
 
+// GetAnalysis provides a mock function with given fields: ctx, id, userID
+func (_m *ModelService) GetAnalysis(ctx context.Context, id int64, userID int64) (domain.MeshAnalysis, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 domain.MeshAnalysis
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.MeshAnalysis); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Get(0).(domain.MeshAnalysis)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id, userID
 func (_m *ModelService) GetByID(ctx context.Context, id int64, userID int64) (domain.Model, error) {
 	ret := _m.Called(ctx, id, userID)
@@ -95,6 +158,287 @@ func (_m *ModelService) GetByName(ctx context.Context, name string) (domain.Mode
 	return r0, r1
 }
 
+// GetContent provides a mock function with given fields: ctx, id, userID, format, lod
+func (_m *ModelService) GetContent(ctx context.Context, id int64, userID int64, format string, lod string) ([]byte, error) {
+	ret := _m.Called(ctx, id, userID, format, lod)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string, string) []byte); ok {
+		r0 = rf(ctx, id, userID, format, lod)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string, string) error); ok {
+		r1 = rf(ctx, id, userID, format, lod)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDiff provides a mock function with given fields: ctx, id, otherID, userID, align
+func (_m *ModelService) GetDiff(ctx context.Context, id int64, otherID int64, userID int64, align bool) (domain.Diff, error) {
+	ret := _m.Called(ctx, id, otherID, userID, align)
+
+	var r0 domain.Diff
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64, bool) domain.Diff); ok {
+		r0 = rf(ctx, id, otherID, userID, align)
+	} else {
+		r0 = ret.Get(0).(domain.Diff)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64, bool) error); ok {
+		r1 = rf(ctx, id, otherID, userID, align)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDirectDownloadURL provides a mock function with given fields: ctx, id, userID
+func (_m *ModelService) GetDirectDownloadURL(ctx context.Context, id int64, userID int64) (string, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) string); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSlices provides a mock function with given fields: ctx, id, userID, z, layerHeight
+func (_m *ModelService) GetSlices(ctx context.Context, id int64, userID int64, z float64, layerHeight float64) ([]domain.Layer, error) {
+	ret := _m.Called(ctx, id, userID, z, layerHeight)
+
+	var r0 []domain.Layer
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, float64, float64) []domain.Layer); ok {
+		r0 = rf(ctx, id, userID, z, layerHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Layer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, float64, float64) error); ok {
+		r1 = rf(ctx, id, userID, z, layerHeight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSupportAnalysis provides a mock function with given fields: ctx, id, userID, direction, overhangAngle
+func (_m *ModelService) GetSupportAnalysis(ctx context.Context, id int64, userID int64, direction domain.Point, overhangAngle float64) (domain.SupportAnalysis, error) {
+	ret := _m.Called(ctx, id, userID, direction, overhangAngle)
+
+	var r0 domain.SupportAnalysis
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, domain.Point, float64) domain.SupportAnalysis); ok {
+		r0 = rf(ctx, id, userID, direction, overhangAngle)
+	} else {
+		r0 = ret.Get(0).(domain.SupportAnalysis)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, domain.Point, float64) error); ok {
+		r1 = rf(ctx, id, userID, direction, overhangAngle)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetThicknessAnalysis provides a mock function with given fields: ctx, id, userID, minThickness, process
+func (_m *ModelService) GetThicknessAnalysis(ctx context.Context, id int64, userID int64, minThickness float64, process string) (domain.ThicknessAnalysis, error) {
+	ret := _m.Called(ctx, id, userID, minThickness, process)
+
+	var r0 domain.ThicknessAnalysis
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, float64, string) domain.ThicknessAnalysis); ok {
+		r0 = rf(ctx, id, userID, minThickness, process)
+	} else {
+		r0 = ret.Get(0).(domain.ThicknessAnalysis)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, float64, string) error); ok {
+		r1 = rf(ctx, id, userID, minThickness, process)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetThumbnail provides a mock function with given fields: ctx, id, userID, size
+func (_m *ModelService) GetThumbnail(ctx context.Context, id int64, userID int64, size int) ([]byte, error) {
+	ret := _m.Called(ctx, id, userID, size)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int) []byte); ok {
+		r0 = rf(ctx, id, userID, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int) error); ok {
+		r1 = rf(ctx, id, userID, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Nest provides a mock function with given fields: ctx, userID, req
+func (_m *ModelService) Nest(ctx context.Context, userID int64, req domain.NestRequest) (domain.Nest, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	var r0 domain.Nest
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.NestRequest) domain.Nest); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(domain.Nest)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.NestRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Orient provides a mock function with given fields: ctx, id, userID, req
+func (_m *ModelService) Orient(ctx context.Context, id int64, userID int64, req domain.OrientRequest) (domain.OrientResult, error) {
+	ret := _m.Called(ctx, id, userID, req)
+
+	var r0 domain.OrientResult
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, domain.OrientRequest) domain.OrientResult); ok {
+		r0 = rf(ctx, id, userID, req)
+	} else {
+		r0 = ret.Get(0).(domain.OrientResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, domain.OrientRequest) error); ok {
+		r1 = rf(ctx, id, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repair provides a mock function with given fields: ctx, id, userID
+func (_m *ModelService) Repair(ctx context.Context, id int64, userID int64) (domain.Model, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 domain.Model
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Model); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Model)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Scale provides a mock function with given fields: ctx, id, userID, factor, unit
+func (_m *ModelService) Scale(ctx context.Context, id int64, userID int64, factor float64, unit string) (domain.Model, error) {
+	ret := _m.Called(ctx, id, userID, factor, unit)
+
+	var r0 domain.Model
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, float64, string) domain.Model); ok {
+		r0 = rf(ctx, id, userID, factor, unit)
+	} else {
+		r0 = ret.Get(0).(domain.Model)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, float64, string) error); ok {
+		r1 = rf(ctx, id, userID, factor, unit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Slice provides a mock function with given fields: ctx, id, userID, profile
+func (_m *ModelService) Slice(ctx context.Context, id int64, userID int64, profile domain.PrintProfile) (domain.Model, error) {
+	ret := _m.Called(ctx, id, userID, profile)
+
+	var r0 domain.Model
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, domain.PrintProfile) domain.Model); ok {
+		r0 = rf(ctx, id, userID, profile)
+	} else {
+		r0 = ret.Get(0).(domain.Model)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, domain.PrintProfile) error); ok {
+		r1 = rf(ctx, id, userID, profile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Split provides a mock function with given fields: ctx, id, userID
+func (_m *ModelService) Split(ctx context.Context, id int64, userID int64) ([]domain.Model, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 []domain.Model
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []domain.Model); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Model)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Store provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
 func (_m *ModelService) Store(_a0 context.Context, _a1 *domain.Model, _a2 io.Reader, _a3 string, _a4 int64) error {
 	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
@@ -108,3 +452,24 @@ func (_m *ModelService) Store(_a0 context.Context, _a1 *domain.Model, _a2 io.Rea
 
 	return r0
 }
+
+// Transform provides a mock function with given fields: ctx, id, userID, req
+func (_m *ModelService) Transform(ctx context.Context, id int64, userID int64, req domain.TransformRequest) (domain.Model, error) {
+	ret := _m.Called(ctx, id, userID, req)
+
+	var r0 domain.Model
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, domain.TransformRequest) domain.Model); ok {
+		r0 = rf(ctx, id, userID, req)
+	} else {
+		r0 = ret.Get(0).(domain.Model)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, domain.TransformRequest) error); ok {
+		r1 = rf(ctx, id, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
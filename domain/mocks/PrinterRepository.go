@@ -0,0 +1,87 @@
+// Code generated by mockery v2.3.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/rknizzle/rkmesh/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PrinterRepository is an autogenerated mock type for the PrinterRepository type
+type PrinterRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *PrinterRepository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllUserPrinters provides a mock function with given fields: ctx, userID
+func (_m *PrinterRepository) GetAllUserPrinters(ctx context.Context, userID int64) ([]domain.Printer, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []domain.Printer
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.Printer); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Printer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id, userID
+func (_m *PrinterRepository) GetByID(ctx context.Context, id int64, userID int64) (domain.Printer, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 domain.Printer
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Printer); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Printer)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: ctx, p
+func (_m *PrinterRepository) Store(ctx context.Context, p *domain.Printer) error {
+	ret := _m.Called(ctx, p)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Printer) error); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
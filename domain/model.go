@@ -7,12 +7,54 @@ import (
 )
 
 type Model struct {
-	ID         int64     `json:"id"`
-	Name       string    `json:"name" validate:"required"`
-	UserID     int64     `json:"user_id"`
-	DownloadID string    `json:"download_id"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID          int64  `json:"id"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	Designer    string `json:"designer"`
+	UserID      int64  `json:"user_id"`
+	DownloadID  string `json:"download_id"`
+	Format      string `json:"format"`
+	// Unit is the length of one unit of the model coordinates: mm, cm, m or inch
+	Unit string `json:"unit"`
+	// ParentID is the model that this model was derived from, for example by repairing it
+	ParentID *int64 `json:"parent_id"`
+	// ThumbnailURL is the API path of the rendered preview image. It is not stored with the model.
+	ThumbnailURL string    `json:"thumbnail_url"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// mass properties computed from the mesh when the model is stored
+	Volume        float64     `json:"volume"`
+	SurfaceArea   float64     `json:"surface_area"`
+	BoundingBox   BoundingBox `json:"bounding_box"`
+	TriangleCount int64       `json:"triangle_count"`
+	VertexCount   int64       `json:"vertex_count"`
+	Centroid      Point       `json:"centroid"`
+	// SupportVolume is the estimated volume of support that printing the model as uploaded needs. It
+	// is estimated the first time the model is quoted and is nil until then.
+	SupportVolume *float64 `json:"support_volume"`
+
+	// print estimates computed from the moves of G-code models when the model is stored
+	PrintTime      float64 `json:"print_time"`
+	FilamentLength float64 `json:"filament_length"`
+	FilamentWeight float64 `json:"filament_weight"`
+	LayerCount     int64   `json:"layer_count"`
+}
+
+// GCodeFormat is the format of models that hold the toolpath of a print instead of a mesh
+const GCodeFormat = "gcode"
+
+// Point is a position in the coordinate space of a model
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// BoundingBox is the axis-aligned box that encloses a model
+type BoundingBox struct {
+	Min Point `json:"min"`
+	Max Point `json:"max"`
 }
 
 // ModelService represent the models business logic
@@ -20,6 +62,22 @@ type ModelService interface {
 	GetAllUserModels(ctx context.Context, userID int64) ([]Model, error)
 	GetByID(ctx context.Context, id int64, userID int64) (Model, error)
 	GetDirectDownloadURL(ctx context.Context, id int64, userID int64) (string, error)
+	GetContent(ctx context.Context, id int64, userID int64, format string, lod string) ([]byte, error)
+	GetAnalysis(ctx context.Context, id int64, userID int64) (MeshAnalysis, error)
+	GetSupportAnalysis(ctx context.Context, id int64, userID int64, direction Point, overhangAngle float64) (SupportAnalysis, error)
+	GetThicknessAnalysis(ctx context.Context, id int64, userID int64, minThickness float64, process string) (ThicknessAnalysis, error)
+	GetDiff(ctx context.Context, id int64, otherID int64, userID int64, align bool) (Diff, error)
+	Repair(ctx context.Context, id int64, userID int64) (Model, error)
+	Split(ctx context.Context, id int64, userID int64) ([]Model, error)
+	Scale(ctx context.Context, id int64, userID int64, factor float64, unit string) (Model, error)
+	Transform(ctx context.Context, id int64, userID int64, req TransformRequest) (Model, error)
+	Orient(ctx context.Context, id int64, userID int64, req OrientRequest) (OrientResult, error)
+	Boolean(ctx context.Context, userID int64, req BooleanRequest) (Model, error)
+	ArrangePlate(ctx context.Context, userID int64, req PlateRequest) (Plate, error)
+	Nest(ctx context.Context, userID int64, req NestRequest) (Nest, error)
+	GetThumbnail(ctx context.Context, id int64, userID int64, size int) ([]byte, error)
+	GetSlices(ctx context.Context, id int64, userID int64, z float64, layerHeight float64) ([]Layer, error)
+	Slice(ctx context.Context, id int64, userID int64, profile PrintProfile) (Model, error)
 	GetByName(ctx context.Context, name string) (Model, error)
 	Store(context.Context, *Model, io.Reader, string, int64) error
 	Delete(ctx context.Context, id int64, userID int64) error
@@ -32,4 +90,7 @@ type ModelRepository interface {
 	GetByName(ctx context.Context, name string) (Model, error)
 	Store(ctx context.Context, m *Model) error
 	Delete(ctx context.Context, id int64) error
+	GetAnalysis(ctx context.Context, modelID int64) (MeshAnalysis, error)
+	StoreAnalysis(ctx context.Context, a *MeshAnalysis) error
+	StoreSupportVolume(ctx context.Context, id int64, volume float64) error
 }
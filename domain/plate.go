@@ -0,0 +1,80 @@
+package domain
+
+// PlateItem is a model that is put on a plate a number of times
+type PlateItem struct {
+	ModelID  int64 `json:"model_id" validate:"required"`
+	Quantity int   `json:"quantity" validate:"gte=1"`
+}
+
+// PlateRequest arranges models on the bed of a printer
+type PlateRequest struct {
+	// Name is the name of the stored plate without its extension
+	Name  string      `json:"name"`
+	Items []PlateItem `json:"items" validate:"min=1,dive"`
+	// BedX and BedY are the size of the bed in mm
+	BedX float64 `json:"bed_x" validate:"gt=0"`
+	BedY float64 `json:"bed_y" validate:"gt=0"`
+	// Spacing is the smallest gap in mm between two models, and twice the gap to the edge of the bed
+	Spacing float64 `json:"spacing" validate:"gte=0"`
+	// Rotation is how models may be turned around the vertical axis: none, 90 for steps of 90 degrees
+	// or free. It defaults to 90.
+	Rotation string `json:"rotation" validate:"omitempty,oneof=none 90 free"`
+}
+
+// PlacedModel is a copy of a model on a plate
+type PlacedModel struct {
+	ModelID int64 `json:"model_id"`
+	// Matrix moves the model from its own coordinates onto the plate in mm, in the form that the
+	// transform endpoint accepts
+	Matrix [][]float64 `json:"matrix"`
+	// Angle is the counter-clockwise turn of the model in degrees
+	Angle float64 `json:"angle"`
+}
+
+// Plate is an arrangement of models on a bed that is stored as a 3MF model with a build item per
+// copy
+type Plate struct {
+	Placements []PlacedModel `json:"placements"`
+	// Density is the fraction of the bed that is covered by the footprints of the models
+	Density float64 `json:"density"`
+	Model   Model   `json:"model"`
+}
+
+// NestRequest packs models into the build volume of a powder bed printer
+type NestRequest struct {
+	// Name is the name of the stored build without its extension
+	Name  string      `json:"name"`
+	Items []PlateItem `json:"items" validate:"min=1,dive"`
+	// Shape is box or cylinder and defaults to box
+	Shape string `json:"shape" validate:"omitempty,oneof=box cylinder"`
+	// BuildX, BuildY and BuildZ are the size of the build volume in mm. Cylinders have a diameter of
+	// BuildX and do not use BuildY.
+	BuildX float64 `json:"build_x" validate:"gt=0"`
+	BuildY float64 `json:"build_y" validate:"gte=0"`
+	BuildZ float64 `json:"build_z" validate:"gt=0"`
+	// Spacing is the smallest gap in mm between two models, and twice the gap to the walls
+	Spacing float64 `json:"spacing" validate:"gte=0"`
+	// KeepOrientation keeps models in the orientation that they are in instead of turning them onto
+	// any of their sides
+	KeepOrientation bool `json:"keep_orientation"`
+}
+
+// NestedModel is a copy of a model in a build volume
+type NestedModel struct {
+	ModelID int64 `json:"model_id"`
+	// Matrix moves the model from its own coordinates into the build volume in mm, in the form that
+	// the transform endpoint accepts
+	Matrix [][]float64 `json:"matrix"`
+}
+
+// Nest is a build of models packed into a build volume that is stored as a 3MF model with a build
+// item per copy
+type Nest struct {
+	Placements []NestedModel `json:"placements"`
+	// Height is the height in mm of the top of the highest model
+	Height float64 `json:"height"`
+	// Density is the fraction of the build volume up to the top of the highest model that the models
+	// fill
+	Density float64 `json:"density"`
+	Model   Model   `json:"model"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+// PrintProfile holds the settings that a model is sliced with for an FDM printer. Lengths are in mm,
+// speeds in mm/s and temperatures in degrees Celsius.
+type PrintProfile struct {
+	LayerHeight      float64 `json:"layer_height" validate:"gt=0"`
+	NozzleDiameter   float64 `json:"nozzle_diameter" validate:"gt=0"`
+	FilamentDiameter float64 `json:"filament_diameter" validate:"gt=0"`
+	Perimeters       int     `json:"perimeters" validate:"gte=0"`
+	// InfillDensity is the fraction of the inside of the model that is filled, from 0 to 1
+	InfillDensity     float64 `json:"infill_density" validate:"gte=0,lte=1"`
+	InfillPattern     string  `json:"infill_pattern" validate:"oneof=rectilinear grid"`
+	TopLayers         int     `json:"top_layers" validate:"gte=0"`
+	BottomLayers      int     `json:"bottom_layers" validate:"gte=0"`
+	NozzleTemperature float64 `json:"nozzle_temperature" validate:"gte=0"`
+	BedTemperature    float64 `json:"bed_temperature" validate:"gte=0"`
+	PrintSpeed        float64 `json:"print_speed" validate:"gt=0"`
+	TravelSpeed       float64 `json:"travel_speed" validate:"gt=0"`
+	RetractionLength  float64 `json:"retraction_length" validate:"gte=0"`
+	RetractionSpeed   float64 `json:"retraction_speed" validate:"gt=0"`
+	// BedCenterX and BedCenterY are where the center of the model is placed on the bed
+	BedCenterX float64 `json:"bed_center_x"`
+	BedCenterY float64 `json:"bed_center_y"`
+}
+
+// DefaultPrintProfile returns the settings for printing PLA with a 0.4mm nozzle
+func DefaultPrintProfile() PrintProfile {
+	return PrintProfile{
+		LayerHeight:       0.2,
+		NozzleDiameter:    0.4,
+		FilamentDiameter:  1.75,
+		Perimeters:        2,
+		InfillDensity:     0.2,
+		InfillPattern:     "rectilinear",
+		TopLayers:         4,
+		BottomLayers:      4,
+		NozzleTemperature: 210,
+		BedTemperature:    60,
+		PrintSpeed:        50,
+		TravelSpeed:       150,
+		RetractionLength:  1,
+		RetractionSpeed:   40,
+		BedCenterX:        100,
+		BedCenterY:        100,
+	}
+}
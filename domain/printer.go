@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Printer is a machine that a user prints models on
+type Printer struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name" validate:"required"`
+	UserID int64  `json:"user_id"`
+	// Technology is the printing process of the machine, like fdm, sla or sls
+	Technology string `json:"technology" validate:"required"`
+	// BuildX, BuildY and BuildZ are the size of the build volume in mm
+	BuildX float64 `json:"build_x" validate:"gt=0"`
+	BuildY float64 `json:"build_y" validate:"gt=0"`
+	BuildZ float64 `json:"build_z" validate:"gt=0"`
+	// NozzleSizes are the diameters in mm of the nozzles that the machine can be fitted with
+	NozzleSizes []float64 `json:"nozzle_sizes" validate:"dive,gt=0"`
+	// MaterialIDs are the materials from the catalog that the machine can print
+	MaterialIDs []int64   `json:"material_ids"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Fit reports whether a model fits into the build volume of a printer
+type Fit struct {
+	ModelID   int64 `json:"model_id"`
+	PrinterID int64 `json:"printer_id"`
+	// Fits is true when the model fits in at least one orientation
+	Fits bool `json:"fits"`
+	// FitsAsIs is true when the model fits in the orientation that it is in
+	FitsAsIs bool `json:"fits_as_is"`
+	// Matrix is the rotation that fits the model with the most room to spare, in the form that the
+	// transform endpoint accepts
+	Matrix [][]float64 `json:"matrix"`
+	// Size is the size of the model in mm once it is rotated
+	Size Point `json:"size"`
+	// Scale is the factor that the model has to be scaled by to fit, or 1 when it fits already
+	Scale float64 `json:"scale"`
+}
+
+// PrinterService represent the printers business logic
+type PrinterService interface {
+	GetAllUserPrinters(ctx context.Context, userID int64) ([]Printer, error)
+	GetByID(ctx context.Context, id int64, userID int64) (Printer, error)
+	Store(ctx context.Context, p *Printer) error
+	Delete(ctx context.Context, id int64, userID int64) error
+	Fit(ctx context.Context, modelID int64, printerID int64, userID int64) (Fit, error)
+}
+
+// PrinterRepository represent the printers repository contract
+type PrinterRepository interface {
+	GetAllUserPrinters(ctx context.Context, userID int64) ([]Printer, error)
+	GetByID(ctx context.Context, id int64, userID int64) (Printer, error)
+	Store(ctx context.Context, p *Printer) error
+	Delete(ctx context.Context, id int64) error
+}
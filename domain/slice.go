@@ -0,0 +1,20 @@
+package domain
+
+// Layer is the cross section of a model with a horizontal plane
+type Layer struct {
+	Z        float64   `json:"z"`
+	Polygons []Polygon `json:"polygons"`
+}
+
+// Polygon is a connected region of a layer. The outline is counter-clockwise and the holes are
+// clockwise when viewed from above. Islands inside of a hole are separate polygons.
+type Polygon struct {
+	Outline []Point2D   `json:"outline"`
+	Holes   [][]Point2D `json:"holes"`
+}
+
+// Point2D is a position in the plane of a layer
+type Point2D struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+// TransformOperation is a single step of a transform. Rotations and mirrors are around an axis
+// through the origin, and scales are either uniform by a factor or by x, y and z per axis.
+type TransformOperation struct {
+	Type string `json:"type" validate:"oneof=rotate translate scale mirror"`
+	// Axis is x, y or z for rotations and mirrors
+	Axis string `json:"axis" validate:"omitempty,oneof=x y z"`
+	// Angle is the counter-clockwise rotation in degrees
+	Angle  float64 `json:"angle"`
+	Factor float64 `json:"factor"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Z      float64 `json:"z"`
+}
+
+// TransformRequest is either a list of operations that are applied in order, or a 4x4 affine matrix
+// in row-major order that is applied to column vectors
+type TransformRequest struct {
+	Operations []TransformOperation `json:"operations" validate:"dive"`
+	Matrix     [][]float64          `json:"matrix"`
+	// DropToPlate moves the result so that its lowest point lies on the build plate at Z=0
+	DropToPlate bool `json:"drop_to_plate"`
+}
+
+// OrientRequest configures the search for the best orientation to print a model in
+type OrientRequest struct {
+	// OverhangAngle is the steepest angle in degrees from vertical that prints without support
+	OverhangAngle float64 `json:"overhang_angle" validate:"gt=0,lt=90"`
+	// Store saves a copy of the model in the best orientation
+	Store bool `json:"store"`
+}
+
+// Orientation is a way of placing a model on the build plate, and how well it prints that way
+type Orientation struct {
+	// Down is the direction in the coordinates of the model that faces the build plate
+	Down Point `json:"down"`
+	// Matrix is the rotation that places the model, in the form that the transform endpoint accepts
+	Matrix        [][]float64 `json:"matrix"`
+	OverhangArea  float64     `json:"overhang_area"`
+	SupportVolume float64     `json:"support_volume"`
+	ContactArea   float64     `json:"contact_area"`
+	BuildHeight   float64     `json:"build_height"`
+	// Score ranks the orientation against the other candidates. Lower is better.
+	Score float64 `json:"score"`
+}
+
+// OrientResult holds the best orientations of a model from best to worst
+type OrientResult struct {
+	Candidates []Orientation `json:"candidates"`
+	// Model is the copy of the model in the best orientation when it was stored
+	Model *Model `json:"model,omitempty"`
+}
+
+// BooleanRequest combines the solids of two or more models. The models are combined in order, so a
+// difference subtracts every other model from the first.
+type BooleanRequest struct {
+	ModelIDs  []int64 `json:"model_ids" validate:"min=2"`
+	Operation string  `json:"operation" validate:"oneof=union difference intersection"`
+}
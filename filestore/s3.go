@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -29,16 +30,36 @@ func (s *s3Filestore) Upload(ctx context.Context, file io.Reader, filename strin
 	u := uuid.NewV4()
 
 	key := filename + "-" + u.String()
+	err := s.UploadWithID(ctx, file, key)
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (s *s3Filestore) UploadWithID(ctx context.Context, file io.Reader, id string) error {
 	_, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
 		Bucket: aws.String(s.bucket),
 		ACL:    aws.String("public-read"),
-		Key:    aws.String(key),
+		Key:    aws.String(id),
 		Body:   file,
 	})
+	return err
+}
+
+func (s *s3Filestore) Download(ctx context.Context, id string) (io.ReadCloser, error) {
+	out, err := s.svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(id),
+	})
 	if err != nil {
-		return "", err
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
 	}
-	return key, nil
+
+	return out.Body, nil
 }
 
 func (s *s3Filestore) GetDirectDownloadURL(id string) (string, error) {
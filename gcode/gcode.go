@@ -0,0 +1,441 @@
+// Package gcode reads the G-code that FDM printers run and estimates how long a print takes and how
+// much filament it uses
+package gcode
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// ErrNoMoves will throw if a file does not contain any moves, which means it is not G-code
+var ErrNoMoves = errors.New("File does not contain any G-code moves")
+
+const (
+	// arcSegmentLength is the length in mm of the straight moves that arcs are split into
+	arcSegmentLength = 0.5
+	// maxArcSegments limits how many moves a single arc is split into
+	maxArcSegments = 720
+	mmPerInch      = 25.4
+)
+
+// Options describes the printer and filament that a file is analyzed for
+type Options struct {
+	// Acceleration in mm/s² that is used until the file sets its own with M204
+	Acceleration float64
+	// JunctionDeviation in mm is how far the nozzle may deviate from a corner without slowing down
+	// to a stop
+	JunctionDeviation float64
+	// FilamentDiameter in mm
+	FilamentDiameter float64
+	// FilamentDensity in g/cm³
+	FilamentDensity float64
+}
+
+// DefaultOptions are the settings of a typical printer with 1.75mm PLA
+var DefaultOptions = Options{
+	Acceleration:      1000,
+	JunctionDeviation: 0.013,
+	FilamentDiameter:  1.75,
+	FilamentDensity:   1.24,
+}
+
+// Analysis is the result of running a G-code file through a model of the printer
+type Analysis struct {
+	// PrintTime is the estimated time in seconds that the print takes
+	PrintTime float64
+	// FilamentLength is the length in mm of the filament that is used
+	FilamentLength float64
+	// FilamentWeight is the weight in grams of the filament that is used
+	FilamentWeight float64
+	// Layers is the number of heights that the printer extrudes at
+	Layers int
+	// Bounds encloses every extruding move, or every move if the file does not extrude
+	Bounds mesh.Box
+}
+
+// machine is the state of the printer while a file is executed
+type machine struct {
+	options Options
+	planner planner
+
+	position     mesh.Vector
+	e            float64
+	feedrate     float64
+	accel        float64
+	relative     bool
+	relativeE    bool
+	unitsPerMM   float64
+	filament     float64
+	dwell        float64
+	moves        int
+	lastLayerZ   float64
+	layers       int
+	bounds       mesh.Box
+	hasBounds    bool
+	travelBounds mesh.Box
+	hasTravel    bool
+}
+
+// Analyze executes a G-code file and reports how long it takes to print and how much it uses
+func Analyze(r io.Reader, options Options) (Analysis, error) {
+	m := &machine{
+		options:    options,
+		planner:    planner{junctionDeviation: options.JunctionDeviation},
+		accel:      options.Acceleration,
+		unitsPerMM: 1,
+		lastLayerZ: math.Inf(-1),
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		m.execute(parseLine(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return Analysis{}, err
+	}
+
+	if m.moves == 0 {
+		return Analysis{}, ErrNoMoves
+	}
+
+	bounds := m.bounds
+	if !m.hasBounds {
+		bounds = m.travelBounds
+	}
+
+	radius := options.FilamentDiameter / 2
+	volume := m.filament * math.Pi * radius * radius
+	return Analysis{
+		PrintTime:      m.planner.time() + m.dwell,
+		FilamentLength: m.filament,
+		// the density is per cm³ and the volume is in mm³
+		FilamentWeight: volume * options.FilamentDensity / 1000,
+		Layers:         m.layers,
+		Bounds:         bounds,
+	}, nil
+}
+
+// command is a single line of G-code, like G1 with its parameters X10 Y20 F3000
+type command struct {
+	name   string
+	params map[byte]float64
+}
+
+func (c command) param(name byte) (float64, bool) {
+	v, ok := c.params[name]
+	return v, ok
+}
+
+// parseLine splits a line into its command and parameters. Comments, line numbers and checksums
+// are dropped, and words that are not a letter followed by a number are ignored.
+func parseLine(line string) command {
+	if i := strings.IndexByte(line, ';'); i >= 0 {
+		line = line[:i]
+	}
+	if i := strings.IndexByte(line, '*'); i >= 0 {
+		line = line[:i]
+	}
+	for {
+		start := strings.IndexByte(line, '(')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(line[start:], ')')
+		if end < 0 {
+			line = line[:start]
+			break
+		}
+		line = line[:start] + " " + line[start+end+1:]
+	}
+
+	c := command{params: make(map[byte]float64)}
+	for _, word := range splitWords(line) {
+		letter := byte(unicode.ToUpper(rune(word[0])))
+		if letter == 'N' {
+			continue
+		}
+
+		if c.name == "" && (letter == 'G' || letter == 'M') {
+			// drop leading zeros so that G01 is the same command as G1
+			number := strings.TrimLeft(word[1:], "0")
+			if number == "" || number[0] == '.' {
+				number = "0" + number
+			}
+			c.name = string(letter) + number
+			continue
+		}
+
+		v, err := strconv.ParseFloat(word[1:], 64)
+		if err != nil {
+			continue
+		}
+		c.params[letter] = v
+	}
+	return c
+}
+
+// splitWords splits a line into words that start with a letter. Spaces between words are optional
+// in G-code, so G1X10Y20 is the same as G1 X10 Y20.
+func splitWords(line string) []string {
+	line = strings.Join(strings.Fields(line), "")
+
+	var words []string
+	start := 0
+	for i, r := range line {
+		if i > start && unicode.IsLetter(r) {
+			words = append(words, line[start:i])
+			start = i
+		}
+	}
+	if start < len(line) {
+		words = append(words, line[start:])
+	}
+
+	res := words[:0]
+	for _, w := range words {
+		if len(w) > 1 && unicode.IsLetter(rune(w[0])) {
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+func (m *machine) execute(c command) {
+	switch c.name {
+	case "G0", "G1":
+		m.linear(c)
+	case "G2", "G3":
+		m.arc(c, c.name == "G2")
+	case "G4":
+		if p, ok := c.param('P'); ok {
+			m.dwell += p / 1000
+		}
+		if s, ok := c.param('S'); ok {
+			m.dwell += s
+		}
+	case "G20":
+		m.unitsPerMM = mmPerInch
+	case "G21":
+		m.unitsPerMM = 1
+	case "G28":
+		m.home(c)
+	case "G90":
+		m.relative = false
+		m.relativeE = false
+	case "G91":
+		m.relative = true
+		m.relativeE = true
+	case "G92":
+		m.setPosition(c)
+	case "M82":
+		m.relativeE = false
+	case "M83":
+		m.relativeE = true
+	case "M204":
+		// P is the acceleration of printing moves and S the legacy setting for all moves
+		if p, ok := c.param('P'); ok {
+			m.accel = p
+		} else if s, ok := c.param('S'); ok {
+			m.accel = s
+		}
+	}
+}
+
+// target returns the position that a move ends at and how much it extrudes
+func (m *machine) target(c command) (mesh.Vector, float64) {
+	to := m.position
+	for _, axis := range []struct {
+		name  byte
+		value *float64
+	}{{'X', &to.X}, {'Y', &to.Y}, {'Z', &to.Z}} {
+		v, ok := c.param(axis.name)
+		if !ok {
+			continue
+		}
+		v *= m.unitsPerMM
+		if m.relative {
+			*axis.value += v
+		} else {
+			*axis.value = v
+		}
+	}
+
+	var de float64
+	if e, ok := c.param('E'); ok {
+		e *= m.unitsPerMM
+		if m.relativeE {
+			de = e
+		} else {
+			de = e - m.e
+		}
+	}
+	return to, de
+}
+
+func (m *machine) setFeedrate(c command) {
+	if f, ok := c.param('F'); ok && f > 0 {
+		// feedrates are given per minute
+		m.feedrate = f * m.unitsPerMM / 60
+	}
+}
+
+func (m *machine) linear(c command) {
+	m.setFeedrate(c)
+	to, de := m.target(c)
+	m.moveTo(to, de)
+}
+
+// arc splits a clockwise or counter-clockwise arc in the XY plane into straight moves. The center
+// is either given relative to the start with I and J, or by the radius R.
+func (m *machine) arc(c command, clockwise bool) {
+	m.setFeedrate(c)
+	to, de := m.target(c)
+	from := m.position
+
+	var center mesh.Vector
+	i, hasI := c.param('I')
+	j, hasJ := c.param('J')
+	if r, ok := c.param('R'); ok && !hasI && !hasJ {
+		var valid bool
+		center, valid = arcCenter(from, to, r*m.unitsPerMM, clockwise)
+		if !valid {
+			m.moveTo(to, de)
+			return
+		}
+	} else {
+		center = mesh.Vector{X: from.X + i*m.unitsPerMM, Y: from.Y + j*m.unitsPerMM}
+	}
+
+	radius := math.Hypot(from.X-center.X, from.Y-center.Y)
+	start := math.Atan2(from.Y-center.Y, from.X-center.X)
+	end := math.Atan2(to.Y-center.Y, to.X-center.X)
+	sweep := end - start
+	if clockwise && sweep >= 0 {
+		sweep -= 2 * math.Pi
+	} else if !clockwise && sweep <= 0 {
+		sweep += 2 * math.Pi
+	}
+
+	segments := int(math.Ceil(math.Abs(sweep) * radius / arcSegmentLength))
+	if segments < 1 {
+		segments = 1
+	}
+	if segments > maxArcSegments {
+		segments = maxArcSegments
+	}
+
+	for s := 1; s <= segments; s++ {
+		t := float64(s) / float64(segments)
+		angle := start + sweep*t
+		p := mesh.Vector{
+			X: center.X + radius*math.Cos(angle),
+			Y: center.Y + radius*math.Sin(angle),
+			Z: from.Z + (to.Z-from.Z)*t,
+		}
+		if s == segments {
+			p = to
+		}
+		m.moveTo(p, de/float64(segments))
+	}
+}
+
+// arcCenter finds the center of an arc with a radius between two points. A negative radius selects
+// the arc that is longer than half a circle.
+func arcCenter(from, to mesh.Vector, r float64, clockwise bool) (mesh.Vector, bool) {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	d := math.Hypot(dx, dy)
+	if d == 0 || d > 2*math.Abs(r) {
+		return mesh.Vector{}, false
+	}
+
+	h := math.Sqrt(r*r - d*d/4)
+	if clockwise != (r < 0) {
+		h = -h
+	}
+	return mesh.Vector{X: from.X + dx/2 - h*dy/d, Y: from.Y + dy/2 + h*dx/d}, true
+}
+
+// moveTo moves the print head in a straight line while extruding de mm of filament
+func (m *machine) moveTo(to mesh.Vector, de float64) {
+	delta := to.Sub(m.position)
+	length := delta.Length()
+	extrudes := de > 0 && length > 0
+
+	m.e += de
+	m.filament += de
+	m.moves++
+
+	mv := move{speed: m.feedrate, accel: m.accel, length: length}
+	if length > 0 {
+		d := delta.DivScalar(length)
+		mv.direction = [3]float64{d.X, d.Y, d.Z}
+	} else {
+		// moves of only the extruder, like retractions, are timed by the length of filament
+		mv.length = math.Abs(de)
+	}
+	m.planner.add(mv)
+
+	if extrudes {
+		if to.Z > m.lastLayerZ {
+			m.lastLayerZ = to.Z
+			m.layers++
+		}
+		m.include(&m.bounds, &m.hasBounds, m.position, to)
+	} else {
+		m.include(&m.travelBounds, &m.hasTravel, m.position, to)
+	}
+
+	m.position = to
+}
+
+func (m *machine) include(b *mesh.Box, initialized *bool, points ...mesh.Vector) {
+	for _, p := range points {
+		if !*initialized {
+			*b = mesh.Box{Min: p, Max: p}
+			*initialized = true
+		}
+		b.Min = b.Min.Min(p)
+		b.Max = b.Max.Max(p)
+	}
+}
+
+// home moves the given axes, or all of them, to zero
+func (m *machine) home(c command) {
+	_, x := c.param('X')
+	_, y := c.param('Y')
+	_, z := c.param('Z')
+	all := !x && !y && !z
+	if all || x {
+		m.position.X = 0
+	}
+	if all || y {
+		m.position.Y = 0
+	}
+	if all || z {
+		m.position.Z = 0
+	}
+}
+
+// setPosition changes the current position of the given axes without moving
+func (m *machine) setPosition(c command) {
+	if v, ok := c.param('X'); ok {
+		m.position.X = v * m.unitsPerMM
+	}
+	if v, ok := c.param('Y'); ok {
+		m.position.Y = v * m.unitsPerMM
+	}
+	if v, ok := c.param('Z'); ok {
+		m.position.Z = v * m.unitsPerMM
+	}
+	if v, ok := c.param('E'); ok {
+		m.e = v * m.unitsPerMM
+	}
+}
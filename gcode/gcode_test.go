@@ -0,0 +1,120 @@
+package gcode_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rknizzle/rkmesh/gcode"
+	"github.com/rknizzle/rkmesh/mesh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// square prints two layers of a 10mm square at 60mm/s
+const square = `; a small test print
+G28 ; home
+G90
+M82
+G92 E0
+G1 Z0.2 F3000
+G0 X0 Y0 F6000
+G1 X10 Y0 E1 F3600
+G1 X10 Y10 E2
+G1 X0 Y10 E3
+G1 X0 Y0 E4
+G1 E2 F2400 ; retract
+G1 Z0.4
+G1 E4
+N10 G1 X10 Y0 E5*52
+G1 X10 Y10 E6
+G1 X0 Y10 E7 (a comment)
+G1X0Y0E8
+`
+
+func TestAnalyze(t *testing.T) {
+	a, err := gcode.Analyze(strings.NewReader(square), gcode.DefaultOptions)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, a.Layers)
+	assert.InDelta(t, 8, a.FilamentLength, 1e-9)
+
+	radius := gcode.DefaultOptions.FilamentDiameter / 2
+	weight := 8 * math.Pi * radius * radius * gcode.DefaultOptions.FilamentDensity / 1000
+	assert.InDelta(t, weight, a.FilamentWeight, 1e-9)
+
+	assert.Equal(t, mesh.Box{Min: mesh.Vector{Z: 0.2}, Max: mesh.Vector{X: 10, Y: 10, Z: 0.4}}, a.Bounds)
+
+	// 80mm of lines at 60mm/s take at least 1.33s, and the corners and retraction slow them down
+	assert.Greater(t, a.PrintTime, 80.0/60)
+	assert.Less(t, a.PrintTime, 80.0/60+2)
+}
+
+func TestAnalyzeAcceleration(t *testing.T) {
+	// a single 100mm line at 100mm/s takes exactly 1s without acceleration, and longer when the
+	// print head has to speed up and slow down
+	line := "G1 X100 F6000\n"
+
+	fast, err := gcode.Analyze(strings.NewReader("M204 S1000000\n"+line), gcode.DefaultOptions)
+	require.NoError(t, err)
+	assert.InDelta(t, 1, fast.PrintTime, 0.01)
+
+	slow, err := gcode.Analyze(strings.NewReader("M204 S1000\n"+line), gcode.DefaultOptions)
+	require.NoError(t, err)
+	// accelerating and decelerating both cover 5mm in 0.1s, leaving 90mm at full speed
+	assert.InDelta(t, 1.1, slow.PrintTime, 1e-9)
+}
+
+func TestAnalyzeRelative(t *testing.T) {
+	g := `G91
+M83
+G1 Z0.2 F1200
+G1 X10 E1
+G1 Y10 E1
+G92 E0
+G1 X-10 E0.5
+`
+	a, err := gcode.Analyze(strings.NewReader(g), gcode.DefaultOptions)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 2.5, a.FilamentLength, 1e-9)
+	assert.Equal(t, 1, a.Layers)
+	assert.Equal(t, mesh.Box{Min: mesh.Vector{Z: 0.2}, Max: mesh.Vector{X: 10, Y: 10, Z: 0.2}}, a.Bounds)
+}
+
+func TestAnalyzeArcs(t *testing.T) {
+	tests := []struct {
+		name string
+		arc  string
+	}{
+		{"center", "G2 X20 Y0 I10 J0 E1"},
+		{"radius", "G2 X20 Y0 R10 E1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := "G1 Z0.2 F6000\nG0 X0 Y0\n" + tt.arc + "\n"
+			a, err := gcode.Analyze(strings.NewReader(g), gcode.DefaultOptions)
+			require.NoError(t, err)
+
+			// a clockwise half circle from (0,0) to (20,0) bulges up to y=10
+			assert.InDelta(t, 0, a.Bounds.Min.X, 1e-9)
+			assert.InDelta(t, 20, a.Bounds.Max.X, 1e-9)
+			assert.InDelta(t, 10, a.Bounds.Max.Y, 0.01)
+			assert.InDelta(t, 0, a.Bounds.Min.Y, 1e-9)
+		})
+	}
+}
+
+func TestAnalyzeInches(t *testing.T) {
+	a, err := gcode.Analyze(strings.NewReader("G20\nG1 Z0.01 F60\nG1 X1 E0.1\n"), gcode.DefaultOptions)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 25.4, a.Bounds.Max.X, 1e-9)
+	assert.InDelta(t, 2.54, a.FilamentLength, 1e-9)
+}
+
+func TestAnalyzeNoMoves(t *testing.T) {
+	_, err := gcode.Analyze(strings.NewReader("solid cube\nendsolid cube\n"), gcode.DefaultOptions)
+	assert.Equal(t, gcode.ErrNoMoves, err)
+}
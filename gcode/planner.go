@@ -0,0 +1,109 @@
+package gcode
+
+import "math"
+
+// move is a straight line that the print head travels at a constant nominal speed
+type move struct {
+	length float64
+	// direction is the unit vector of the move, which is zero for moves of only the extruder
+	direction [3]float64
+	speed     float64
+	accel     float64
+	// entry and exit are the planned speeds at the start and end of the move
+	entry, exit float64
+}
+
+// planner estimates how long a list of moves takes the way firmware executes them. Every move
+// accelerates from its entry speed towards its nominal speed and decelerates to its exit speed,
+// and the speed at the corner between two moves is limited by the junction deviation.
+type planner struct {
+	junctionDeviation float64
+	moves             []move
+}
+
+func (p *planner) add(m move) {
+	if m.length <= 0 || m.speed <= 0 {
+		return
+	}
+	p.moves = append(p.moves, m)
+}
+
+// time plans the speeds at the junctions between the moves and returns the total time in seconds
+func (p *planner) time() float64 {
+	moves := p.moves
+	for i := range moves {
+		moves[i].entry, moves[i].exit = 0, 0
+	}
+
+	// the fastest each junction can be passed without exceeding the deviation from the corner
+	for i := 1; i < len(moves); i++ {
+		limit := p.junctionSpeed(moves[i-1], moves[i])
+		moves[i].entry = limit
+		moves[i-1].exit = limit
+	}
+
+	// make sure that every move can slow down enough for the next one...
+	for i := len(moves) - 1; i >= 0; i-- {
+		m := &moves[i]
+		m.entry = math.Min(m.entry, math.Sqrt(m.exit*m.exit+2*m.accel*m.length))
+		if i > 0 {
+			moves[i-1].exit = math.Min(moves[i-1].exit, m.entry)
+		}
+	}
+
+	// ...and speed up enough from the previous one
+	for i := range moves {
+		m := &moves[i]
+		m.exit = math.Min(m.exit, math.Sqrt(m.entry*m.entry+2*m.accel*m.length))
+		if i+1 < len(moves) {
+			moves[i+1].entry = math.Min(moves[i+1].entry, m.exit)
+		}
+	}
+
+	var total float64
+	for _, m := range moves {
+		total += trapezoidTime(m.length, m.entry, m.speed, m.exit, m.accel)
+	}
+	return total
+}
+
+// junctionSpeed returns the highest speed at which the corner between two moves can be passed,
+// using the junction deviation model of Marlin and Grbl
+func (p *planner) junctionSpeed(a, b move) float64 {
+	limit := math.Min(a.speed, b.speed)
+	if a.direction == [3]float64{} || b.direction == [3]float64{} {
+		return 0
+	}
+
+	// the cosine of the angle between the moves, where -1 is a straight line
+	cosTheta := -(a.direction[0]*b.direction[0] + a.direction[1]*b.direction[1] + a.direction[2]*b.direction[2])
+	if cosTheta < -0.999999 {
+		return limit
+	}
+	if cosTheta > 0.999999 {
+		return 0
+	}
+
+	sinHalfTheta := math.Sqrt(0.5 * (1 - cosTheta))
+	accel := math.Min(a.accel, b.accel)
+	v := math.Sqrt(accel * p.junctionDeviation * sinHalfTheta / (1 - sinHalfTheta))
+	return math.Min(v, limit)
+}
+
+// trapezoidTime returns how long a move of a length takes that starts at speed v0, cruises at most
+// at speed v and ends at speed v1, accelerating and decelerating at a
+func trapezoidTime(length, v0, v, v1, a float64) float64 {
+	if a <= 0 {
+		return length / v
+	}
+
+	accelDistance := (v*v - v0*v0) / (2 * a)
+	decelDistance := (v*v - v1*v1) / (2 * a)
+	if accelDistance+decelDistance <= length {
+		return (v-v0)/a + (v-v1)/a + (length-accelDistance-decelDistance)/v
+	}
+
+	// the move is too short to reach its nominal speed
+	peak := math.Sqrt((2*a*length + v0*v0 + v1*v1) / 2)
+	return (peak-v0)/a + (peak-v1)/a
+}
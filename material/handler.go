@@ -0,0 +1,136 @@
+package material
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/rknizzle/rkmesh/domain"
+)
+
+type responseError struct {
+	Message string `json:"message"`
+}
+
+type MaterialHandler struct {
+	Service domain.MaterialService
+}
+
+// NewMaterialHandler will initialize the /materials resources endpoints
+func NewMaterialHandler(e *echo.Group, s domain.MaterialService) {
+	handler := &MaterialHandler{
+		Service: s,
+	}
+
+	// /materials...
+	e.GET("", handler.GetAll)
+	e.POST("", handler.Store)
+}
+
+// NewQuoteHandler will initialize the endpoint that quotes models. It is registered on the /models
+// resources.
+func NewQuoteHandler(e *echo.Group, s domain.MaterialService) {
+	handler := &MaterialHandler{
+		Service: s,
+	}
+
+	// /models...
+	e.POST("/:id/quote", handler.Quote)
+}
+
+func (m *MaterialHandler) GetAll(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	list, err := m.Service.GetAll(ctx, userID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+func (m *MaterialHandler) Store(c echo.Context) error {
+	var material domain.Material
+	err := c.Bind(&material)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	err = validator.New().Struct(material)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	// materials that users add are only available to them, the catalog is seeded by the migrations
+	userID := getUserIDFromRequest(c)
+	material.UserID = &userID
+
+	err = m.Service.Store(ctx, &material)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, material)
+}
+
+// Quote prices printing a model in a material from the catalog
+func (m *MaterialHandler) Quote(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	req := domain.QuoteRequest{Quantity: 1}
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	err = validator.New().Struct(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	quote, err := m.Service.Quote(ctx, id, userID, req)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, quote)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func getUserIDFromRequest(c echo.Context) int64 {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	return int64(claims["user_id"].(float64))
+}
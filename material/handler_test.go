@@ -0,0 +1,141 @@
+package material_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/domain/mocks"
+	"github.com/rknizzle/rkmesh/material"
+)
+
+func TestHandlerGetAll(t *testing.T) {
+	var mockUserID int64 = 1
+	mockService := new(mocks.MaterialService)
+	mockService.On("GetAll", mock.Anything, mockUserID).Return([]domain.Material{{ID: 1, Name: "PLA", Process: "fdm"}}, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/materials", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := material.MaterialHandler{
+		Service: mockService,
+	}
+	err = handler.GetAll(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"PLA"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerStore(t *testing.T) {
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"valid", `{"name":"PETG","process":"fdm","density":1.27,"cost_per_kg":30}`, http.StatusCreated},
+		// the material is added for the user that sends it, not for the user in the body
+		{"other-user", `{"user_id":2,"name":"PETG","process":"fdm","density":1.27,"cost_per_kg":30}`, http.StatusCreated},
+		{"missing-density", `{"name":"PETG","process":"fdm","cost_per_kg":30}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MaterialService)
+			mockService.On("Store", mock.Anything, mock.MatchedBy(func(m *domain.Material) bool {
+				return m.UserID != nil && *m.UserID == mockUserID
+			})).Return(nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/materials", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := material.MaterialHandler{
+				Service: mockService,
+			}
+			err = handler.Store(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code == http.StatusCreated {
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestHandlerQuote(t *testing.T) {
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name     string
+		body     string
+		expected domain.QuoteRequest
+		code     int
+	}{
+		{"default-quantity", `{"material_id":2}`, domain.QuoteRequest{MaterialID: 2, Quantity: 1}, http.StatusOK},
+		{"quantity", `{"material_id":2,"quantity":5}`, domain.QuoteRequest{MaterialID: 2, Quantity: 5}, http.StatusOK},
+		{"missing-material", `{"quantity":5}`, domain.QuoteRequest{}, http.StatusBadRequest},
+		{"zero-quantity", `{"material_id":2,"quantity":0}`, domain.QuoteRequest{}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuote := domain.Quote{ModelID: 1, MaterialID: 2, Quantity: tt.expected.Quantity, Total: 12.5}
+			mockService := new(mocks.MaterialService)
+			mockService.On("Quote", mock.Anything, int64(1), mockUserID, tt.expected).Return(mockQuote, nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/models/1/quote", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("models/:id/quote")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := material.MaterialHandler{
+				Service: mockService,
+			}
+			err = handler.Quote(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code == http.StatusOK {
+				assert.Contains(t, rec.Body.String(), `"total":12.5`)
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func mockTokenWithUserID(mockUserID int64) *jwt.Token {
+	// Echo's JWT middleware gives the user_id as a float64 value
+	return &jwt.Token{
+		Claims: jwt.MapClaims{
+			"user_id": float64(mockUserID),
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package material
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rknizzle/rkmesh/domain"
+)
+
+type postgresMaterialRepository struct {
+	Conn *sql.DB
+}
+
+// NewPostgresMaterialRepository will create an object that represent the material.Repository interface
+func NewPostgresMaterialRepository(Conn *sql.DB) domain.MaterialRepository {
+	return &postgresMaterialRepository{Conn}
+}
+
+// gets all rows from the result of a sql query
+func (p *postgresMaterialRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Material, err error) {
+	rows, err := p.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Material, 0)
+	for rows.Next() {
+		t := domain.Material{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.Process,
+			&t.Density,
+			&t.CostPerKg,
+			&t.Colour,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+			&t.UserID,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+// GetAll returns the materials of the catalog and the materials that a user added
+func (p *postgresMaterialRepository) GetAll(ctx context.Context, userID int64) ([]domain.Material, error) {
+	query := `SELECT * FROM materials WHERE user_id IS NULL OR user_id = $1 ORDER BY process, name`
+
+	return p.fetch(ctx, query, userID)
+}
+
+// GetByID returns a material of the catalog or a material that a user added
+func (p *postgresMaterialRepository) GetByID(ctx context.Context, id int64, userID int64) (res domain.Material, err error) {
+	query := `SELECT * FROM materials WHERE id = $1 AND (user_id IS NULL OR user_id = $2)`
+
+	list, err := p.fetch(ctx, query, id, userID)
+	if err != nil {
+		return domain.Material{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return domain.Material{}, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (p *postgresMaterialRepository) Store(ctx context.Context, m *domain.Material) (err error) {
+	query := `INSERT INTO materials (name, process, density, cost_per_kg, colour, user_id, updated_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id`
+	stmt, err := p.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	var ID int64
+	err = stmt.QueryRowContext(ctx, m.Name, m.Process, m.Density, m.CostPerKg, m.Colour, m.UserID).Scan(&ID)
+	if err != nil {
+		return
+	}
+
+	m.ID = ID
+	return
+}
+
+func (p *postgresMaterialRepository) GetMachineRate(ctx context.Context, process string) (res domain.MachineRate, err error) {
+	query := `SELECT * FROM machine_rates WHERE process = $1`
+
+	err = p.Conn.QueryRowContext(ctx, query, process).Scan(
+		&res.Process,
+		&res.HourlyRate,
+		&res.BuildRate,
+		&res.SupportDensity,
+		&res.SetupFee,
+		&res.Markup,
+	)
+	if err == sql.ErrNoRows {
+		return domain.MachineRate{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.MachineRate{}, err
+	}
+
+	return
+}
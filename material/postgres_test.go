@@ -0,0 +1,97 @@
+package material_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/material"
+)
+
+var materialColumns = []string{
+	"id", "name", "process", "density", "cost_per_kg", "colour", "updated_at", "created_at", "user_id",
+}
+
+func TestPostgresGetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows(materialColumns).
+		AddRow(1, "PLA", "fdm", 1.24, 25.0, "white", time.Now(), time.Now(), nil).
+		AddRow(6, "PA11", "sls", 1.03, 90.0, "black", time.Now(), time.Now(), 1)
+	mock.ExpectQuery("SELECT (.+) FROM materials").WithArgs(1).WillReturnRows(rows)
+
+	p := material.NewPostgresMaterialRepository(db)
+
+	list, err := p.GetAll(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "PLA", list[0].Name)
+	assert.Equal(t, 1.24, list[0].Density)
+	assert.Nil(t, list[0].UserID)
+	if assert.NotNil(t, list[1].UserID) {
+		assert.Equal(t, int64(1), *list[1].UserID)
+	}
+}
+
+func TestPostgresGetByIDOfOtherUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	// the material was added by another user, so the query does not find it
+	mock.ExpectQuery("SELECT (.+) FROM materials").WithArgs(6, 2).WillReturnRows(sqlmock.NewRows(materialColumns))
+
+	p := material.NewPostgresMaterialRepository(db)
+
+	_, err = p.GetByID(context.TODO(), 6, 2)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestPostgresStore(t *testing.T) {
+	var userID int64 = 1
+	m := &domain.Material{UserID: &userID, Name: "PETG", Process: "fdm", Density: 1.27, CostPerKg: 30, Colour: "black"}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	prep := mock.ExpectPrepare("INSERT INTO materials")
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(3)
+	prep.ExpectQuery().WithArgs(m.Name, m.Process, m.Density, m.CostPerKg, m.Colour, m.UserID).WillReturnRows(rows)
+
+	p := material.NewPostgresMaterialRepository(db)
+
+	err = p.Store(context.TODO(), m)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), m.ID)
+}
+
+func TestPostgresGetMachineRate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"process", "hourly_rate", "build_rate", "support_density", "setup_fee", "markup"}).
+		AddRow("fdm", 4.0, 12.0, 0.15, 5.0, 0.3)
+	mock.ExpectQuery("SELECT (.+) FROM machine_rates").WithArgs("fdm").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM machine_rates").WithArgs("dmls").WillReturnError(sql.ErrNoRows)
+
+	p := material.NewPostgresMaterialRepository(db)
+
+	rate, err := p.GetMachineRate(context.TODO(), "fdm")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.MachineRate{Process: "fdm", HourlyRate: 4, BuildRate: 12, SupportDensity: 0.15, SetupFee: 5, Markup: 0.3}, rate)
+
+	_, err = p.GetMachineRate(context.TODO(), "dmls")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
@@ -0,0 +1,172 @@
+package material
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/gcode"
+	"github.com/rknizzle/rkmesh/mesh"
+	"github.com/rknizzle/rkmesh/model"
+)
+
+// gcodeProcess is the only process that G-code can be printed with
+const gcodeProcess = "fdm"
+
+type materialService struct {
+	materialRepo   domain.MaterialRepository
+	modelRepo      domain.ModelRepository
+	filestore      domain.Filestore
+	contextTimeout time.Duration
+}
+
+// NewMaterialService will create a new materialService object representation of domain.MaterialService interface
+func NewMaterialService(mr domain.MaterialRepository, m domain.ModelRepository, s domain.Filestore, timeout time.Duration) domain.MaterialService {
+	return &materialService{
+		materialRepo:   mr,
+		modelRepo:      m,
+		filestore:      s,
+		contextTimeout: timeout,
+	}
+}
+
+func (s *materialService) GetAll(c context.Context, userID int64) ([]domain.Material, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	return s.materialRepo.GetAll(ctx, userID)
+}
+
+func (s *materialService) Store(c context.Context, m *domain.Material) error {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	// materials can only be quoted for processes that have machine rates
+	_, err := s.materialRepo.GetMachineRate(ctx, m.Process)
+	if err == domain.ErrNotFound {
+		return domain.ErrBadParamInput
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.materialRepo.Store(ctx, m)
+}
+
+// Quote prices a job of printing a model in a material. Models with a toolpath are priced by the
+// filament and time that the toolpath takes, and meshes by their volume and support estimate.
+func (s *materialService) Quote(c context.Context, modelID int64, userID int64, req domain.QuoteRequest) (domain.Quote, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	model, err := s.modelRepo.GetByID(ctx, modelID, userID)
+	if err != nil {
+		return domain.Quote{}, err
+	}
+
+	material, err := s.materialRepo.GetByID(ctx, req.MaterialID, userID)
+	// the material is part of the request so a missing one is a bad request rather than a missing model
+	if err == domain.ErrNotFound {
+		return domain.Quote{}, domain.ErrBadParamInput
+	}
+	if err != nil {
+		return domain.Quote{}, err
+	}
+
+	if model.Format == domain.GCodeFormat && material.Process != gcodeProcess {
+		return domain.Quote{}, domain.ErrBadParamInput
+	}
+	if model.Format != domain.GCodeFormat && model.Volume <= 0 {
+		return domain.Quote{}, domain.ErrBadParamInput
+	}
+
+	rate, err := s.materialRepo.GetMachineRate(ctx, material.Process)
+	if err != nil {
+		return domain.Quote{}, err
+	}
+
+	if model.Format != domain.GCodeFormat && model.SupportVolume == nil {
+		volume, err := s.estimateSupport(ctx, model)
+		if err != nil {
+			return domain.Quote{}, err
+		}
+		model.SupportVolume = &volume
+	}
+
+	return price(model, material, rate, req.Quantity), nil
+}
+
+// estimateSupport estimates the support volume of a mesh model the first time it is quoted and
+// caches it with the model
+func (s *materialService) estimateSupport(ctx context.Context, m domain.Model) (float64, error) {
+	parsed, err := model.LoadMesh(ctx, s.filestore, m)
+	if err != nil {
+		return 0, err
+	}
+
+	volume, err := parsed.SupportVolume(ctx, mesh.DefaultOverhangAngle)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.modelRepo.StoreSupportVolume(ctx, m.ID, volume)
+	if err != nil {
+		return 0, err
+	}
+
+	return volume, nil
+}
+
+// price breaks the cost of printing a number of copies of a model down into line items
+func price(model domain.Model, material domain.Material, rate domain.MachineRate, quantity int64) domain.Quote {
+	var weight, hours float64
+	if model.Format == domain.GCodeFormat {
+		// the filament weight of G-code is estimated with the density of PLA
+		weight = model.FilamentWeight * material.Density / gcode.DefaultOptions.FilamentDensity
+		hours = model.PrintTime / 3600
+	} else {
+		// densities and build rates are in cm³ and the volumes in the unit of the model
+		scale := 1.0
+		if unit, ok := mesh.ParseUnit(model.Unit); ok {
+			scale = unit.Millimeters()
+		}
+		volume := (model.Volume + *model.SupportVolume*rate.SupportDensity) * scale * scale * scale / 1000
+		weight = volume * material.Density
+		if rate.BuildRate > 0 {
+			hours = volume / rate.BuildRate
+		}
+	}
+
+	n := float64(quantity)
+	lines := []domain.QuoteLine{
+		{Description: "Material", Amount: roundCents(weight / 1000 * material.CostPerKg * n)},
+		{Description: "Machine time", Amount: roundCents(hours * rate.HourlyRate * n)},
+		{Description: "Setup fee", Amount: roundCents(rate.SetupFee)},
+	}
+
+	var subtotal float64
+	for _, l := range lines {
+		subtotal += l.Amount
+	}
+	lines = append(lines, domain.QuoteLine{Description: "Markup", Amount: roundCents(subtotal * rate.Markup)})
+
+	var total float64
+	for _, l := range lines {
+		total += l.Amount
+	}
+
+	return domain.Quote{
+		ModelID:     model.ID,
+		MaterialID:  material.ID,
+		Quantity:    quantity,
+		Weight:      weight,
+		MachineTime: hours,
+		LineItems:   lines,
+		Total:       roundCents(total),
+	}
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
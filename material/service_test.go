@@ -0,0 +1,235 @@
+package material_test
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/domain/mocks"
+	"github.com/rknizzle/rkmesh/material"
+)
+
+var (
+	mockPLA   = domain.Material{ID: 2, Name: "PLA", Process: "fdm", Density: 1.25, CostPerKg: 20}
+	mockResin = domain.Material{ID: 3, Name: "Resin", Process: "sla", Density: 1.1, CostPerKg: 80}
+	mockRate  = domain.MachineRate{
+		Process:        "fdm",
+		HourlyRate:     4,
+		BuildRate:      10,
+		SupportDensity: 0.2,
+		SetupFee:       5,
+		Markup:         0.5,
+	}
+)
+
+// mockOverhang is a tetrahedron with a 10x10mm triangle on top that stands on its bottom corner, so
+// its sloped underside needs support
+const mockOverhang = `solid overhang
+facet normal 0 0 1
+outer loop
+vertex 0 0 2
+vertex 10 0 2
+vertex 0 10 2
+endloop
+endfacet
+facet normal 0 -1 0
+outer loop
+vertex 0 0 2
+vertex 0 0 0
+vertex 10 0 2
+endloop
+endfacet
+facet normal -1 0 0
+outer loop
+vertex 0 0 2
+vertex 0 10 2
+vertex 0 0 0
+endloop
+endfacet
+facet normal 1 1 -5
+outer loop
+vertex 10 0 2
+vertex 0 0 0
+vertex 0 10 2
+endloop
+endfacet
+endsolid overhang
+`
+
+// supportVolume returns a pointer to an estimated support volume
+func supportVolume(v float64) *float64 {
+	return &v
+}
+
+func TestServiceGetAll(t *testing.T) {
+	mockMaterialRepo := new(mocks.MaterialRepository)
+	mockMaterialRepo.On("GetAll", mock.Anything, int64(1)).Return([]domain.Material{mockPLA, mockResin}, nil).Once()
+
+	s := material.NewMaterialService(mockMaterialRepo, new(mocks.ModelRepository), new(mocks.Filestore), time.Second*2)
+
+	list, err := s.GetAll(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	mockMaterialRepo.AssertExpectations(t)
+}
+
+func TestServiceStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		m := mockPLA
+		mockMaterialRepo.On("GetMachineRate", mock.Anything, "fdm").Return(mockRate, nil).Once()
+		mockMaterialRepo.On("Store", mock.Anything, &m).Return(nil).Once()
+
+		s := material.NewMaterialService(mockMaterialRepo, new(mocks.ModelRepository), new(mocks.Filestore), time.Second*2)
+
+		err := s.Store(context.TODO(), &m)
+
+		assert.NoError(t, err)
+		mockMaterialRepo.AssertExpectations(t)
+	})
+	t.Run("unknown-process", func(t *testing.T) {
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		m := domain.Material{Name: "Steel", Process: "dmls", Density: 8, CostPerKg: 400}
+		mockMaterialRepo.On("GetMachineRate", mock.Anything, "dmls").Return(domain.MachineRate{}, domain.ErrNotFound).Once()
+
+		s := material.NewMaterialService(mockMaterialRepo, new(mocks.ModelRepository), new(mocks.Filestore), time.Second*2)
+
+		err := s.Store(context.TODO(), &m)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockMaterialRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestServiceQuote(t *testing.T) {
+	var mockUserID int64 = 1
+
+	t.Run("mesh", func(t *testing.T) {
+		// 10cm³ of part and 20cm³ of support that is filled to 20%
+		mockModel := domain.Model{ID: 1, Format: "stl", Volume: 10000, SupportVolume: supportVolume(20000)}
+		mockModelRepo := new(mocks.ModelRepository)
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockMaterialRepo.On("GetByID", mock.Anything, int64(2), mockUserID).Return(mockPLA, nil).Once()
+		mockMaterialRepo.On("GetMachineRate", mock.Anything, "fdm").Return(mockRate, nil).Once()
+
+		s := material.NewMaterialService(mockMaterialRepo, mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		quote, err := s.Quote(context.TODO(), 1, mockUserID, domain.QuoteRequest{MaterialID: 2, Quantity: 2})
+
+		require.NoError(t, err)
+		// 14cm³ of PLA weighs 17.5g and takes 1.4h to print
+		assert.InDelta(t, 17.5, quote.Weight, 1e-9)
+		assert.InDelta(t, 1.4, quote.MachineTime, 1e-9)
+		assert.Equal(t, []domain.QuoteLine{
+			{Description: "Material", Amount: 0.7},
+			{Description: "Machine time", Amount: 11.2},
+			{Description: "Setup fee", Amount: 5},
+			{Description: "Markup", Amount: 8.45},
+		}, quote.LineItems)
+		assert.InDelta(t, 25.35, quote.Total, 1e-9)
+		assert.Equal(t, int64(2), quote.Quantity)
+	})
+	t.Run("inch-mesh", func(t *testing.T) {
+		// a cubic inch is 16.387cm³
+		mockModel := domain.Model{ID: 1, Format: "stl", Unit: "inch", Volume: 1, SupportVolume: supportVolume(0)}
+		mockModelRepo := new(mocks.ModelRepository)
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockMaterialRepo.On("GetByID", mock.Anything, int64(2), mockUserID).Return(mockPLA, nil).Once()
+		mockMaterialRepo.On("GetMachineRate", mock.Anything, "fdm").Return(mockRate, nil).Once()
+
+		s := material.NewMaterialService(mockMaterialRepo, mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		quote, err := s.Quote(context.TODO(), 1, mockUserID, domain.QuoteRequest{MaterialID: 2, Quantity: 1})
+
+		require.NoError(t, err)
+		assert.InDelta(t, 16.387064*1.25, quote.Weight, 1e-6)
+	})
+	t.Run("estimated-support", func(t *testing.T) {
+		// the support of a model is estimated and cached the first time it is quoted
+		mockModel := domain.Model{ID: 1, Format: "stl", DownloadID: "overhang.stl-xxx", Volume: 100.0 / 3}
+		mockModelRepo := new(mocks.ModelRepository)
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockMaterialRepo.On("GetByID", mock.Anything, int64(2), mockUserID).Return(mockPLA, nil).Once()
+		mockMaterialRepo.On("GetMachineRate", mock.Anything, "fdm").Return(mockRate, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "overhang.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockOverhang)), nil).Once()
+		var estimate float64
+		mockModelRepo.On("StoreSupportVolume", mock.Anything, int64(1), mock.AnythingOfType("float64")).
+			Run(func(args mock.Arguments) { estimate = args.Get(2).(float64) }).Return(nil).Once()
+
+		s := material.NewMaterialService(mockMaterialRepo, mockModelRepo, mockFilestore, time.Second*2)
+
+		quote, err := s.Quote(context.TODO(), 1, mockUserID, domain.QuoteRequest{MaterialID: 2, Quantity: 1})
+
+		require.NoError(t, err)
+		mockModelRepo.AssertExpectations(t)
+		mockFilestore.AssertExpectations(t)
+		// the wedge below the sloped face is filled with support
+		assert.Greater(t, estimate, 0.0)
+		assert.InDelta(t, (100.0/3+estimate*mockRate.SupportDensity)/1000*mockPLA.Density, quote.Weight, 1e-9)
+	})
+	t.Run("gcode", func(t *testing.T) {
+		mockModel := domain.Model{ID: 1, Format: "gcode", PrintTime: 7200, FilamentWeight: 12.4}
+		mockModelRepo := new(mocks.ModelRepository)
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockMaterialRepo.On("GetByID", mock.Anything, int64(2), mockUserID).Return(mockPLA, nil).Once()
+		mockMaterialRepo.On("GetMachineRate", mock.Anything, "fdm").Return(mockRate, nil).Once()
+
+		s := material.NewMaterialService(mockMaterialRepo, mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		quote, err := s.Quote(context.TODO(), 1, mockUserID, domain.QuoteRequest{MaterialID: 2, Quantity: 1})
+
+		require.NoError(t, err)
+		// the filament weight was estimated for a density of 1.24
+		assert.InDelta(t, 12.5, quote.Weight, 1e-9)
+		assert.InDelta(t, 2, quote.MachineTime, 1e-9)
+		assert.Equal(t, 8.0, quote.LineItems[1].Amount)
+	})
+	t.Run("gcode-other-process", func(t *testing.T) {
+		mockModel := domain.Model{ID: 1, Format: "gcode", PrintTime: 7200, FilamentWeight: 12.4}
+		mockModelRepo := new(mocks.ModelRepository)
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockMaterialRepo.On("GetByID", mock.Anything, int64(3), mockUserID).Return(mockResin, nil).Once()
+
+		s := material.NewMaterialService(mockMaterialRepo, mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		_, err := s.Quote(context.TODO(), 1, mockUserID, domain.QuoteRequest{MaterialID: 3, Quantity: 1})
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+	t.Run("unknown-material", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(domain.Model{ID: 1, Volume: 1}, nil).Once()
+		mockMaterialRepo.On("GetByID", mock.Anything, int64(9), mockUserID).Return(domain.Material{}, domain.ErrNotFound).Once()
+
+		s := material.NewMaterialService(mockMaterialRepo, mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		_, err := s.Quote(context.TODO(), 1, mockUserID, domain.QuoteRequest{MaterialID: 9, Quantity: 1})
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+	t.Run("model-not-found", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(domain.Model{}, domain.ErrNotFound).Once()
+
+		s := material.NewMaterialService(new(mocks.MaterialRepository), mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		_, err := s.Quote(context.TODO(), 1, mockUserID, domain.QuoteRequest{MaterialID: 2, Quantity: 1})
+
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,160 @@
+package mesh
+
+import "sort"
+
+// Analysis is a report on the integrity of a mesh, used to decide whether it can be printed
+type Analysis struct {
+	// NonManifoldEdges are edges shared by more than two triangles
+	NonManifoldEdges int
+	// BoundaryEdges are edges used by only one triangle
+	BoundaryEdges int
+	// Holes is the number of separate loops formed by the boundary edges
+	Holes int
+	// InconsistentlyWoundFaces is the number of triangles whose winding disagrees with the rest of
+	// their shell
+	InconsistentlyWoundFaces int
+	// DegenerateTriangles have a repeated corner or no area
+	DegenerateTriangles int
+	// DuplicateTriangles are additional copies of a triangle that uses the same three vertices
+	DuplicateTriangles int
+	Shells             int
+	// Watertight is true if the mesh is closed and every edge is shared by exactly two triangles
+	Watertight bool
+}
+
+// degenerateAreaTolerance is the area, relative to the squared size of the mesh, below which a
+// triangle is considered to have no area
+const degenerateAreaTolerance = 1e-12
+
+// Analyze checks the mesh for defects that prevent it from being printed
+func (m *Mesh) Analyze() Analysis {
+	t := newTopology(m)
+	var a Analysis
+
+	for _, uses := range t.edges {
+		switch {
+		case len(uses) == 1:
+			a.BoundaryEdges++
+		case len(uses) > 2:
+			a.NonManifoldEdges++
+		}
+	}
+
+	a.Holes = countBoundaryLoops(t)
+	a.DegenerateTriangles = m.countDegenerateTriangles(t)
+	a.DuplicateTriangles = countDuplicateTriangles(m, t)
+
+	shells := m.Shells()
+	a.Shells = len(shells)
+	a.InconsistentlyWoundFaces = countInconsistentFaces(t.neighbors(len(m.Triangles)), shells)
+
+	a.Watertight = len(m.Triangles) > 0 && a.BoundaryEdges == 0 && a.NonManifoldEdges == 0
+	return a
+}
+
+func (m *Mesh) countDegenerateTriangles(t *topology) int {
+	size := m.Bounds().Size()
+	tolerance := degenerateAreaTolerance * size.Dot(size)
+
+	count := 0
+	for i := range m.Triangles {
+		if t.isCollapsed(m, i) {
+			count++
+			continue
+		}
+
+		v1, v2, v3 := m.Corners(i)
+		if v2.Sub(v1).Cross(v3.Sub(v1)).Length()/2 <= tolerance {
+			count++
+		}
+	}
+	return count
+}
+
+func countDuplicateTriangles(m *Mesh, t *topology) int {
+	seen := make(map[Triangle]bool)
+	count := 0
+	for i := range m.Triangles {
+		c := t.corners(m, i)
+		sort.Ints(c[:])
+		if seen[c] {
+			count++
+		}
+		seen[c] = true
+	}
+	return count
+}
+
+// countBoundaryLoops counts the connected groups of boundary edges
+func countBoundaryLoops(t *topology) int {
+	adjacent := make(map[int][]int)
+	for e, uses := range t.edges {
+		if len(uses) == 1 {
+			adjacent[e[0]] = append(adjacent[e[0]], e[1])
+			adjacent[e[1]] = append(adjacent[e[1]], e[0])
+		}
+	}
+
+	visited := make(map[int]bool)
+	loops := 0
+	for start := range adjacent {
+		if visited[start] {
+			continue
+		}
+		loops++
+
+		stack := []int{start}
+		visited[start] = true
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, next := range adjacent[v] {
+				if !visited[next] {
+					visited[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+	}
+	return loops
+}
+
+// countInconsistentFaces propagates an orientation across each shell starting from its first
+// triangle. The triangles that would need to be flipped to agree with the seed are counted, and
+// since either orientation could be the intended one the smaller side is reported.
+func countInconsistentFaces(neighbors [][]neighbor, shells [][]int) int {
+	flipped := make([]int, len(neighbors))
+	count := 0
+	for _, shell := range shells {
+		flips, total := 0, 0
+
+		// 0 means unvisited, 1 keeps the orientation and -1 flips it
+		flipped[shell[0]] = 1
+		stack := []int{shell[0]}
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			total++
+			if flipped[i] == -1 {
+				flips++
+			}
+
+			for _, n := range neighbors[i] {
+				if flipped[n.triangle] != 0 {
+					continue
+				}
+				flipped[n.triangle] = flipped[i]
+				if !n.consistent {
+					flipped[n.triangle] = -flipped[i]
+				}
+				stack = append(stack, n.triangle)
+			}
+		}
+
+		if flips > total-flips {
+			flips = total - flips
+		}
+		count += flips
+	}
+	return count
+}
@@ -0,0 +1,106 @@
+package mesh_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// tetrahedron returns a closed, consistently wound tetrahedron offset along the x axis
+func tetrahedron(offset float64) *mesh.Mesh {
+	return &mesh.Mesh{
+		Vertices: []mesh.Vector{
+			{X: offset}, {X: offset + 1}, {X: offset, Y: 1}, {X: offset, Z: 1},
+		},
+		Triangles: []mesh.Triangle{{0, 2, 1}, {0, 1, 3}, {0, 3, 2}, {1, 2, 3}},
+	}
+}
+
+func TestAnalyzeWatertight(t *testing.T) {
+	a := tetrahedron(0).Analyze()
+
+	assert.Equal(t, mesh.Analysis{Shells: 1, Watertight: true}, a)
+}
+
+func TestAnalyzeHole(t *testing.T) {
+	m := tetrahedron(0)
+	m.Triangles = m.Triangles[1:]
+
+	a := m.Analyze()
+
+	assert.False(t, a.Watertight)
+	assert.Equal(t, 3, a.BoundaryEdges)
+	assert.Equal(t, 1, a.Holes)
+}
+
+func TestAnalyzeInconsistentWinding(t *testing.T) {
+	m := tetrahedron(0)
+	m.Triangles[3] = mesh.Triangle{1, 3, 2}
+
+	a := m.Analyze()
+
+	assert.True(t, a.Watertight)
+	assert.Equal(t, 1, a.InconsistentlyWoundFaces)
+}
+
+func TestAnalyzeDuplicateAndDegenerate(t *testing.T) {
+	m := tetrahedron(0)
+	m.Triangles = append(m.Triangles, mesh.Triangle{0, 2, 1}, mesh.Triangle{0, 0, 1})
+	// a zero area sliver between existing vertices
+	m.Vertices = append(m.Vertices, mesh.Vector{X: 0.5})
+	m.Triangles = append(m.Triangles, mesh.Triangle{0, 4, 1})
+
+	a := m.Analyze()
+
+	assert.Equal(t, 1, a.DuplicateTriangles)
+	assert.Equal(t, 2, a.DegenerateTriangles)
+	assert.Equal(t, 3, a.NonManifoldEdges)
+	assert.False(t, a.Watertight)
+}
+
+func TestAnalyzeSplitVertices(t *testing.T) {
+	// the same tetrahedron with a separate copy of each vertex for every triangle
+	closed := tetrahedron(0)
+	m := &mesh.Mesh{}
+	for i := range closed.Triangles {
+		v1, v2, v3 := closed.Corners(i)
+		n := len(m.Vertices)
+		m.Vertices = append(m.Vertices, v1, v2, v3)
+		m.Triangles = append(m.Triangles, mesh.Triangle{n, n + 1, n + 2})
+	}
+
+	assert.Equal(t, mesh.Analysis{Shells: 1, Watertight: true}, m.Analyze())
+}
+
+func TestShells(t *testing.T) {
+	m := tetrahedron(0)
+	m.Append(tetrahedron(5))
+
+	shells := m.Shells()
+
+	assert.Equal(t, [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}}, shells)
+	assert.Equal(t, 2, m.Analyze().Shells)
+}
+
+func TestParts(t *testing.T) {
+	m := tetrahedron(0)
+	m.Append(tetrahedron(5))
+	m.Colors = make([]mesh.Color, len(m.Vertices))
+	for i := range m.Colors {
+		m.Colors[i] = mesh.Color{R: uint8(i), A: 255}
+	}
+
+	parts := m.Parts()
+
+	if assert.Len(t, parts, 2) {
+		for i, p := range parts {
+			assert.Len(t, p.Vertices, 4)
+			assert.Equal(t, tetrahedron(float64(5*i)).Bounds(), p.Bounds())
+			assert.InDelta(t, 1/6.0, p.Properties().Volume, 1e-9)
+			assert.True(t, p.Analyze().Watertight)
+			assert.ElementsMatch(t, m.Colors[4*i:4*i+4], p.Colors)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package mesh
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrDoesNotFit is returned when the parts do not all fit on the bed
+var ErrDoesNotFit = errors.New("Parts do not fit on the bed")
+
+// ErrUnknownRotation is returned for rotation modes other than none, 90 and free
+var ErrUnknownRotation = errors.New("Rotation mode is not supported")
+
+// RotationMode is how parts may be turned around the vertical axis when they are arranged
+type RotationMode string
+
+const (
+	// RotateNone keeps parts in the direction that they are in
+	RotateNone RotationMode = "none"
+	// RotateRightAngles turns parts in steps of 90 degrees
+	RotateRightAngles RotationMode = "90"
+	// RotateFree turns parts to any angle, in steps of arrangeFreeSteps per turn
+	RotateFree RotationMode = "free"
+)
+
+const (
+	// arrangeResolution is the number of cells along the longer side of the bed that footprints are
+	// rasterized into when they are packed
+	arrangeResolution = 256
+	// arrangeFreeSteps is the number of angles per turn that are tried for parts that can rotate
+	// freely
+	arrangeFreeSteps = 36
+)
+
+// Placement is where a part is put on the bed. Its footprint is turned counter-clockwise by Angle
+// in radians around the origin and then moved by Offset.
+type Placement struct {
+	Angle  float64
+	Offset Vector2
+}
+
+// Matrix returns the transform that moves a mesh from its own coordinates onto its placement
+func (p Placement) Matrix() Matrix {
+	return Translation(Vector{X: p.Offset.X, Y: p.Offset.Y}).Mul(Rotation(Vector{Z: 1}, p.Angle))
+}
+
+// Footprint returns the outline of the mesh seen from above as the convex hull of its vertices,
+// counter-clockwise
+func (m *Mesh) Footprint() []Vector2 {
+	points := make([]Vector2, len(m.Vertices))
+	for i, v := range m.Vertices {
+		points[i] = Vector2{X: v.X, Y: v.Y}
+	}
+	return convexHull(points)
+}
+
+// FootprintArea returns the area that a footprint covers
+func FootprintArea(footprint []Vector2) float64 {
+	return math.Abs(polygonArea(footprint))
+}
+
+// convexHull returns the convex hull of points counter-clockwise, using the monotone chain algorithm
+func convexHull(points []Vector2) []Vector2 {
+	sorted := append([]Vector2(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+	if len(sorted) < 3 {
+		return sorted
+	}
+
+	turn := func(o, a, b Vector2) float64 {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+
+	hull := make([]Vector2, 0, 2*len(sorted))
+	// the lower hull from left to right and then the upper hull back
+	for _, p := range sorted {
+		for len(hull) >= 2 && turn(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(sorted) - 2; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && turn(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return hull[:len(hull)-1]
+}
+
+// Arrange packs convex footprints onto a bed that starts at the origin, keeping at least spacing
+// between them and half of it to the edges of the bed. The largest footprints are placed first,
+// each in the rotation and position that keeps it closest to the front left corner. Footprints are
+// rasterized into cells of arrangeResolution per side of the bed, so the spacing is rounded up to
+// whole cells.
+func Arrange(footprints [][]Vector2, bed Vector2, spacing float64, mode RotationMode) ([]Placement, error) {
+	var angles []float64
+	switch mode {
+	case RotateNone:
+		angles = []float64{0}
+	case RotateRightAngles:
+		angles = []float64{0, math.Pi / 2, math.Pi, 3 * math.Pi / 2}
+	case RotateFree:
+		for i := 0; i < arrangeFreeSteps; i++ {
+			angles = append(angles, 2*math.Pi*float64(i)/arrangeFreeSteps)
+		}
+	default:
+		return nil, ErrUnknownRotation
+	}
+
+	cell := math.Max(bed.X, bed.Y) / arrangeResolution
+	grid := newArrangeGrid(int(bed.X/cell), int(bed.Y/cell))
+
+	order := make([]int, len(footprints))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return FootprintArea(footprints[order[i]]) > FootprintArea(footprints[order[j]])
+	})
+
+	placements := make([]Placement, len(footprints))
+	for _, i := range order {
+		found := false
+		var best arrangeMask
+		var bestX, bestY int
+		for _, angle := range angles {
+			mask := newArrangeMask(footprints[i], angle, cell, spacing/2)
+			x, y, ok := grid.find(mask)
+			if !ok {
+				continue
+			}
+
+			// the rotation that reaches the least far from the front left corner wins
+			if !found || y+mask.height < bestY+best.height || (y+mask.height == bestY+best.height && x+mask.width < bestX+best.width) {
+				found, best, bestX, bestY = true, mask, x, y
+			}
+		}
+		if !found {
+			return nil, ErrDoesNotFit
+		}
+
+		grid.fill(best, bestX, bestY)
+		placements[i] = Placement{
+			Angle: best.angle,
+			Offset: Vector2{
+				X: float64(bestX)*cell + spacing/2 - best.min.X,
+				Y: float64(bestY)*cell + spacing/2 - best.min.Y,
+			},
+		}
+	}
+	return placements, nil
+}
+
+// arrangeMask is the cells that a rotated footprint covers, together with the spacing around it.
+// Footprints are convex so every row of cells is a single span.
+type arrangeMask struct {
+	angle float64
+	// min is the corner of the bounds of the rotated footprint, which lies at half the spacing from
+	// the corner of the first cell
+	min           Vector2
+	width, height int
+	// spans are the first and last column that every row covers. Rows that are not covered have a
+	// first column after their last.
+	spans [][2]int
+}
+
+func newArrangeMask(footprint []Vector2, angle, cell, margin float64) arrangeMask {
+	c, s := math.Cos(angle), math.Sin(angle)
+	rotated := make([]Vector2, len(footprint))
+	min, max := Vector2{X: math.Inf(1), Y: math.Inf(1)}, Vector2{X: math.Inf(-1), Y: math.Inf(-1)}
+	for i, p := range footprint {
+		r := Vector2{X: c*p.X - s*p.Y, Y: s*p.X + c*p.Y}
+		rotated[i] = r
+		min = Vector2{X: math.Min(min.X, r.X), Y: math.Min(min.Y, r.Y)}
+		max = Vector2{X: math.Max(max.X, r.X), Y: math.Max(max.Y, r.Y)}
+	}
+	// the footprint is moved so that the corner of its bounds lies at the margin
+	for i := range rotated {
+		rotated[i] = Vector2{X: rotated[i].X - min.X + margin, Y: rotated[i].Y - min.Y + margin}
+	}
+
+	mask := arrangeMask{
+		angle:  angle,
+		min:    min,
+		width:  int(math.Ceil((max.X - min.X + 2*margin) / cell)),
+		height: int(math.Ceil((max.Y - min.Y + 2*margin) / cell)),
+	}
+	if mask.width == 0 {
+		mask.width = 1
+	}
+	if mask.height == 0 {
+		mask.height = 1
+	}
+
+	// a cell is covered when the footprint, grown by the margin, reaches into it
+	for row := 0; row < mask.height; row++ {
+		lo, hi, ok := bandRange(rotated, float64(row)*cell-margin, float64(row+1)*cell+margin)
+		if !ok {
+			mask.spans = append(mask.spans, [2]int{1, 0})
+			continue
+		}
+		first := int(math.Floor((lo - margin) / cell))
+		last := int(math.Ceil((hi+margin)/cell)) - 1
+		if first < 0 {
+			first = 0
+		}
+		if last >= mask.width {
+			last = mask.width - 1
+		}
+		mask.spans = append(mask.spans, [2]int{first, last})
+	}
+	return mask
+}
+
+// bandRange returns the range of x that a convex polygon covers between two heights
+func bandRange(polygon []Vector2, bottom, top float64) (float64, float64, bool) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i, p := range polygon {
+		if p.Y >= bottom && p.Y <= top {
+			lo, hi = math.Min(lo, p.X), math.Max(hi, p.X)
+		}
+		// edges that cross the bottom or the top of the band
+		q := polygon[(i+1)%len(polygon)]
+		for _, y := range [2]float64{bottom, top} {
+			if (p.Y-y)*(q.Y-y) < 0 {
+				x := p.X + (q.X-p.X)*(y-p.Y)/(q.Y-p.Y)
+				lo, hi = math.Min(lo, x), math.Max(hi, x)
+			}
+		}
+	}
+	return lo, hi, lo <= hi
+}
+
+// arrangeGrid is the cells of the bed that are taken by the parts placed so far
+type arrangeGrid struct {
+	columns, rows int
+	// last holds for every cell the column of the closest taken cell at or before it in its row, or
+	// -1 when there is none
+	last [][]int
+}
+
+func newArrangeGrid(columns, rows int) *arrangeGrid {
+	g := &arrangeGrid{columns: columns, rows: rows, last: make([][]int, rows)}
+	for y := range g.last {
+		g.last[y] = make([]int, columns)
+		for x := range g.last[y] {
+			g.last[y][x] = -1
+		}
+	}
+	return g
+}
+
+// find returns the first free position for a mask, scanning rows from the front of the bed and
+// columns from the left
+func (g *arrangeGrid) find(mask arrangeMask) (int, int, bool) {
+	for y := 0; y+mask.height <= g.rows; y++ {
+		for x := 0; x+mask.width <= g.columns; {
+			// the mask jumps past the furthest taken cell that it covers
+			jump := 0
+			for row, span := range mask.spans {
+				if span[0] > span[1] {
+					continue
+				}
+				if taken := g.last[y+row][x+span[1]]; taken >= x+span[0] {
+					if j := taken - (x + span[0]) + 1; j > jump {
+						jump = j
+					}
+				}
+			}
+			if jump == 0 {
+				return x, y, true
+			}
+			x += jump
+		}
+	}
+	return 0, 0, false
+}
+
+// fill takes the cells that a mask covers at a position
+func (g *arrangeGrid) fill(mask arrangeMask, x, y int) {
+	for row, span := range mask.spans {
+		if span[0] > span[1] {
+			continue
+		}
+		line := g.last[y+row]
+		for column := x + span[0]; column <= x+span[1]; column++ {
+			line[column] = column
+		}
+		for column := x + span[1] + 1; column < g.columns && line[column] < x+span[1]; column++ {
+			line[column] = x + span[1]
+		}
+	}
+}
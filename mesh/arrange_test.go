@@ -0,0 +1,96 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestFootprint(t *testing.T) {
+	// the hull of a cylinder seen from above is the polygon around its rim
+	f := cylinder(5, 5, 3, 0, 10, 16).Footprint()
+
+	require.Len(t, f, 16)
+	var area float64
+	for i, p := range f {
+		q := f[(i+1)%len(f)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	// counter-clockwise
+	assert.InDelta(t, 16*0.5*9*math.Sin(2*math.Pi/16), area/2, 1e-9)
+}
+
+// footprintBounds returns the bounds of a footprint once it is placed
+func footprintBounds(footprint []mesh.Vector2, p mesh.Placement) mesh.Box {
+	m := &mesh.Mesh{}
+	for _, v := range footprint {
+		m.Vertices = append(m.Vertices, mesh.Vector{X: v.X, Y: v.Y})
+	}
+	return m.Transform(p.Matrix()).Bounds()
+}
+
+func TestArrange(t *testing.T) {
+	square := cube(10).Footprint()
+	bed := mesh.Vector2{X: 25, Y: 25}
+
+	t.Run("spacing", func(t *testing.T) {
+		footprints := [][]mesh.Vector2{square, square, square, square}
+
+		placements, err := mesh.Arrange(footprints, bed, 2, mesh.RotateNone)
+
+		require.NoError(t, err)
+		require.Len(t, placements, 4)
+		var bounds []mesh.Box
+		for _, p := range placements {
+			b := footprintBounds(square, p)
+			assert.GreaterOrEqual(t, b.Min.X, 1-1e-9)
+			assert.GreaterOrEqual(t, b.Min.Y, 1-1e-9)
+			assert.LessOrEqual(t, b.Max.X, bed.X-1+1e-9)
+			assert.LessOrEqual(t, b.Max.Y, bed.Y-1+1e-9)
+			bounds = append(bounds, b)
+		}
+		for i := range bounds {
+			for j := i + 1; j < len(bounds); j++ {
+				gap := math.Max(
+					math.Max(bounds[j].Min.X-bounds[i].Max.X, bounds[i].Min.X-bounds[j].Max.X),
+					math.Max(bounds[j].Min.Y-bounds[i].Max.Y, bounds[i].Min.Y-bounds[j].Max.Y))
+				assert.GreaterOrEqual(t, gap, 2-1e-9)
+			}
+		}
+	})
+	t.Run("full", func(t *testing.T) {
+		footprints := [][]mesh.Vector2{square, square, square, square, square}
+
+		_, err := mesh.Arrange(footprints, bed, 2, mesh.RotateNone)
+
+		assert.Equal(t, mesh.ErrDoesNotFit, err)
+	})
+	t.Run("rotated", func(t *testing.T) {
+		// a long bar only fits across a narrow bed when it is turned
+		bar := box(mesh.Vector{}, mesh.Vector{X: 30, Y: 5, Z: 5}, false).Footprint()
+		narrow := mesh.Vector2{X: 10, Y: 40}
+
+		_, err := mesh.Arrange([][]mesh.Vector2{bar}, narrow, 1, mesh.RotateNone)
+		assert.Equal(t, mesh.ErrDoesNotFit, err)
+
+		for _, mode := range []mesh.RotationMode{mesh.RotateRightAngles, mesh.RotateFree} {
+			placements, err := mesh.Arrange([][]mesh.Vector2{bar}, narrow, 1, mode)
+
+			require.NoError(t, err)
+			b := footprintBounds(bar, placements[0])
+			assert.InDelta(t, 5, b.Max.X-b.Min.X, 1e-9)
+			assert.InDelta(t, 30, b.Max.Y-b.Min.Y, 1e-9)
+			assert.GreaterOrEqual(t, b.Min.X, 0.5-1e-9)
+			assert.LessOrEqual(t, b.Max.Y, narrow.Y-0.5+1e-9)
+		}
+	})
+	t.Run("unknown-rotation", func(t *testing.T) {
+		_, err := mesh.Arrange([][]mesh.Vector2{square}, bed, 0, "45")
+
+		assert.Equal(t, mesh.ErrUnknownRotation, err)
+	})
+}
@@ -0,0 +1,463 @@
+package mesh
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrUnknownOperation is returned for boolean operations other than union, difference and
+// intersection
+var ErrUnknownOperation = errors.New("Boolean operation is not supported")
+
+// BooleanOperation is a way of combining the solids of two meshes
+type BooleanOperation string
+
+const (
+	// Union keeps everything that is inside either mesh
+	Union BooleanOperation = "union"
+	// Difference keeps what is inside the first mesh but not the second
+	Difference BooleanOperation = "difference"
+	// Intersection keeps what is inside both meshes
+	Intersection BooleanOperation = "intersection"
+)
+
+const (
+	// planeTolerance is the distance, relative to the size of the meshes, within which a point lies
+	// on a plane. Faces that lie on the same plane within the tolerance are treated as coplanar.
+	planeTolerance = 1e-7
+	// maxJunctionPasses is the most times that triangles are split to close the gaps along edges that
+	// end on the middle of another edge
+	maxJunctionPasses = 8
+)
+
+// Boolean combines the solids of two closed meshes that are wound outwards. Only the faces near
+// where the meshes overlap are cut against each other, using a binary space partitioning tree of
+// the faces of the other mesh so that no piece crosses the surface of the other mesh. Every piece is
+// then kept or dropped depending on whether it is inside, outside or on the surface of the other
+// mesh, and the pieces are welded back into a closed mesh.
+func Boolean(op BooleanOperation, a, b *Mesh) (*Mesh, error) {
+	if op != Union && op != Difference && op != Intersection {
+		return nil, ErrUnknownOperation
+	}
+
+	ba, bb := a.Bounds(), b.Bounds()
+	size := ba.Max.Max(bb.Max).Sub(ba.Min.Min(bb.Min))
+	c := &csg{epsilon: planeTolerance * size.Length()}
+
+	// faces outside the box where both meshes overlap can not touch the other mesh
+	margin := Vector{X: c.epsilon, Y: c.epsilon, Z: c.epsilon}
+	overlap := Box{Min: ba.Min.Max(bb.Min).Sub(margin), Max: ba.Max.Min(bb.Max).Add(margin)}
+	nearA, farA := c.polygons(a, overlap)
+	nearB, farB := c.polygons(b, overlap)
+
+	piecesA := c.newNode(nearB).cut(c, nearA)
+	piecesB := c.newNode(nearA).cut(c, nearB)
+	treeA, treeB := newBVH(a), newBVH(b)
+
+	out := newBuilder()
+	if op != Intersection {
+		out.polygons(farA, false)
+	}
+	if op == Union {
+		out.polygons(farB, false)
+	}
+	for _, p := range piecesA {
+		switch c.classify(p, treeB) {
+		case csgOutside:
+			if op != Intersection {
+				out.polygon(p, false)
+			}
+		case csgInside:
+			if op == Intersection {
+				out.polygon(p, false)
+			}
+		case csgSameFacing:
+			// faces that both meshes share are only kept once
+			if op != Difference {
+				out.polygon(p, false)
+			}
+		case csgOppositeFacing:
+			if op == Difference {
+				out.polygon(p, false)
+			}
+		}
+	}
+	for _, p := range piecesB {
+		switch c.classify(p, treeA) {
+		case csgOutside:
+			if op == Union {
+				out.polygon(p, false)
+			}
+		case csgInside:
+			if op == Intersection {
+				out.polygon(p, false)
+			}
+			// the inside of the second mesh becomes the wall of the hole that it leaves behind
+			if op == Difference {
+				out.polygon(p, true)
+			}
+		}
+	}
+
+	res := out.mesh.weld()
+	res.removeDegenerateTriangles()
+	res.closeJunctions()
+	res.removeDegenerateTriangles()
+	return res, nil
+}
+
+// polygon adds a convex polygon to the mesh as a fan of triangles, reversing it when flip is set
+func (b *builder) polygon(p csgPolygon, flip bool) {
+	for i := 2; i < len(p.vertices); i++ {
+		if flip {
+			b.triangle(p.vertices[0], p.vertices[i], p.vertices[i-1])
+		} else {
+			b.triangle(p.vertices[0], p.vertices[i-1], p.vertices[i])
+		}
+	}
+}
+
+func (b *builder) polygons(polygons []csgPolygon, flip bool) {
+	for _, p := range polygons {
+		b.polygon(p, flip)
+	}
+}
+
+// csg holds the tolerance that the planes of a boolean operation are compared with
+type csg struct {
+	epsilon float64
+}
+
+type csgPlane struct {
+	normal Vector
+	w      float64
+}
+
+func (p csgPlane) flip() csgPlane {
+	return csgPlane{normal: p.normal.MulScalar(-1), w: -p.w}
+}
+
+type csgPolygon struct {
+	vertices []Vector
+	plane    csgPlane
+}
+
+func (p csgPolygon) flip() csgPolygon {
+	vertices := make([]Vector, len(p.vertices))
+	for i, v := range p.vertices {
+		vertices[len(vertices)-1-i] = v
+	}
+	return csgPolygon{vertices: vertices, plane: p.plane.flip()}
+}
+
+// polygons returns the triangles of a mesh that have an area, split into those that overlap a box
+// and those that do not
+func (c *csg) polygons(m *Mesh, b Box) (near, far []csgPolygon) {
+	for i := range m.Triangles {
+		v1, v2, v3 := m.Corners(i)
+		n := m.Normal(i)
+		if n.Length() == 0 {
+			continue
+		}
+
+		p := csgPolygon{vertices: []Vector{v1, v2, v3}, plane: csgPlane{normal: n, w: n.Dot(v1)}}
+		min, max := v1.Min(v2).Min(v3), v1.Max(v2).Max(v3)
+		if min.X > b.Max.X || min.Y > b.Max.Y || min.Z > b.Max.Z || max.X < b.Min.X || max.Y < b.Min.Y || max.Z < b.Min.Z {
+			far = append(far, p)
+		} else {
+			near = append(near, p)
+		}
+	}
+	return near, far
+}
+
+// classes of a piece relative to the surface of the other mesh
+const (
+	csgOutside = iota
+	csgInside
+	// csgSameFacing and csgOppositeFacing pieces lie on a face of the other mesh that faces the same
+	// or the opposite way
+	csgSameFacing
+	csgOppositeFacing
+)
+
+// inwardProbe is the direction of the rays that decide whether a piece is inside a mesh. It is
+// skewed so that it rarely runs along the faces or edges of a mesh.
+var inwardProbe = Vector{X: 0.5257311, Y: 0.6154122, Z: 0.5872853}.Normalize()
+
+// classify decides whether a piece that does not cross the surface of a mesh is inside, outside or
+// on the surface of it
+func (c *csg) classify(p csgPolygon, tree *bvh) int {
+	var centre Vector
+	for _, v := range p.vertices {
+		centre = centre.Add(v)
+	}
+	centre = centre.DivScalar(float64(len(p.vertices)))
+	n := p.plane.normal
+
+	// a ray from just above the piece back onto it hits the face that the piece lies on
+	offset := 10 * c.epsilon
+	if t, tri, ok := tree.intersect(centre.Add(n.MulScalar(offset)), n.MulScalar(-1), -1); ok && math.Abs(t-offset) <= 2*c.epsilon {
+		facing := tree.mesh.Normal(tri).Dot(n)
+		if facing > 1-planeTolerance {
+			return csgSameFacing
+		}
+		if facing < -1+planeTolerance {
+			return csgOppositeFacing
+		}
+	}
+
+	// a ray from inside of a closed mesh first leaves it through a face that faces along the ray
+	t, tri, ok := tree.intersect(centre, inwardProbe, -1)
+	if ok && t > 0 && tree.mesh.Normal(tri).Dot(inwardProbe) > 0 {
+		return csgInside
+	}
+	return csgOutside
+}
+
+// classes of a point or polygon relative to a plane
+const (
+	csgCoplanar = 0
+	csgFront    = 1
+	csgBack     = 2
+	csgSpanning = 3
+)
+
+// split sorts a polygon into the lists of polygons that are in front of, behind or on the plane,
+// cutting it in two when it crosses the plane. Polygons on the plane are sorted by which way they
+// face.
+func (c *csg) split(pl csgPlane, p csgPolygon, coplanarFront, coplanarBack, fronts, backs *[]csgPolygon) {
+	class := 0
+	classes := make([]int, len(p.vertices))
+	for i, v := range p.vertices {
+		t := pl.normal.Dot(v) - pl.w
+		switch {
+		case t < -c.epsilon:
+			classes[i] = csgBack
+		case t > c.epsilon:
+			classes[i] = csgFront
+		default:
+			classes[i] = csgCoplanar
+		}
+		class |= classes[i]
+	}
+
+	switch class {
+	case csgCoplanar:
+		if pl.normal.Dot(p.plane.normal) > 0 {
+			*coplanarFront = append(*coplanarFront, p)
+		} else {
+			*coplanarBack = append(*coplanarBack, p)
+		}
+	case csgFront:
+		*fronts = append(*fronts, p)
+	case csgBack:
+		*backs = append(*backs, p)
+	case csgSpanning:
+		var f, b []Vector
+		for i, vi := range p.vertices {
+			j := (i + 1) % len(p.vertices)
+			ti, tj := classes[i], classes[j]
+			vj := p.vertices[j]
+			if ti != csgBack {
+				f = append(f, vi)
+			}
+			if ti != csgFront {
+				b = append(b, vi)
+			}
+			if ti|tj == csgSpanning {
+				t := (pl.w - pl.normal.Dot(vi)) / pl.normal.Dot(vj.Sub(vi))
+				v := vi.Add(vj.Sub(vi).MulScalar(t))
+				f = append(f, v)
+				b = append(b, v)
+			}
+		}
+		if len(f) >= 3 {
+			*fronts = append(*fronts, csgPolygon{vertices: f, plane: p.plane})
+		}
+		if len(b) >= 3 {
+			*backs = append(*backs, csgPolygon{vertices: b, plane: p.plane})
+		}
+	}
+}
+
+// node is a node of a binary space partitioning tree. Every node splits space along the plane of
+// its polygons, with the front of the plane facing out of the solid.
+type bspNode struct {
+	plane       *csgPlane
+	front, back *bspNode
+	polygons    []csgPolygon
+}
+
+func (c *csg) newNode(polygons []csgPolygon) *bspNode {
+	n := &bspNode{}
+	n.build(c, polygons)
+	return n
+}
+
+// build adds polygons to the tree, splitting them along the planes of the nodes they pass through
+func (n *bspNode) build(c *csg, polygons []csgPolygon) {
+	if len(polygons) == 0 {
+		return
+	}
+	if n.plane == nil {
+		pl := polygons[0].plane
+		n.plane = &pl
+	}
+
+	var fronts, backs []csgPolygon
+	for _, p := range polygons {
+		c.split(*n.plane, p, &n.polygons, &n.polygons, &fronts, &backs)
+	}
+	if len(fronts) > 0 {
+		if n.front == nil {
+			n.front = &bspNode{}
+		}
+		n.front.build(c, fronts)
+	}
+	if len(backs) > 0 {
+		if n.back == nil {
+			n.back = &bspNode{}
+		}
+		n.back.build(c, backs)
+	}
+}
+
+// cut splits polygons along the planes of the tree until none of them crosses a polygon of the tree
+func (n *bspNode) cut(c *csg, polygons []csgPolygon) []csgPolygon {
+	if n.plane == nil || len(polygons) == 0 {
+		return polygons
+	}
+
+	var coplanar, fronts, backs []csgPolygon
+	for _, p := range polygons {
+		c.split(*n.plane, p, &coplanar, &coplanar, &fronts, &backs)
+	}
+	// slivers that are thinner than the tolerance are on every plane that passes close to them, and
+	// only go down one side of the tree
+	parallel := coplanar[:0]
+	for _, p := range coplanar {
+		if math.Abs(n.plane.normal.Dot(p.plane.normal)) > 1-planeTolerance {
+			parallel = append(parallel, p)
+		} else {
+			fronts = append(fronts, p)
+		}
+	}
+	coplanar = parallel
+	// the walls that bound the faces on the plane can be on either side of it, so polygons on the
+	// plane are cut by both sides of the tree
+	if n.front != nil {
+		fronts = n.front.cut(c, fronts)
+		coplanar = n.front.cut(c, coplanar)
+	}
+	if n.back != nil {
+		backs = n.back.cut(c, backs)
+		coplanar = n.back.cut(c, coplanar)
+	}
+	return append(append(fronts, backs...), coplanar...)
+}
+
+// closeJunctions closes the gaps that are left where the corner of a triangle lies on the middle of
+// an edge of a neighbouring triangle, by splitting that triangle at the corner
+func (m *Mesh) closeJunctions() {
+	tolerance := weldTolerance * m.Bounds().Size().Length()
+
+	for pass := 0; pass < maxJunctionPasses; pass++ {
+		t := newTopology(m)
+
+		// only vertices on open edges can be the corner that an edge has to be split at
+		open := make(map[int]bool)
+		for e, uses := range t.edges {
+			if len(uses) == 1 {
+				open[e[0]] = true
+				open[e[1]] = true
+			}
+		}
+		if len(open) == 0 {
+			return
+		}
+		corners := make([]int, 0, len(open))
+		for v := range open {
+			corners = append(corners, v)
+		}
+		sort.Ints(corners)
+
+		changed := false
+		kept := make([]Triangle, 0, len(m.Triangles))
+		for i, tri := range m.Triangles {
+			if t.isCollapsed(m, i) {
+				kept = append(kept, tri)
+				continue
+			}
+
+			// a triangle is split along at most one edge per pass so that the topology stays valid
+			done := false
+			for j := 0; j < 3 && !done; j++ {
+				a, b, c := tri[j], tri[(j+1)%3], tri[(j+2)%3]
+				if len(t.edges[newEdge(t.vertex[a], t.vertex[b])]) != 1 {
+					continue
+				}
+
+				on := m.verticesOnEdge(corners, t.vertex[a], t.vertex[b], tolerance)
+				if len(on) == 0 {
+					continue
+				}
+
+				// the triangle is fanned from its opposite corner through every vertex on the edge
+				previous := a
+				for _, v := range append(on, b) {
+					kept = append(kept, Triangle{previous, v, c})
+					previous = v
+				}
+				done = true
+			}
+			if done {
+				changed = true
+			} else {
+				kept = append(kept, tri)
+			}
+		}
+		m.Triangles = kept
+		if !changed {
+			return
+		}
+	}
+}
+
+// verticesOnEdge returns the vertices that lie on an edge between its ends, ordered from a to b
+func (m *Mesh) verticesOnEdge(candidates []int, a, b int, tolerance float64) []int {
+	va, vb := m.Vertices[a], m.Vertices[b]
+	d := vb.Sub(va)
+	length := d.Length()
+	if length == 0 {
+		return nil
+	}
+	dir := d.DivScalar(length)
+
+	type hit struct {
+		vertex int
+		t      float64
+	}
+	var hits []hit
+	for _, v := range candidates {
+		if v == a || v == b {
+			continue
+		}
+		p := m.Vertices[v].Sub(va)
+		t := p.Dot(dir)
+		if t <= tolerance || t >= length-tolerance {
+			continue
+		}
+		if p.Sub(dir.MulScalar(t)).Length() <= tolerance {
+			hits = append(hits, hit{v, t})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].t < hits[j].t })
+	res := make([]int, len(hits))
+	for i, h := range hits {
+		res[i] = h.vertex
+	}
+	return res
+}
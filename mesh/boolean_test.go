@@ -0,0 +1,81 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// cylinder returns a closed cylinder around the Z axis through (x, y)
+func cylinder(x, y, radius, bottom, top float64, segments int) *mesh.Mesh {
+	m := &mesh.Mesh{Vertices: []mesh.Vector{{X: x, Y: y, Z: bottom}, {X: x, Y: y, Z: top}}}
+	for i := 0; i < segments; i++ {
+		a := 2 * math.Pi * float64(i) / float64(segments)
+		m.Vertices = append(m.Vertices,
+			mesh.Vector{X: x + radius*math.Cos(a), Y: y + radius*math.Sin(a), Z: bottom},
+			mesh.Vector{X: x + radius*math.Cos(a), Y: y + radius*math.Sin(a), Z: top})
+	}
+	for i := 0; i < segments; i++ {
+		b1, t1 := 2+2*i, 3+2*i
+		b2, t2 := 2+2*((i+1)%segments), 3+2*((i+1)%segments)
+		m.Triangles = append(m.Triangles,
+			mesh.Triangle{0, b2, b1},
+			mesh.Triangle{1, t1, t2},
+			mesh.Triangle{b1, b2, t2},
+			mesh.Triangle{b1, t2, t1})
+	}
+	return m
+}
+
+func TestBoolean(t *testing.T) {
+	a := box(mesh.Vector{}, mesh.Vector{X: 10, Y: 10, Z: 10}, false)
+
+	tests := []struct {
+		name   string
+		b      *mesh.Mesh
+		op     mesh.BooleanOperation
+		volume float64
+	}{
+		{"union", box(mesh.Vector{X: 5, Y: 2, Z: 2}, mesh.Vector{X: 15, Y: 8, Z: 8}, false), mesh.Union, 1180},
+		{"difference", box(mesh.Vector{X: 5, Y: 2, Z: 2}, mesh.Vector{X: 15, Y: 8, Z: 8}, false), mesh.Difference, 820},
+		{"intersection", box(mesh.Vector{X: 5, Y: 2, Z: 2}, mesh.Vector{X: 15, Y: 8, Z: 8}, false), mesh.Intersection, 180},
+		// the boxes share the faces at Y=0, Y=10, Z=0 and Z=10
+		{"coplanar-union", box(mesh.Vector{X: 5}, mesh.Vector{X: 15, Y: 10, Z: 10}, false), mesh.Union, 1500},
+		{"coplanar-difference", box(mesh.Vector{X: 5}, mesh.Vector{X: 15, Y: 10, Z: 10}, false), mesh.Difference, 500},
+		{"coplanar-intersection", box(mesh.Vector{X: 5}, mesh.Vector{X: 15, Y: 10, Z: 10}, false), mesh.Intersection, 500},
+		{"disjoint-union", box(mesh.Vector{X: 20}, mesh.Vector{X: 30, Y: 10, Z: 10}, false), mesh.Union, 2000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := mesh.Boolean(tt.op, a, tt.b)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.volume, res.Properties().Volume, 1e-6)
+			assert.True(t, res.Analyze().Watertight)
+		})
+	}
+}
+
+func TestBooleanMountingHole(t *testing.T) {
+	// a hole through the middle of a plate, with the ends of the cylinder on the faces of the plate
+	plate := box(mesh.Vector{}, mesh.Vector{X: 20, Y: 20, Z: 5}, false)
+	hole := cylinder(10, 10, 3, 0, 5, 32)
+
+	res, err := mesh.Boolean(mesh.Difference, plate, hole)
+
+	require.NoError(t, err)
+	a := res.Analyze()
+	assert.True(t, a.Watertight)
+	assert.Zero(t, a.InconsistentlyWoundFaces)
+	assert.InDelta(t, 20*20*5-hole.Properties().Volume, res.Properties().Volume, 1e-6)
+}
+
+func TestBooleanUnknownOperation(t *testing.T) {
+	_, err := mesh.Boolean("xor", cube(1), cube(1))
+
+	assert.Equal(t, mesh.ErrUnknownOperation, err)
+}
@@ -0,0 +1,286 @@
+package mesh
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// bvhLeafSize is the most triangles that a leaf of a bounding volume hierarchy holds
+	bvhLeafSize = 4
+	// rayEpsilon is the shortest distance along a ray that counts as a hit, so that rays do not hit
+	// the surface that they start on
+	rayEpsilon = 1e-9
+)
+
+// bvh is a bounding volume hierarchy over the triangles of a mesh that speeds up casting rays
+// against it and finding the closest points on it
+type bvh struct {
+	mesh  *Mesh
+	nodes []bvhNode
+	// order holds the indices of the triangles so that the triangles of every node are consecutive
+	order []int
+}
+
+type bvhNode struct {
+	bounds Box
+	// left is the index of the first of the two children of an inner node. The second child follows
+	// it.
+	left int
+	// start and count are the triangles of a leaf in order. Inner nodes have a count of zero.
+	start, count int
+}
+
+func newBVH(m *Mesh) *bvh {
+	b := &bvh{mesh: m, order: make([]int, len(m.Triangles))}
+	centroids := make([]Vector, len(m.Triangles))
+	for i := range m.Triangles {
+		b.order[i] = i
+		v1, v2, v3 := m.Corners(i)
+		centroids[i] = v1.Add(v2).Add(v3).DivScalar(3)
+	}
+
+	b.nodes = append(b.nodes, bvhNode{})
+	if len(m.Triangles) > 0 {
+		b.build(0, 0, len(m.Triangles), centroids)
+	}
+	return b
+}
+
+// build fills in a node for the triangles from start to end in order and splits it in two at the
+// median of the longest side of the box around their centroids
+func (b *bvh) build(node, start, end int, centroids []Vector) {
+	bounds := b.triangleBounds(b.order[start])
+	centroidBounds := Box{Min: centroids[b.order[start]], Max: centroids[b.order[start]]}
+	for _, t := range b.order[start+1 : end] {
+		tb := b.triangleBounds(t)
+		bounds.Min = bounds.Min.Min(tb.Min)
+		bounds.Max = bounds.Max.Max(tb.Max)
+		centroidBounds.Min = centroidBounds.Min.Min(centroids[t])
+		centroidBounds.Max = centroidBounds.Max.Max(centroids[t])
+	}
+	b.nodes[node].bounds = bounds
+
+	if end-start <= bvhLeafSize {
+		b.nodes[node].start, b.nodes[node].count = start, end-start
+		return
+	}
+
+	size := centroidBounds.Size()
+	axis := func(v Vector) float64 { return v.X }
+	if size.Y > size.X && size.Y >= size.Z {
+		axis = func(v Vector) float64 { return v.Y }
+	} else if size.Z > size.X && size.Z > size.Y {
+		axis = func(v Vector) float64 { return v.Z }
+	}
+
+	triangles := b.order[start:end]
+	sort.Slice(triangles, func(i, j int) bool {
+		return axis(centroids[triangles[i]]) < axis(centroids[triangles[j]])
+	})
+
+	left := len(b.nodes)
+	b.nodes = append(b.nodes, bvhNode{}, bvhNode{})
+	b.nodes[node].left = left
+
+	mid := (start + end) / 2
+	b.build(left, start, mid, centroids)
+	b.build(left+1, mid, end, centroids)
+}
+
+func (b *bvh) triangleBounds(t int) Box {
+	v1, v2, v3 := b.mesh.Corners(t)
+	return Box{Min: v1.Min(v2).Min(v3), Max: v1.Max(v2).Max(v3)}
+}
+
+// intersect returns the distance along a ray to the closest triangle that it hits and the index of
+// that triangle. The direction has to be of unit length. The triangle skip is ignored, which keeps
+// rays from hitting the triangle they start on.
+func (b *bvh) intersect(origin, direction Vector, skip int) (float64, int, bool) {
+	if len(b.order) == 0 {
+		return 0, 0, false
+	}
+
+	inverse := Vector{X: 1 / direction.X, Y: 1 / direction.Y, Z: 1 / direction.Z}
+	closest, hit := math.Inf(1), -1
+
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := &b.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+
+		if !rayHitsBox(origin, inverse, n.bounds, closest) {
+			continue
+		}
+
+		if n.count == 0 {
+			stack = append(stack, n.left, n.left+1)
+			continue
+		}
+
+		for _, t := range b.order[n.start : n.start+n.count] {
+			if t == skip {
+				continue
+			}
+			v1, v2, v3 := b.mesh.Corners(t)
+			if d, ok := rayHitsTriangle(origin, direction, v1, v2, v3); ok && d < closest {
+				closest, hit = d, t
+			}
+		}
+	}
+
+	return closest, hit, hit >= 0
+}
+
+// closest returns the point on the mesh that is closest to p and the index of the triangle that it
+// lies on. Nodes are visited nearest first so that most of the tree is never opened.
+func (b *bvh) closest(p Vector) (Vector, int) {
+	if len(b.order) == 0 {
+		return Vector{}, -1
+	}
+
+	var best Vector
+	distance, hit := math.Inf(1), -1
+
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := &b.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+
+		if boxDistance2(p, n.bounds) >= distance {
+			continue
+		}
+
+		if n.count == 0 {
+			// the nearer child is pushed last so that it is visited first
+			if boxDistance2(p, b.nodes[n.left].bounds) < boxDistance2(p, b.nodes[n.left+1].bounds) {
+				stack = append(stack, n.left+1, n.left)
+			} else {
+				stack = append(stack, n.left, n.left+1)
+			}
+			continue
+		}
+
+		for _, t := range b.order[n.start : n.start+n.count] {
+			v1, v2, v3 := b.mesh.Corners(t)
+			q := closestOnTriangle(p, v1, v2, v3)
+			if d := q.Sub(p).Dot(q.Sub(p)); d < distance {
+				best, distance, hit = q, d, t
+			}
+		}
+	}
+
+	return best, hit
+}
+
+// boxDistance2 returns the squared distance from a point to a box, which is zero inside of it
+func boxDistance2(p Vector, b Box) float64 {
+	var d float64
+	for _, axis := range [3][3]float64{{p.X, b.Min.X, b.Max.X}, {p.Y, b.Min.Y, b.Max.Y}, {p.Z, b.Min.Z, b.Max.Z}} {
+		if axis[0] < axis[1] {
+			d += (axis[1] - axis[0]) * (axis[1] - axis[0])
+		} else if axis[0] > axis[2] {
+			d += (axis[0] - axis[2]) * (axis[0] - axis[2])
+		}
+	}
+	return d
+}
+
+// closestOnTriangle returns the point on a triangle that is closest to p, by finding the region of
+// the triangle that p projects onto
+func closestOnTriangle(p, a, b, c Vector) Vector {
+	ab, ac, ap := b.Sub(a), c.Sub(a), p.Sub(a)
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a
+	}
+
+	bp := p.Sub(b)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b
+	}
+
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return a.Add(ab.MulScalar(d1 / (d1 - d3)))
+	}
+
+	cp := p.Sub(c)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c
+	}
+
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return a.Add(ac.MulScalar(d2 / (d2 - d6)))
+	}
+
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return b.Add(c.Sub(b).MulScalar((d4 - d3) / ((d4 - d3) + (d5 - d6))))
+	}
+
+	// p projects onto the inside of the triangle
+	denominator := va + vb + vc
+	if denominator == 0 {
+		return a
+	}
+	return a.Add(ab.MulScalar(vb / denominator)).Add(ac.MulScalar(vc / denominator))
+}
+
+// rayHitsBox reports whether a ray enters a box closer than a distance, using the slab method
+func rayHitsBox(origin, inverse Vector, b Box, max float64) bool {
+	tMin, tMax := 0.0, max
+	for _, axis := range [3][4]float64{
+		{origin.X, inverse.X, b.Min.X, b.Max.X},
+		{origin.Y, inverse.Y, b.Min.Y, b.Max.Y},
+		{origin.Z, inverse.Z, b.Min.Z, b.Max.Z},
+	} {
+		t1 := (axis[2] - axis[0]) * axis[1]
+		t2 := (axis[3] - axis[0]) * axis[1]
+		// rays that run parallel to a slab give NaN when they start on its boundary
+		if math.IsNaN(t1) || math.IsNaN(t2) {
+			continue
+		}
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = math.Max(tMin, t1)
+		tMax = math.Min(tMax, t2)
+		if tMin > tMax {
+			return false
+		}
+	}
+	return true
+}
+
+// rayHitsTriangle returns the distance along a ray to where it crosses a triangle, using the
+// Möller–Trumbore algorithm. Triangles are hit from both sides.
+func rayHitsTriangle(origin, direction, v1, v2, v3 Vector) (float64, bool) {
+	e1, e2 := v2.Sub(v1), v3.Sub(v1)
+	p := direction.Cross(e2)
+	det := e1.Dot(p)
+	if math.Abs(det) < 1e-12 {
+		return 0, false
+	}
+
+	inv := 1 / det
+	s := origin.Sub(v1)
+	u := s.Dot(p) * inv
+	if u < 0 || u > 1 {
+		return 0, false
+	}
+	q := s.Cross(e1)
+	v := direction.Dot(q) * inv
+	if v < 0 || u+v > 1 {
+		return 0, false
+	}
+
+	t := e2.Dot(q) * inv
+	if t <= rayEpsilon {
+		return 0, false
+	}
+	return t, true
+}
@@ -0,0 +1,438 @@
+package mesh
+
+import (
+	"container/heap"
+	"context"
+	"math"
+)
+
+const (
+	// boundaryWeight is how much more it costs to move a vertex away from the border of an open
+	// surface than away from the surface itself, which keeps the outline of open meshes intact
+	boundaryWeight = 1000
+	// singularTolerance is the determinant below which a quadric has no unique optimal position
+	singularTolerance = 1e-12
+	// cornerTolerance is the sine of the angle between two border edges below which their shared
+	// vertex lies on a straight border instead of at a corner of it
+	cornerTolerance = 1e-9
+)
+
+// Decimate returns a simplified copy of the mesh with at most target triangles. Edges are collapsed
+// in the order of the quadric error metric by Garland and Heckbert, so flat regions are simplified
+// before detailed ones. Collapses that would flip a face or make the surface non-manifold are
+// skipped, so the result can have more triangles than the target if no valid collapse is left. The
+// corners of the border of open surfaces never move. Collapses of equal cost are made in the order
+// of their vertices so that the result is the same every time. Decimating a large mesh takes a while,
+// so it stops with the error of the context once the context is done.
+func (m *Mesh) Decimate(ctx context.Context, target int) (*Mesh, error) {
+	d := newDecimator(m)
+	for d.triangleCount > target && d.queue.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		c := heap.Pop(&d.queue).(collapse)
+		if !d.isCurrent(c) {
+			continue
+		}
+		d.collapse(c)
+	}
+	return d.mesh(), nil
+}
+
+// quadric is a symmetric 4x4 matrix stored as its upper triangle. It measures the sum of the squared
+// distances from a point to a set of planes.
+type quadric [10]float64
+
+func planeQuadric(n Vector, d float64, weight float64) quadric {
+	return quadric{
+		n.X * n.X, n.X * n.Y, n.X * n.Z, n.X * d,
+		n.Y * n.Y, n.Y * n.Z, n.Y * d,
+		n.Z * n.Z, n.Z * d,
+		d * d,
+	}.scale(weight)
+}
+
+func (q quadric) add(o quadric) quadric {
+	for i := range q {
+		q[i] += o[i]
+	}
+	return q
+}
+
+func (q quadric) scale(s float64) quadric {
+	for i := range q {
+		q[i] *= s
+	}
+	return q
+}
+
+// error returns the sum of squared distances from v to the planes of the quadric
+func (q quadric) error(v Vector) float64 {
+	return q[0]*v.X*v.X + 2*q[1]*v.X*v.Y + 2*q[2]*v.X*v.Z + 2*q[3]*v.X +
+		q[4]*v.Y*v.Y + 2*q[5]*v.Y*v.Z + 2*q[6]*v.Y +
+		q[7]*v.Z*v.Z + 2*q[8]*v.Z +
+		q[9]
+}
+
+// optimum returns the point with the smallest error, if the quadric has a unique minimum
+func (q quadric) optimum() (Vector, bool) {
+	a, b, c := q[0], q[1], q[2]
+	e, f := q[4], q[5]
+	h := q[7]
+
+	det := a*(e*h-f*f) - b*(b*h-f*c) + c*(b*f-e*c)
+	if math.Abs(det) < singularTolerance {
+		return Vector{}, false
+	}
+
+	// solve the gradient of the error for zero with Cramer's rule
+	x, y, z := -q[3], -q[6], -q[8]
+	return Vector{
+		X: (x*(e*h-f*f) - b*(y*h-f*z) + c*(y*f-e*z)) / det,
+		Y: (a*(y*h-z*f) - x*(b*h-f*c) + c*(b*z-y*c)) / det,
+		Z: (a*(e*z-f*y) - b*(b*z-y*c) + x*(b*f-e*c)) / det,
+	}, true
+}
+
+// collapse is a candidate edge collapse that merges vertex b into vertex a at position
+type collapse struct {
+	a, b     int
+	position Vector
+	cost     float64
+	// versions of a and b when the collapse was computed. The collapse is stale once either vertex
+	// has changed.
+	versionA, versionB int
+}
+
+type collapseQueue []collapse
+
+func (q collapseQueue) Len() int { return len(q) }
+func (q collapseQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	if q[i].a != q[j].a {
+		return q[i].a < q[j].a
+	}
+	return q[i].b < q[j].b
+}
+func (q collapseQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *collapseQueue) Push(x interface{}) { *q = append(*q, x.(collapse)) }
+func (q *collapseQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+type decimator struct {
+	vertices  []Vector
+	colors    []Color
+	triangles []Triangle
+	quadrics  []quadric
+	// faces lists the triangles around each vertex. It can contain removed triangles, which are
+	// skipped.
+	faces   [][]int
+	removed []bool
+	version []int
+	merged  []bool
+	// corner marks the vertices where the border of an open surface turns, which are kept in place
+	corner        []bool
+	triangleCount int
+	queue         collapseQueue
+}
+
+func newDecimator(m *Mesh) *decimator {
+	t := newTopology(m)
+	d := &decimator{
+		vertices: append([]Vector(nil), m.Vertices...),
+		quadrics: make([]quadric, len(m.Vertices)),
+		faces:    make([][]int, len(m.Vertices)),
+		version:  make([]int, len(m.Vertices)),
+		merged:   make([]bool, len(m.Vertices)),
+		corner:   make([]bool, len(m.Vertices)),
+	}
+	if m.HasColors() {
+		d.colors = append([]Color(nil), m.Colors...)
+	}
+
+	// vertices that share a position are merged up front so that split seams are simplified as
+	// one surface
+	for i := range m.Triangles {
+		if t.isCollapsed(m, i) {
+			continue
+		}
+		tri := t.corners(m, i)
+		f := len(d.triangles)
+		d.triangles = append(d.triangles, tri)
+		for _, v := range tri {
+			d.faces[v] = append(d.faces[v], f)
+		}
+	}
+	d.removed = make([]bool, len(d.triangles))
+	d.triangleCount = len(d.triangles)
+
+	// the directions of the border edges at every vertex on the border
+	borders := make(map[int][]Vector)
+	for _, tri := range d.triangles {
+		v1, v2, v3 := d.vertices[tri[0]], d.vertices[tri[1]], d.vertices[tri[2]]
+		n := v2.Sub(v1).Cross(v3.Sub(v1))
+		area := n.Length() / 2
+		if area == 0 {
+			continue
+		}
+		n = n.Normalize()
+		q := planeQuadric(n, -n.Dot(v1), area)
+		for _, v := range tri {
+			d.quadrics[v] = d.quadrics[v].add(q)
+		}
+
+		// constrain the border of open surfaces with a plane through the edge that is perpendicular
+		// to the face
+		for j := 0; j < 3; j++ {
+			a, b := tri[j], tri[(j+1)%3]
+			if len(t.edges[newEdge(a, b)]) != 1 {
+				continue
+			}
+			edge := d.vertices[b].Sub(d.vertices[a])
+			borders[a] = append(borders[a], edge)
+			borders[b] = append(borders[b], edge)
+			normal := edge.Cross(n).Normalize()
+			q := planeQuadric(normal, -normal.Dot(d.vertices[a]), boundaryWeight*edge.Dot(edge))
+			d.quadrics[a] = d.quadrics[a].add(q)
+			d.quadrics[b] = d.quadrics[b].add(q)
+		}
+	}
+
+	// a vertex lies on a straight border when it has two border edges that point the same way
+	for v, edges := range borders {
+		d.corner[v] = len(edges) != 2 ||
+			edges[0].Cross(edges[1]).Length() > cornerTolerance*edges[0].Length()*edges[1].Length()
+	}
+
+	// edges are queued in the order of the triangles instead of the order of a map, so that the
+	// result does not change from run to run
+	queued := make(map[edge]bool)
+	for _, tri := range d.triangles {
+		for j := 0; j < 3; j++ {
+			e := newEdge(tri[j], tri[(j+1)%3])
+			if queued[e] {
+				continue
+			}
+			queued[e] = true
+			if c, ok := d.candidate(e[0], e[1]); ok {
+				d.queue = append(d.queue, c)
+			}
+		}
+	}
+	heap.Init(&d.queue)
+
+	return d
+}
+
+// candidate computes the collapse of the edge between a and b. Edges between two corners can not be
+// collapsed, and edges with one corner are collapsed onto it.
+func (d *decimator) candidate(a, b int) (collapse, bool) {
+	if d.corner[a] && d.corner[b] {
+		return collapse{}, false
+	}
+	if d.corner[b] {
+		a, b = b, a
+	}
+	q := d.quadrics[a].add(d.quadrics[b])
+
+	position, ok := q.optimum()
+	if d.corner[a] {
+		position = d.vertices[a]
+	} else if !ok {
+		// fall back to the best of the end points and the midpoint when the optimum is not unique
+		candidates := []Vector{d.vertices[a], d.vertices[b], d.vertices[a].Add(d.vertices[b]).DivScalar(2)}
+		position = candidates[0]
+		for _, c := range candidates[1:] {
+			if q.error(c) < q.error(position) {
+				position = c
+			}
+		}
+	}
+
+	return collapse{
+		a:        a,
+		b:        b,
+		position: position,
+		cost:     q.error(position),
+		versionA: d.version[a],
+		versionB: d.version[b],
+	}, true
+}
+
+func (d *decimator) isCurrent(c collapse) bool {
+	return !d.merged[c.a] && !d.merged[c.b] && d.version[c.a] == c.versionA && d.version[c.b] == c.versionB
+}
+
+// collapse merges vertex b into vertex a if that keeps the surface valid
+func (d *decimator) collapse(c collapse) {
+	a, b := c.a, c.b
+	if !d.canCollapse(a, b, c.position) {
+		return
+	}
+
+	d.vertices[a] = c.position
+	d.quadrics[a] = d.quadrics[a].add(d.quadrics[b])
+	if d.colors != nil {
+		d.colors[a] = mixColors(d.colors[a], d.colors[b])
+	}
+
+	var faces []int
+	for _, f := range d.liveFaces(a) {
+		if d.hasVertex(f, b) {
+			d.removed[f] = true
+			d.triangleCount--
+			continue
+		}
+		faces = append(faces, f)
+	}
+	for _, f := range d.liveFaces(b) {
+		for j, v := range d.triangles[f] {
+			if v == b {
+				d.triangles[f][j] = a
+			}
+		}
+		faces = append(faces, f)
+	}
+
+	d.faces[a] = faces
+	d.faces[b] = nil
+	d.merged[b] = true
+	// every queued collapse of a is now stale, so the edges around it are queued again
+	d.version[a]++
+	for _, n := range d.neighbors(a) {
+		if c, ok := d.candidate(a, n); ok {
+			heap.Push(&d.queue, c)
+		}
+	}
+}
+
+// canCollapse reports whether merging b into a at position keeps the surface manifold and keeps
+// every remaining face pointing the same way
+func (d *decimator) canCollapse(a, b int, position Vector) bool {
+	// the vertices around both ends of the edge may only be shared by the faces on the edge,
+	// otherwise the collapse would pinch the surface
+	shared := 0
+	for _, f := range d.liveFaces(a) {
+		if d.hasVertex(f, b) {
+			shared++
+		}
+	}
+	if shared == 0 {
+		return false
+	}
+	aNeighbors := d.neighbors(a)
+	common := 0
+	for _, n := range d.neighbors(b) {
+		if containsInt(aNeighbors, n) {
+			common++
+		}
+	}
+	if common != shared {
+		return false
+	}
+
+	for _, v := range []int{a, b} {
+		for _, f := range d.liveFaces(v) {
+			if d.hasVertex(f, a) && d.hasVertex(f, b) {
+				continue
+			}
+
+			tri := d.triangles[f]
+			corners := [3]Vector{d.vertices[tri[0]], d.vertices[tri[1]], d.vertices[tri[2]]}
+			before := corners[1].Sub(corners[0]).Cross(corners[2].Sub(corners[0]))
+			for j := range tri {
+				if tri[j] == v {
+					corners[j] = position
+				}
+			}
+			after := corners[1].Sub(corners[0]).Cross(corners[2].Sub(corners[0]))
+			if after.Length() == 0 || before.Dot(after) <= 0 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (d *decimator) liveFaces(v int) []int {
+	live := d.faces[v][:0]
+	for _, f := range d.faces[v] {
+		if !d.removed[f] {
+			live = append(live, f)
+		}
+	}
+	d.faces[v] = live
+	return live
+}
+
+func (d *decimator) hasVertex(f int, v int) bool {
+	t := d.triangles[f]
+	return t[0] == v || t[1] == v || t[2] == v
+}
+
+// neighbors returns the vertices that share an edge with v
+func (d *decimator) neighbors(v int) []int {
+	// vertices only have a handful of neighbors so a linear search is faster than a set
+	var n []int
+	for _, f := range d.liveFaces(v) {
+		for _, u := range d.triangles[f] {
+			if u != v && !containsInt(n, u) {
+				n = append(n, u)
+			}
+		}
+	}
+	return n
+}
+
+func containsInt(s []int, v int) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// mesh builds the decimated mesh out of the vertices and triangles that are left
+func (d *decimator) mesh() *Mesh {
+	r := &Mesh{}
+	index := make(map[int]int)
+	for f, tri := range d.triangles {
+		if d.removed[f] {
+			continue
+		}
+
+		var t Triangle
+		for j, v := range tri {
+			i, ok := index[v]
+			if !ok {
+				i = len(r.Vertices)
+				index[v] = i
+				r.Vertices = append(r.Vertices, d.vertices[v])
+				if d.colors != nil {
+					r.Colors = append(r.Colors, d.colors[v])
+				}
+			}
+			t[j] = i
+		}
+		r.Triangles = append(r.Triangles, t)
+	}
+	return r
+}
+
+func mixColors(a, b Color) Color {
+	return Color{
+		R: uint8((uint16(a.R) + uint16(b.R)) / 2),
+		G: uint8((uint16(a.G) + uint16(b.G)) / 2),
+		B: uint8((uint16(a.B) + uint16(b.B)) / 2),
+		A: uint8((uint16(a.A) + uint16(b.A)) / 2),
+	}
+}
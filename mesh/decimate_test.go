@@ -0,0 +1,112 @@
+package mesh_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// sphere returns a closed UV sphere with the given number of rings and segments
+func sphere(radius float64, rings, segments int) *mesh.Mesh {
+	m := &mesh.Mesh{}
+	m.Vertices = append(m.Vertices, mesh.Vector{Z: radius})
+	for r := 1; r < rings; r++ {
+		theta := math.Pi * float64(r) / float64(rings)
+		for s := 0; s < segments; s++ {
+			phi := 2 * math.Pi * float64(s) / float64(segments)
+			m.Vertices = append(m.Vertices, mesh.Vector{
+				X: radius * math.Sin(theta) * math.Cos(phi),
+				Y: radius * math.Sin(theta) * math.Sin(phi),
+				Z: radius * math.Cos(theta),
+			})
+		}
+	}
+	bottom := len(m.Vertices)
+	m.Vertices = append(m.Vertices, mesh.Vector{Z: -radius})
+
+	ring := func(r, s int) int { return 1 + (r-1)*segments + s%segments }
+	for s := 0; s < segments; s++ {
+		m.Triangles = append(m.Triangles, mesh.Triangle{0, ring(1, s), ring(1, s+1)})
+		for r := 1; r < rings-1; r++ {
+			m.Triangles = append(m.Triangles,
+				mesh.Triangle{ring(r, s), ring(r+1, s), ring(r+1, s+1)},
+				mesh.Triangle{ring(r, s), ring(r+1, s+1), ring(r, s+1)},
+			)
+		}
+		m.Triangles = append(m.Triangles, mesh.Triangle{bottom, ring(rings-1, s+1), ring(rings-1, s)})
+	}
+	return m
+}
+
+func TestDecimate(t *testing.T) {
+	m := sphere(10, 40, 80)
+	original := m.Properties()
+
+	d, err := m.Decimate(context.Background(), len(m.Triangles)/10)
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, len(d.Triangles), len(m.Triangles)/10)
+	a := d.Analyze()
+	assert.True(t, a.Watertight)
+	assert.Zero(t, a.InconsistentlyWoundFaces)
+	assert.InEpsilon(t, original.Volume, d.Properties().Volume, 0.05)
+}
+
+func TestDecimateFlatSurface(t *testing.T) {
+	// a subdivided square, which only needs two triangles
+	const n = 10
+	m := &mesh.Mesh{}
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			m.Vertices = append(m.Vertices, mesh.Vector{X: float64(x), Y: float64(y)})
+		}
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			i := y*(n+1) + x
+			m.Triangles = append(m.Triangles, mesh.Triangle{i, i + 1, i + n + 2}, mesh.Triangle{i, i + n + 2, i + n + 1})
+		}
+	}
+
+	d, err := m.Decimate(context.Background(), 2)
+	require.NoError(t, err)
+
+	require.Len(t, d.Triangles, 2)
+	assert.InDelta(t, 100, d.Properties().SurfaceArea, 1e-9)
+	assert.Equal(t, mesh.Box{Max: mesh.Vector{X: n, Y: n}}, d.Bounds())
+}
+
+func TestDecimateKeepsSmallMeshes(t *testing.T) {
+	m := cube(1)
+
+	d, err := m.Decimate(context.Background(), 100)
+	require.NoError(t, err)
+
+	assert.Len(t, d.Triangles, len(m.Triangles))
+}
+
+func TestDecimateIsDeterministic(t *testing.T) {
+	m := sphere(10, 20, 40)
+
+	first, err := m.Decimate(context.Background(), len(m.Triangles)/4)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		d, err := m.Decimate(context.Background(), len(m.Triangles)/4)
+		require.NoError(t, err)
+		assert.Equal(t, first, d)
+	}
+}
+
+func TestDecimateCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := sphere(10, 20, 40).Decimate(ctx, 10)
+
+	assert.Equal(t, context.Canceled, err)
+}
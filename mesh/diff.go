@@ -0,0 +1,237 @@
+package mesh
+
+import "math"
+
+const (
+	// deviationSamples is about the number of points of each surface that the distance to the other
+	// surface is measured at. Triangles are sampled at least once, so meshes with more triangles are
+	// sampled more.
+	deviationSamples = 20000
+	// deviationMaxDivisions is the most times that the edges of a triangle are divided to sample it
+	deviationMaxDivisions = 16
+	// alignSamples is the most vertices that are matched to the other mesh while aligning a mesh
+	alignSamples = 1000
+	// alignIterations is the most rounds of matching and moving that aligning a mesh takes
+	alignIterations = 50
+)
+
+// Deviation describes how far the surfaces of two meshes lie apart
+type Deviation struct {
+	// Vertices is the distance from every vertex of the mesh to the surface of the other mesh. It is
+	// positive where the vertex lies outside of the other mesh and negative where it lies inside.
+	Vertices []float64
+	// Max is the largest distance from either surface to the other, the Hausdorff distance
+	Max float64
+	// Mean and RMS are the mean and root mean square of the distances between the surfaces, weighted
+	// by area
+	Mean float64
+	RMS  float64
+}
+
+// Deviation measures the distance between the surface of the mesh and the surface of another mesh
+// in both directions. Both surfaces are sampled evenly at about deviationSamples points, so the
+// distances are exact at the vertices and close to it in between.
+func (m *Mesh) Deviation(other *Mesh) Deviation {
+	res := Deviation{Vertices: make([]float64, len(m.Vertices))}
+	if len(m.Triangles) == 0 || len(other.Triangles) == 0 {
+		return res
+	}
+
+	mine, theirs := newBVH(m), newBVH(other)
+	for i, v := range m.Vertices {
+		q, t := theirs.closest(v)
+		d := v.Sub(q).Length()
+		if other.Normal(t).Dot(v.Sub(q)) < 0 {
+			d = -d
+		}
+		res.Vertices[i] = d
+		res.Max = math.Max(res.Max, math.Abs(d))
+	}
+	for _, v := range other.Vertices {
+		q, _ := mine.closest(v)
+		res.Max = math.Max(res.Max, v.Sub(q).Length())
+	}
+
+	var area, sum, squares float64
+	sample := func(from *Mesh, to *bvh) {
+		// samples lie about as far apart as the corners of squares that divide the surface
+		spacing := math.Sqrt(from.Properties().SurfaceArea / deviationSamples)
+		for i := range from.Triangles {
+			v1, v2, v3 := from.Corners(i)
+			a := v2.Sub(v1).Cross(v3.Sub(v1)).Length() / 2
+			if a == 0 {
+				continue
+			}
+
+			longest := math.Max(v2.Sub(v1).Length(), math.Max(v3.Sub(v2).Length(), v1.Sub(v3).Length()))
+			divisions := int(math.Ceil(longest / spacing))
+			if divisions < 1 {
+				divisions = 1
+			}
+			if divisions > deviationMaxDivisions {
+				divisions = deviationMaxDivisions
+			}
+
+			points := subdivisionCentroids(v1, v2, v3, divisions)
+			weight := a / float64(len(points))
+			for _, p := range points {
+				q, _ := to.closest(p)
+				d := p.Sub(q).Length()
+				sum += d * weight
+				squares += d * d * weight
+				res.Max = math.Max(res.Max, d)
+			}
+			area += a
+		}
+	}
+	sample(m, theirs)
+	sample(other, mine)
+
+	if area > 0 {
+		res.Mean = sum / area
+		res.RMS = math.Sqrt(squares / area)
+	}
+	return res
+}
+
+// AlignTo returns the rigid transform that best fits the mesh onto another mesh, found with the
+// iterative closest point algorithm. The meshes are first moved so that the centres of their bounds
+// meet, and then the vertices of the mesh are matched to the closest points on the other mesh and
+// moved onto them until the fit stops improving. Meshes that start out far from aligned can end up
+// in a fit that is only the best nearby.
+func (m *Mesh) AlignTo(other *Mesh) Matrix {
+	if len(m.Vertices) == 0 || len(other.Triangles) == 0 {
+		return Identity()
+	}
+
+	// vertices are sampled evenly through the mesh when there are too many to match all of them
+	step := (len(m.Vertices) + alignSamples - 1) / alignSamples
+	var samples []Vector
+	for i := 0; i < len(m.Vertices); i += step {
+		samples = append(samples, m.Vertices[i])
+	}
+
+	a, b := m.Bounds(), other.Bounds()
+	res := Translation(b.Min.Add(b.Max).Sub(a.Min.Add(a.Max)).DivScalar(2))
+
+	tree := newBVH(other)
+	matches := make([]Vector, len(samples))
+	tolerance := a.Size().Length() * 1e-9
+	last := math.Inf(1)
+	for iteration := 0; iteration < alignIterations; iteration++ {
+		var squares float64
+		for i, s := range samples {
+			p := res.Apply(s)
+			matches[i], _ = tree.closest(p)
+			squares += matches[i].Sub(p).Dot(matches[i].Sub(p))
+		}
+		rms := math.Sqrt(squares / float64(len(samples)))
+		if last-rms <= tolerance {
+			break
+		}
+		last = rms
+
+		res = fitRigid(samples, matches)
+	}
+	return res
+}
+
+// fitRigid returns the rotation and translation that moves points closest onto their matches in the
+// least squares sense, using Horn's closed form solution with unit quaternions
+func fitRigid(points, matches []Vector) Matrix {
+	var ca, cb Vector
+	for i := range points {
+		ca = ca.Add(points[i])
+		cb = cb.Add(matches[i])
+	}
+	ca, cb = ca.DivScalar(float64(len(points))), cb.DivScalar(float64(len(points)))
+
+	// s is the cross covariance of the points and their matches around their centres
+	var s [3][3]float64
+	for i := range points {
+		p, q := points[i].Sub(ca), matches[i].Sub(cb)
+		a, b := [3]float64{p.X, p.Y, p.Z}, [3]float64{q.X, q.Y, q.Z}
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				s[j][k] += a[j] * b[k]
+			}
+		}
+	}
+
+	// the rotation is the eigenvector of the largest eigenvalue of n, as a quaternion
+	n := [4][4]float64{
+		{s[0][0] + s[1][1] + s[2][2], s[1][2] - s[2][1], s[2][0] - s[0][2], s[0][1] - s[1][0]},
+		{s[1][2] - s[2][1], s[0][0] - s[1][1] - s[2][2], s[0][1] + s[1][0], s[2][0] + s[0][2]},
+		{s[2][0] - s[0][2], s[0][1] + s[1][0], -s[0][0] + s[1][1] - s[2][2], s[1][2] + s[2][1]},
+		{s[0][1] - s[1][0], s[2][0] + s[0][2], s[1][2] + s[2][1], -s[0][0] - s[1][1] + s[2][2]},
+	}
+	q := largestEigenvector(n)
+	w, x, y, z := q[0], q[1], q[2], q[3]
+
+	r := Matrix{
+		{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y), 0},
+		{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x), 0},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y), 0},
+		{0, 0, 0, 1},
+	}
+	return Translation(cb.Sub(r.Apply(ca))).Mul(r)
+}
+
+// largestEigenvector returns the unit eigenvector of the largest eigenvalue of a symmetric matrix,
+// using the Jacobi eigenvalue algorithm
+func largestEigenvector(a [4][4]float64) [4]float64 {
+	var v [4][4]float64
+	for i := range v {
+		v[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 50; sweep++ {
+		var off float64
+		for i := 0; i < 4; i++ {
+			for j := i + 1; j < 4; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+
+		for p := 0; p < 4; p++ {
+			for q := p + 1; q < 4; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+
+				// the rotation in the plane of p and q that zeroes a[p][q]
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < 4; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p], a[k][q] = c*akp-s*akq, s*akp+c*akq
+				}
+				for k := 0; k < 4; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k], a[q][k] = c*apk-s*aqk, s*apk+c*aqk
+				}
+				for k := 0; k < 4; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+
+	largest := 0
+	for i := 1; i < 4; i++ {
+		if a[i][i] > a[largest][largest] {
+			largest = i
+		}
+	}
+	return [4]float64{v[0][largest], v[1][largest], v[2][largest], v[3][largest]}
+}
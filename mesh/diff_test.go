@@ -0,0 +1,68 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestDeviation(t *testing.T) {
+	t.Run("same", func(t *testing.T) {
+		d := cube(10).Deviation(cube(10))
+
+		assert.InDelta(t, 0, d.Max, 1e-9)
+		assert.InDelta(t, 0, d.Mean, 1e-9)
+		assert.InDelta(t, 0, d.RMS, 1e-9)
+		assert.Len(t, d.Vertices, 8)
+	})
+	t.Run("grown", func(t *testing.T) {
+		// every face of the larger cube lies 1mm outside of the smaller one
+		small := box(mesh.Vector{X: 1, Y: 1, Z: 1}, mesh.Vector{X: 9, Y: 9, Z: 9}, false)
+		large := cube(10)
+
+		d := large.Deviation(small)
+
+		for _, v := range d.Vertices {
+			assert.InDelta(t, math.Sqrt(3), v, 1e-9)
+		}
+		assert.InDelta(t, math.Sqrt(3), d.Max, 1e-9)
+		assert.Greater(t, d.Mean, 1.0)
+		assert.Less(t, d.Mean, d.RMS)
+		assert.Less(t, d.RMS, math.Sqrt(3))
+
+		// the vertices of the smaller cube lie inside of the larger one
+		for _, v := range small.Deviation(large).Vertices {
+			assert.InDelta(t, -1, v, 1e-9)
+		}
+	})
+	t.Run("bump", func(t *testing.T) {
+		// a block on top of a cube is only found by sampling the other way
+		bumped := cube(10)
+		bumped.Append(box(mesh.Vector{X: 4, Y: 4, Z: 10}, mesh.Vector{X: 6, Y: 6, Z: 13}, false))
+
+		d := cube(10).Deviation(bumped)
+
+		for _, v := range d.Vertices {
+			assert.InDelta(t, 0, v, 1e-9)
+		}
+		assert.InDelta(t, 3, d.Max, 1e-9)
+		assert.Greater(t, d.RMS, 0.0)
+	})
+}
+
+func TestAlignTo(t *testing.T) {
+	m := box(mesh.Vector{}, mesh.Vector{X: 20, Y: 10, Z: 5}, false)
+	moved := mesh.Translation(mesh.Vector{X: 3, Y: -7, Z: 12}).Mul(mesh.Rotation(mesh.Vector{X: 1, Y: 2, Z: 3}.Normalize(), 0.3))
+	other := m.Transform(moved)
+
+	aligned := m.Transform(m.AlignTo(other))
+
+	d := aligned.Deviation(other)
+	assert.InDelta(t, 0, d.Max, 1e-6)
+	for i, v := range aligned.Vertices {
+		assert.InDelta(t, 0, v.Sub(other.Vertices[i]).Length(), 1e-6)
+	}
+}
@@ -0,0 +1,115 @@
+package mesh
+
+import "math"
+
+const (
+	// fitDirections is the number of evenly spread directions along which the extreme vertices of a
+	// mesh are collected to speed up trying rotations
+	fitDirections = 256
+	// fitTurns is the number of rotations around the vertical axis that are tried for every side that
+	// a mesh can stand on, spread over half a turn
+	fitTurns = 36
+	// fitTolerance is how far a mesh can stick out of a build volume, as a fraction of its size, and
+	// still fit
+	fitTolerance = 1e-9
+)
+
+// Fit is the rotation in which a mesh fits into a build volume with the most room to spare
+type Fit struct {
+	// Rotation turns the mesh into the orientation that fits best
+	Rotation Matrix
+	// Size is the size of the bounds of the mesh once it is rotated
+	Size Vector
+	// Scale is the largest factor that the mesh can be scaled by in that rotation and still fit.
+	// Meshes with a scale of at least 1 fit as they are.
+	Scale float64
+}
+
+// Fits reports whether the mesh fits into the build volume without scaling it
+func (f Fit) Fits() bool {
+	return f.Scale >= 1-fitTolerance
+}
+
+// FitInto finds the rotation in which the mesh fits into a build volume with the most room to spare.
+// The sides that the mesh can stand on are tried in every direction of the build plate, starting
+// with the orientation that the mesh is in already.
+func (m *Mesh) FitInto(volume Vector) Fit {
+	best := Fit{Rotation: Identity(), Size: m.Bounds().Size()}
+	best.Scale = fitScale(volume, best.Size)
+	if len(m.Vertices) == 0 {
+		return best
+	}
+
+	// only the vertices that stick out furthest in some direction can touch the sides of the volume
+	points := m.extremeVertices()
+
+	var rotations []Matrix
+	for _, down := range m.orientationCandidates() {
+		stand := RotationBetween(down, Vector{Z: -1})
+		for i := 0; i < fitTurns; i++ {
+			rotations = append(rotations, Rotation(Vector{Z: 1}, math.Pi*float64(i)/fitTurns).Mul(stand))
+		}
+	}
+
+	for _, r := range rotations {
+		size := rotatedSize(points, r)
+		if scale := fitScale(volume, size); scale > best.Scale*(1+fitTolerance) {
+			best = Fit{Rotation: r, Size: size, Scale: scale}
+		}
+	}
+
+	// the size of the best rotation is measured again over every vertex in case an extreme vertex was
+	// missed
+	best.Size = rotatedSize(m.Vertices, best.Rotation)
+	best.Scale = fitScale(volume, best.Size)
+	return best
+}
+
+// fitScale returns the largest factor that a box can be scaled by to fit into a build volume
+func fitScale(volume, size Vector) float64 {
+	scale := math.Inf(1)
+	for _, s := range [3][2]float64{{volume.X, size.X}, {volume.Y, size.Y}, {volume.Z, size.Z}} {
+		if s[1] > 0 {
+			scale = math.Min(scale, s[0]/s[1])
+		}
+	}
+	return scale
+}
+
+// rotatedSize returns the size of the bounds of points once they are rotated
+func rotatedSize(points []Vector, r Matrix) Vector {
+	min, max := Vector{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}, Vector{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, p := range points {
+		p = r.Apply(p)
+		min = min.Min(p)
+		max = max.Max(p)
+	}
+	return max.Sub(min)
+}
+
+// extremeVertices returns the vertices that stick out furthest along directions spread evenly over
+// a sphere
+func (m *Mesh) extremeVertices() []Vector {
+	seen := make(map[int]bool)
+	var res []Vector
+
+	golden := math.Pi * (3 - math.Sqrt(5))
+	for i := 0; i < fitDirections; i++ {
+		z := 1 - (2*float64(i)+1)/fitDirections
+		r := math.Sqrt(1 - z*z)
+		phi := golden * float64(i)
+		d := Vector{X: r * math.Cos(phi), Y: r * math.Sin(phi), Z: z}
+
+		furthest, distance := 0, math.Inf(-1)
+		for j, v := range m.Vertices {
+			if dot := v.Dot(d); dot > distance {
+				furthest, distance = j, dot
+			}
+		}
+		if !seen[furthest] {
+			seen[furthest] = true
+			res = append(res, m.Vertices[furthest])
+		}
+	}
+	return res
+}
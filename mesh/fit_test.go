@@ -0,0 +1,46 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestFitInto(t *testing.T) {
+	volume := mesh.Vector{X: 250, Y: 250, Z: 250}
+
+	t.Run("as-is", func(t *testing.T) {
+		f := cube(10).FitInto(volume)
+
+		assert.True(t, f.Fits())
+		assert.InDelta(t, 25, f.Scale, 1e-9)
+	})
+	t.Run("diagonal", func(t *testing.T) {
+		// a 300mm rod only fits diagonally across the build plate
+		rod := box(mesh.Vector{}, mesh.Vector{X: 300, Y: 10, Z: 10}, false)
+
+		f := rod.FitInto(volume)
+
+		assert.True(t, f.Fits())
+		assert.LessOrEqual(t, f.Size.X, volume.X)
+		assert.LessOrEqual(t, f.Size.Y, volume.Y)
+		assert.LessOrEqual(t, f.Size.Z, volume.Z)
+		// the rotation keeps the size of the rod
+		rotated := rod.Transform(f.Rotation).Bounds().Size()
+		assert.InDelta(t, f.Size.X, rotated.X, 1e-9)
+		assert.InDelta(t, 300*10*10, rod.Transform(f.Rotation).Properties().Volume, 1e-6)
+	})
+	t.Run("too-large", func(t *testing.T) {
+		// the diagonal of the build volume is the longest that a thin rod can be
+		rod := box(mesh.Vector{}, mesh.Vector{X: 500, Y: 1, Z: 1}, false)
+
+		f := rod.FitInto(volume)
+
+		assert.False(t, f.Fits())
+		assert.Less(t, f.Scale, 1.0)
+		assert.Greater(t, f.Scale, 250*math.Sqrt(2)/500*0.95)
+	})
+}
@@ -0,0 +1,91 @@
+package mesh
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a file format that a mesh can be read from or written to
+type Format string
+
+const (
+	FormatSTL Format = "stl"
+	FormatOBJ Format = "obj"
+	Format3MF Format = "3mf"
+	FormatPLY Format = "ply"
+
+	// export only formats
+	FormatSTLASCII Format = "stl-ascii"
+	FormatGLB      Format = "glb"
+)
+
+// FormatFromFilename detects the format of a file from its extension
+func FormatFromFilename(filename string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".stl":
+		return FormatSTL, true
+	case ".obj":
+		return FormatOBJ, true
+	case ".3mf":
+		return Format3MF, true
+	case ".ply":
+		return FormatPLY, true
+	default:
+		return "", false
+	}
+}
+
+// Extension returns the file extension, including the leading dot, used for files of the format
+func (f Format) Extension() string {
+	if f == FormatSTLASCII {
+		return ".stl"
+	}
+	return "." + string(f)
+}
+
+// CanWrite reports whether a mesh can be written in the format
+func (f Format) CanWrite() bool {
+	switch f {
+	case FormatSTL, FormatSTLASCII, FormatOBJ, Format3MF, FormatPLY, FormatGLB:
+		return true
+	default:
+		return false
+	}
+}
+
+// Read parses a file of the given format into a mesh
+func Read(r io.Reader, f Format) (*Mesh, error) {
+	switch f {
+	case FormatSTL:
+		return ReadSTL(r)
+	case FormatOBJ:
+		return ReadOBJ(r)
+	case Format3MF:
+		return Read3MF(r)
+	case FormatPLY:
+		return ReadPLY(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Write encodes a mesh in the given format
+func Write(w io.Writer, m *Mesh, f Format) error {
+	switch f {
+	case FormatSTL:
+		return WriteSTL(w, m)
+	case FormatSTLASCII:
+		return WriteASCIISTL(w, m)
+	case FormatOBJ:
+		return WriteOBJ(w, m)
+	case Format3MF:
+		return Write3MF(w, m)
+	case FormatPLY:
+		return WritePLY(w, m)
+	case FormatGLB:
+		return WriteGLB(w, m)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
@@ -0,0 +1,121 @@
+package mesh_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestFormatFromFilename(t *testing.T) {
+	f, ok := mesh.FormatFromFilename("Part.OBJ")
+	assert.True(t, ok)
+	assert.Equal(t, mesh.FormatOBJ, f)
+
+	_, ok = mesh.FormatFromFilename("photo.jpg")
+	assert.False(t, ok)
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	original := &mesh.Mesh{
+		Vertices:  []mesh.Vector{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1.5}},
+		Triangles: []mesh.Triangle{{0, 1, 2}, {0, 3, 1}, {0, 2, 3}, {1, 3, 2}},
+		Colors: []mesh.Color{
+			{R: 255, A: 255}, {G: 255, A: 255}, {B: 255, A: 255}, {R: 10, G: 20, B: 30, A: 255},
+		},
+	}
+
+	for _, f := range []mesh.Format{mesh.FormatSTL, mesh.FormatSTLASCII, mesh.FormatOBJ, mesh.FormatPLY, mesh.Format3MF} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, mesh.Write(&buf, original, f))
+
+			readFormat := f
+			if f == mesh.FormatSTLASCII {
+				readFormat = mesh.FormatSTL
+			}
+			m, err := mesh.Read(&buf, readFormat)
+			require.NoError(t, err)
+
+			assert.Equal(t, original.Vertices, m.Vertices)
+			assert.Equal(t, original.Triangles, m.Triangles)
+		})
+	}
+}
+
+func TestWritePLYKeepsColors(t *testing.T) {
+	original := &mesh.Mesh{
+		Vertices:  []mesh.Vector{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}},
+		Triangles: []mesh.Triangle{{0, 1, 2}},
+		Colors:    []mesh.Color{{R: 255, A: 255}, {G: 255, A: 128}, {B: 255, A: 0}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, mesh.WritePLY(&buf, original))
+
+	m, err := mesh.ReadPLY(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, original.Colors, m.Colors)
+}
+
+func TestWriteGLB(t *testing.T) {
+	m := &mesh.Mesh{
+		Vertices:  []mesh.Vector{{0, 0, 0}, {1, 0, 0}, {0, 2, 3}},
+		Triangles: []mesh.Triangle{{0, 1, 2}},
+		Colors:    []mesh.Color{{R: 255, A: 255}, {G: 255, A: 255}, {B: 255, A: 255}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, mesh.WriteGLB(&buf, m))
+	data := buf.Bytes()
+
+	assert.Equal(t, "glTF", string(data[0:4]))
+	assert.Equal(t, uint32(2), binary.LittleEndian.Uint32(data[4:]))
+	assert.Equal(t, uint32(len(data)), binary.LittleEndian.Uint32(data[8:]))
+
+	jsonLength := binary.LittleEndian.Uint32(data[12:])
+	assert.Equal(t, "JSON", string(data[16:20]))
+	assert.Zero(t, jsonLength%4)
+
+	var doc struct {
+		Accessors []struct {
+			Count int       `json:"count"`
+			Type  string    `json:"type"`
+			Min   []float64 `json:"min"`
+			Max   []float64 `json:"max"`
+		} `json:"accessors"`
+		Buffers []struct {
+			ByteLength int `json:"byteLength"`
+		} `json:"buffers"`
+	}
+	require.NoError(t, json.Unmarshal(data[20:20+jsonLength], &doc))
+
+	// positions, colors and indices
+	require.Len(t, doc.Accessors, 3)
+	assert.Equal(t, 3, doc.Accessors[0].Count)
+	// glTF is Y-up, so Z becomes Y and Y becomes -Z
+	assert.Equal(t, []float64{0, 0, -2}, doc.Accessors[0].Min)
+	assert.Equal(t, []float64{1, 3, 0}, doc.Accessors[0].Max)
+	assert.Equal(t, "VEC4", doc.Accessors[1].Type)
+	assert.Equal(t, 3, doc.Accessors[2].Count)
+	assert.Equal(t, 3*12+3*4+3*4, doc.Buffers[0].ByteLength)
+
+	binHeader := data[20+jsonLength:]
+	assert.Equal(t, "BIN\x00", string(binHeader[4:8]))
+
+	// the last position is (0, 2, 3) rotated into Y-up
+	var position [3]float32
+	require.NoError(t, binary.Read(bytes.NewReader(binHeader[8+2*12:]), binary.LittleEndian, &position))
+	assert.Equal(t, [3]float32{0, 3, -2}, position)
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := mesh.Write(&buf, &mesh.Mesh{}, mesh.Format("step"))
+	assert.Equal(t, mesh.ErrUnsupportedFormat, err)
+}
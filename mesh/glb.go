@@ -0,0 +1,183 @@
+package mesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+)
+
+const (
+	glbMagic     = 0x46546C67 // "glTF"
+	glbVersion   = 2
+	glbJSONChunk = 0x4E4F534A // "JSON"
+	glbBINChunk  = 0x004E4942 // "BIN\x00"
+
+	glTFFloat         = 5126
+	glTFUnsignedByte  = 5121
+	glTFUnsignedInt   = 5125
+	glTFArrayBuffer   = 34962
+	glTFElementBuffer = 34963
+)
+
+type glTFDocument struct {
+	Asset       map[string]string `json:"asset"`
+	Scene       int               `json:"scene"`
+	Scenes      []glTFScene       `json:"scenes"`
+	Nodes       []glTFNode        `json:"nodes"`
+	Meshes      []glTFMesh        `json:"meshes"`
+	Accessors   []glTFAccessor    `json:"accessors"`
+	BufferViews []glTFBufferView  `json:"bufferViews"`
+	Buffers     []glTFBuffer      `json:"buffers"`
+}
+
+type glTFScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type glTFNode struct {
+	Mesh int `json:"mesh"`
+}
+
+type glTFMesh struct {
+	Primitives []glTFPrimitive `json:"primitives"`
+}
+
+type glTFPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+}
+
+type glTFAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Normalized    bool      `json:"normalized,omitempty"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+}
+
+type glTFBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target"`
+}
+
+type glTFBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+// WriteGLB encodes the mesh as a binary glTF 2.0 file for use in web viewers. Vertex colors are
+// written to the COLOR_0 attribute. glTF is Y-up, so the mesh is rotated -90° about the X axis to
+// keep the top of a Z-up model at the top.
+func WriteGLB(w io.Writer, m *Mesh) error {
+	bin := new(bytes.Buffer)
+	doc := glTFDocument{
+		Asset:  map[string]string{"version": "2.0", "generator": "rkmesh"},
+		Scenes: []glTFScene{{Nodes: []int{0}}},
+		Nodes:  []glTFNode{{Mesh: 0}},
+	}
+	primitive := glTFPrimitive{Attributes: make(map[string]int)}
+
+	// positions
+	// the rotation negates Y, so the largest Y of the mesh becomes the smallest Z of the bounds
+	bounds := m.Bounds()
+	bounds.Min, bounds.Max = glTFPosition(Vector{bounds.Min.X, bounds.Max.Y, bounds.Min.Z}),
+		glTFPosition(Vector{bounds.Max.X, bounds.Min.Y, bounds.Max.Z})
+	for _, v := range m.Vertices {
+		p := glTFPosition(v)
+		for _, f := range []float64{p.X, p.Y, p.Z} {
+			binary.Write(bin, binary.LittleEndian, math.Float32bits(float32(f)))
+		}
+	}
+	primitive.Attributes["POSITION"] = len(doc.Accessors)
+	doc.addBufferView(0, bin.Len(), glTFArrayBuffer)
+	doc.Accessors = append(doc.Accessors, glTFAccessor{
+		BufferView:    len(doc.BufferViews) - 1,
+		ComponentType: glTFFloat,
+		Count:         len(m.Vertices),
+		Type:          "VEC3",
+		Min:           []float64{float64(float32(bounds.Min.X)), float64(float32(bounds.Min.Y)), float64(float32(bounds.Min.Z))},
+		Max:           []float64{float64(float32(bounds.Max.X)), float64(float32(bounds.Max.Y)), float64(float32(bounds.Max.Z))},
+	})
+
+	// colors
+	if m.HasColors() {
+		offset := bin.Len()
+		for _, c := range m.Colors {
+			bin.Write([]byte{c.R, c.G, c.B, c.A})
+		}
+		primitive.Attributes["COLOR_0"] = len(doc.Accessors)
+		doc.addBufferView(offset, bin.Len()-offset, glTFArrayBuffer)
+		doc.Accessors = append(doc.Accessors, glTFAccessor{
+			BufferView:    len(doc.BufferViews) - 1,
+			ComponentType: glTFUnsignedByte,
+			Normalized:    true,
+			Count:         len(m.Colors),
+			Type:          "VEC4",
+		})
+	}
+
+	// indices
+	offset := bin.Len()
+	for _, t := range m.Triangles {
+		binary.Write(bin, binary.LittleEndian, []uint32{uint32(t[0]), uint32(t[1]), uint32(t[2])})
+	}
+	primitive.Indices = len(doc.Accessors)
+	doc.addBufferView(offset, bin.Len()-offset, glTFElementBuffer)
+	doc.Accessors = append(doc.Accessors, glTFAccessor{
+		BufferView:    len(doc.BufferViews) - 1,
+		ComponentType: glTFUnsignedInt,
+		Count:         len(m.Triangles) * 3,
+		Type:          "SCALAR",
+	})
+
+	doc.Meshes = []glTFMesh{{Primitives: []glTFPrimitive{primitive}}}
+	doc.Buffers = []glTFBuffer{{ByteLength: bin.Len()}}
+
+	js, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	// both chunks must be 4 byte aligned. JSON is padded with spaces and binary data with zeros.
+	js = append(js, bytes.Repeat([]byte(" "), padding(len(js)))...)
+	bin.Write(make([]byte, padding(bin.Len())))
+
+	header := []uint32{glbMagic, glbVersion, uint32(12 + 8 + len(js) + 8 + bin.Len())}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, []uint32{uint32(len(js)), glbJSONChunk}); err != nil {
+		return err
+	}
+	if _, err := w.Write(js); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, []uint32{uint32(bin.Len()), glbBINChunk}); err != nil {
+		return err
+	}
+	_, err = w.Write(bin.Bytes())
+	return err
+}
+
+// glTFPosition rotates a Z-up position into the Y-up coordinates of glTF
+func glTFPosition(v Vector) Vector {
+	return Vector{X: v.X, Y: v.Z, Z: -v.Y}
+}
+
+func (doc *glTFDocument) addBufferView(offset, length, target int) {
+	doc.BufferViews = append(doc.BufferViews, glTFBufferView{
+		Buffer:     0,
+		ByteOffset: offset,
+		ByteLength: length,
+		Target:     target,
+	})
+}
+
+func padding(n int) int {
+	return (4 - n%4) % 4
+}
@@ -0,0 +1,118 @@
+package mesh
+
+import "math"
+
+// Matrix is a 4x4 affine transform that is applied to column vectors
+type Matrix [4][4]float64
+
+// Identity returns the transform that leaves every point unchanged
+func Identity() Matrix {
+	return Matrix{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// Mul returns the transform that applies b and then a
+func (a Matrix) Mul(b Matrix) Matrix {
+	var m Matrix
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 4; k++ {
+				m[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return m
+}
+
+// Apply transforms a point
+func (a Matrix) Apply(v Vector) Vector {
+	return Vector{
+		a[0][0]*v.X + a[0][1]*v.Y + a[0][2]*v.Z + a[0][3],
+		a[1][0]*v.X + a[1][1]*v.Y + a[1][2]*v.Z + a[1][3],
+		a[2][0]*v.X + a[2][1]*v.Y + a[2][2]*v.Z + a[2][3],
+	}
+}
+
+// Scaling returns the transform that scales points away from the origin along each axis
+func Scaling(x, y, z float64) Matrix {
+	return Matrix{
+		{x, 0, 0, 0},
+		{0, y, 0, 0},
+		{0, 0, z, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// Translation returns the transform that moves points by an offset
+func Translation(v Vector) Matrix {
+	return Matrix{
+		{1, 0, 0, v.X},
+		{0, 1, 0, v.Y},
+		{0, 0, 1, v.Z},
+		{0, 0, 0, 1},
+	}
+}
+
+// Rotation returns the transform that rotates points counter-clockwise around an axis through the
+// origin by an angle in radians
+func Rotation(axis Vector, angle float64) Matrix {
+	a := axis.Normalize()
+	c, s := math.Cos(angle), math.Sin(angle)
+	t := 1 - c
+	return Matrix{
+		{t*a.X*a.X + c, t*a.X*a.Y - s*a.Z, t*a.X*a.Z + s*a.Y, 0},
+		{t*a.X*a.Y + s*a.Z, t*a.Y*a.Y + c, t*a.Y*a.Z - s*a.X, 0},
+		{t*a.X*a.Z - s*a.Y, t*a.Y*a.Z + s*a.X, t*a.Z*a.Z + c, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// RotationBetween returns the shortest rotation that turns direction a onto direction b
+func RotationBetween(a, b Vector) Matrix {
+	a, b = a.Normalize(), b.Normalize()
+	axis := a.Cross(b)
+	cos := a.Dot(b)
+	if axis.Length() < 1e-9 {
+		if cos > 0 {
+			return Identity()
+		}
+		// the directions are opposite so any axis perpendicular to them works
+		axis = a.Cross(Vector{X: 1})
+		if axis.Length() < 1e-9 {
+			axis = a.Cross(Vector{Y: 1})
+		}
+		return Rotation(axis, math.Pi)
+	}
+	return Rotation(axis, math.Atan2(axis.Length(), cos))
+}
+
+// Determinant returns the determinant of the linear part of the transform. It is negative for
+// transforms that mirror, and zero for transforms that flatten points onto a plane.
+func (a Matrix) Determinant() float64 {
+	return a[0][0]*(a[1][1]*a[2][2]-a[1][2]*a[2][1]) -
+		a[0][1]*(a[1][0]*a[2][2]-a[1][2]*a[2][0]) +
+		a[0][2]*(a[1][0]*a[2][1]-a[1][1]*a[2][0])
+}
+
+// Transform returns a copy of the mesh with the transform applied to all of its vertices. Mirroring
+// transforms turn the surface inside out, so the winding of the triangles is reversed for them.
+func (m *Mesh) Transform(a Matrix) *Mesh {
+	res := &Mesh{
+		Vertices:  make([]Vector, len(m.Vertices)),
+		Triangles: append([]Triangle(nil), m.Triangles...),
+		Colors:    append([]Color(nil), m.Colors...),
+	}
+	for i, v := range m.Vertices {
+		res.Vertices[i] = a.Apply(v)
+	}
+	if a.Determinant() < 0 {
+		for i, t := range res.Triangles {
+			res.Triangles[i] = Triangle{t[0], t[2], t[1]}
+		}
+	}
+	return res
+}
@@ -0,0 +1,37 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestRotation(t *testing.T) {
+	r := mesh.Rotation(mesh.Vector{Z: 1}, math.Pi/2)
+	p := r.Apply(mesh.Vector{X: 1})
+
+	assert.InDelta(t, 0, p.X, 1e-9)
+	assert.InDelta(t, 1, p.Y, 1e-9)
+	assert.InDelta(t, 1, r.Determinant(), 1e-9)
+}
+
+func TestTransform(t *testing.T) {
+	m := cube(10)
+	moved := m.Transform(mesh.Translation(mesh.Vector{X: 5}).Mul(mesh.Scaling(2, 1, 1)))
+
+	assert.Equal(t, mesh.Box{Min: mesh.Vector{X: 5}, Max: mesh.Vector{X: 25, Y: 10, Z: 10}}, moved.Bounds())
+	assert.InDelta(t, 2000, moved.Properties().Volume, 1e-9)
+	// the original mesh is left unchanged
+	assert.Equal(t, mesh.Box{Max: mesh.Vector{X: 10, Y: 10, Z: 10}}, m.Bounds())
+}
+
+func TestTransformMirror(t *testing.T) {
+	mirrored := cube(10).Transform(mesh.Scaling(-1, 1, 1))
+
+	// the winding is reversed so the mirrored cube is not inside out
+	assert.InDelta(t, 1000, mirrored.Properties().Volume, 1e-9)
+	assert.Equal(t, -10.0, mirrored.Bounds().Min.X)
+}
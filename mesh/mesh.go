@@ -0,0 +1,130 @@
+// Package mesh contains an in-memory triangle mesh type along with readers for the 3D file formats
+// that can be uploaded as models
+package mesh
+
+import "errors"
+
+var (
+	// ErrEmptyMesh will throw if a parsed file does not contain any triangles
+	ErrEmptyMesh = errors.New("Mesh does not contain any triangles")
+	// ErrInvalidFormat will throw if a file can not be parsed as the expected format
+	ErrInvalidFormat = errors.New("File is not a valid mesh")
+	// ErrUnsupportedFormat will throw if a mesh can not be read or written in the requested format
+	ErrUnsupportedFormat = errors.New("Format is not supported")
+	// ErrTooLarge will throw if a file expands to more triangles than can be held in memory
+	ErrTooLarge = errors.New("Mesh has too many triangles")
+)
+
+// Triangle holds the indices of its three corners in Mesh.Vertices, in counter-clockwise order
+// when viewed from outside the surface
+type Triangle [3]int
+
+// Color is an 8 bit per channel RGBA color
+type Color struct {
+	R, G, B, A uint8
+}
+
+// Mesh is an indexed triangle mesh
+type Mesh struct {
+	Vertices  []Vector
+	Triangles []Triangle
+	// Colors holds an optional color for each vertex. It is either empty or the same length as
+	// Vertices.
+	Colors []Color
+}
+
+// HasColors reports whether the mesh has per-vertex colors
+func (m *Mesh) HasColors() bool {
+	return len(m.Colors) > 0 && len(m.Colors) == len(m.Vertices)
+}
+
+// Box is an axis-aligned bounding box
+type Box struct {
+	Min Vector
+	Max Vector
+}
+
+// Size returns the extent of the box along each axis
+func (b Box) Size() Vector {
+	return b.Max.Sub(b.Min)
+}
+
+// Corners returns the three vertex positions of the i-th triangle
+func (m *Mesh) Corners(i int) (Vector, Vector, Vector) {
+	t := m.Triangles[i]
+	return m.Vertices[t[0]], m.Vertices[t[1]], m.Vertices[t[2]]
+}
+
+// Normal returns the unit normal of the i-th triangle, following the right hand rule around its
+// corners. Degenerate triangles have a zero normal.
+func (m *Mesh) Normal(i int) Vector {
+	v1, v2, v3 := m.Corners(i)
+	return v2.Sub(v1).Cross(v3.Sub(v1)).Normalize()
+}
+
+// Bounds returns the bounding box of all vertices in the mesh
+func (m *Mesh) Bounds() Box {
+	if len(m.Vertices) == 0 {
+		return Box{}
+	}
+
+	b := Box{Min: m.Vertices[0], Max: m.Vertices[0]}
+	for _, v := range m.Vertices[1:] {
+		b.Min = b.Min.Min(v)
+		b.Max = b.Max.Max(v)
+	}
+	return b
+}
+
+// Append adds the vertices and triangles of another mesh to the mesh. If only one of the meshes has
+// colors the vertices of the other are colored white so the colors stay aligned with the vertices.
+func (m *Mesh) Append(other *Mesh) {
+	offset := len(m.Vertices)
+	if m.HasColors() || other.HasColors() {
+		m.Colors = append(m.Colors, whiteColors(len(m.Vertices)-len(m.Colors))...)
+		if other.HasColors() {
+			m.Colors = append(m.Colors, other.Colors...)
+		} else {
+			m.Colors = append(m.Colors, whiteColors(len(other.Vertices))...)
+		}
+	}
+
+	m.Vertices = append(m.Vertices, other.Vertices...)
+	for _, t := range other.Triangles {
+		m.Triangles = append(m.Triangles, Triangle{t[0] + offset, t[1] + offset, t[2] + offset})
+	}
+}
+
+func whiteColors(n int) []Color {
+	colors := make([]Color, n)
+	for i := range colors {
+		colors[i] = Color{255, 255, 255, 255}
+	}
+	return colors
+}
+
+// builder creates an indexed mesh from a soup of triangles by merging vertices that share the exact
+// same position
+type builder struct {
+	mesh    *Mesh
+	indices map[Vector]int
+}
+
+func newBuilder() *builder {
+	return &builder{mesh: &Mesh{}, indices: make(map[Vector]int)}
+}
+
+func (b *builder) vertex(v Vector) int {
+	if i, ok := b.indices[v]; ok {
+		return i
+	}
+
+	i := len(b.mesh.Vertices)
+	b.mesh.Vertices = append(b.mesh.Vertices, v)
+	b.indices[v] = i
+	return i
+}
+
+func (b *builder) triangle(v1, v2, v3 Vector) {
+	b.mesh.Triangles = append(b.mesh.Triangles, Triangle{b.vertex(v1), b.vertex(v2), b.vertex(v3)})
+}
@@ -0,0 +1,31 @@
+package mesh_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestAppendKeepsColorsAligned(t *testing.T) {
+	colored := &mesh.Mesh{
+		Vertices:  []mesh.Vector{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}},
+		Triangles: []mesh.Triangle{{0, 1, 2}},
+		Colors:    []mesh.Color{{R: 255, A: 255}, {R: 255, A: 255}, {R: 255, A: 255}},
+	}
+	plain := &mesh.Mesh{
+		Vertices:  []mesh.Vector{{0, 0, 1}, {1, 0, 1}, {0, 1, 1}},
+		Triangles: []mesh.Triangle{{0, 1, 2}},
+	}
+
+	m := &mesh.Mesh{}
+	m.Append(plain)
+	m.Append(colored)
+
+	require.True(t, m.HasColors())
+	assert.Equal(t, mesh.Color{R: 255, G: 255, B: 255, A: 255}, m.Colors[0])
+	assert.Equal(t, mesh.Color{R: 255, A: 255}, m.Colors[3])
+	assert.Equal(t, mesh.Triangle{3, 4, 5}, m.Triangles[1])
+}
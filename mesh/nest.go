@@ -0,0 +1,335 @@
+package mesh
+
+import (
+	"math"
+	"sort"
+)
+
+// nestResolution is the number of voxels along the longest side of a build volume that parts are
+// packed into
+const nestResolution = 128
+
+// Container is the build volume of a powder bed printer, with its floor at Z=0
+type Container struct {
+	// Size is the size of a box. Cylinders have a diameter of Size.X and a height of Size.Z and
+	// stand centered in the box of that size.
+	Size     Vector
+	Cylinder bool
+}
+
+// Volume returns the volume of the container
+func (c Container) Volume() float64 {
+	if c.Cylinder {
+		return math.Pi * c.Size.X * c.Size.X / 4 * c.Size.Z
+	}
+	return c.Size.X * c.Size.Y * c.Size.Z
+}
+
+// footprint returns the size of the container seen from above
+func (c Container) footprint() Vector2 {
+	if c.Cylinder {
+		return Vector2{X: c.Size.X, Y: c.Size.X}
+	}
+	return Vector2{X: c.Size.X, Y: c.Size.Y}
+}
+
+// Nest packs parts into a build volume, keeping at least spacing between them and half of it to the
+// walls. The largest parts are placed first, each in the position and orientation that keeps its
+// top lowest, dropped onto the parts below it. Parts are voxelized into nestResolution voxels along
+// the longest side of the container, so the spacing is rounded up to whole voxels. When rotate is
+// set, parts are also tried in every orientation that turns their axes onto the axes of the
+// container. Nest returns the transform that moves each part into the container.
+func Nest(parts []*Mesh, c Container, spacing float64, rotate bool) ([]Matrix, error) {
+	rotations := []Matrix{Identity()}
+	if rotate {
+		rotations = axisRotations()
+	}
+
+	bed := c.footprint()
+	voxel := math.Max(math.Max(bed.X, bed.Y), c.Size.Z) / nestResolution
+	heights := newNestHeights(c, voxel)
+
+	order := make([]int, len(parts))
+	sizes := make([]float64, len(parts))
+	for i, p := range parts {
+		order[i] = i
+		s := p.Bounds().Size()
+		sizes[i] = s.X * s.Y * s.Z
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return sizes[order[i]] > sizes[order[j]]
+	})
+
+	// copies of a part share their masks
+	masks := make(map[*Mesh][]nestMask)
+
+	res := make([]Matrix, len(parts))
+	for _, i := range order {
+		if _, ok := masks[parts[i]]; !ok {
+			for _, r := range rotations {
+				masks[parts[i]] = append(masks[parts[i]], newNestMask(parts[i], r, voxel, spacing/2))
+			}
+		}
+
+		found := false
+		var best nestMask
+		var bestX, bestY, bestZ int
+		for _, mask := range masks[parts[i]] {
+			x, y, z, ok := heights.find(mask)
+			if !ok {
+				continue
+			}
+
+			// the orientation whose top stays lowest wins
+			if !found || z+mask.height < bestZ+best.height {
+				found, best, bestX, bestY, bestZ = true, mask, x, y, z
+			}
+		}
+		if !found {
+			return nil, ErrDoesNotFit
+		}
+
+		heights.fill(best, bestX, bestY, bestZ)
+		corner := Vector{X: float64(bestX), Y: float64(bestY), Z: float64(bestZ)}.MulScalar(voxel)
+		offset := corner.Add(Vector{X: spacing / 2, Y: spacing / 2, Z: spacing / 2}).Sub(best.min)
+		res[i] = Translation(offset).Mul(best.rotation)
+	}
+	return res, nil
+}
+
+// axisRotations returns the 24 rotations that turn the axes onto the axes
+func axisRotations() []Matrix {
+	ups := []Vector{{Z: 1}, {Z: -1}, {X: 1}, {X: -1}, {Y: 1}, {Y: -1}}
+
+	var res []Matrix
+	for _, up := range ups {
+		stand := RotationBetween(up, Vector{Z: 1})
+		for turn := 0; turn < 4; turn++ {
+			r := Rotation(Vector{Z: 1}, math.Pi/2*float64(turn)).Mul(stand)
+			// the rotations only hold 0, 1 and -1
+			for i := range r {
+				for j := range r[i] {
+					r[i][j] = math.Round(r[i][j])
+				}
+			}
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// nestMask is the voxels that a rotated part takes up together with the spacing around it, as the
+// lowest and highest voxel of every column. Parts are dropped from above, so the space below a part
+// is taken as well once it is placed.
+type nestMask struct {
+	rotation Matrix
+	// min is the corner of the bounds of the rotated part, which lies at half the spacing from the
+	// corner of the first voxel
+	min                  Vector
+	width, depth, height int
+	// bottoms and tops are the first voxel and the voxel after the last in every column, from the
+	// first row to the last. Empty columns have a top of zero.
+	bottoms, tops []int
+}
+
+func newNestMask(m *Mesh, r Matrix, voxel, margin float64) nestMask {
+	rotated := m.Transform(r)
+	b := rotated.Bounds()
+	size := b.Size()
+
+	mask := nestMask{
+		rotation: r,
+		min:      b.Min,
+		width:    maxInt(1, int(math.Ceil((size.X+2*margin)/voxel))),
+		depth:    maxInt(1, int(math.Ceil((size.Y+2*margin)/voxel))),
+		height:   maxInt(1, int(math.Ceil((size.Z+2*margin)/voxel))),
+	}
+	mask.bottoms = make([]int, mask.width*mask.depth)
+	mask.tops = make([]int, mask.width*mask.depth)
+	lows := make([]float64, len(mask.bottoms))
+	highs := make([]float64, len(mask.tops))
+	for i := range lows {
+		lows[i], highs[i] = math.Inf(1), math.Inf(-1)
+	}
+
+	// columns are cells of the grid in coordinates where the corner of the part lies at the margin.
+	// A triangle reaches into a column when it passes through the column grown by the margin.
+	for i := range rotated.Triangles {
+		v1, v2, v3 := rotated.Corners(i)
+		triangle := [3]Vector{v1.Sub(b.Min), v2.Sub(b.Min), v3.Sub(b.Min)}
+		tmin, tmax := triangle[0].Min(triangle[1]).Min(triangle[2]), triangle[0].Max(triangle[1]).Max(triangle[2])
+
+		x0, x1 := maxInt(0, int(math.Floor(tmin.X/voxel))), minInt(mask.width-1, int(math.Floor((tmax.X+2*margin)/voxel)))
+		y0, y1 := maxInt(0, int(math.Floor(tmin.Y/voxel))), minInt(mask.depth-1, int(math.Floor((tmax.Y+2*margin)/voxel)))
+		for y := y0; y <= y1; y++ {
+			for x := x0; x <= x1; x++ {
+				lo, hi, ok := clippedHeights(triangle,
+					float64(x)*voxel-2*margin, float64(x+1)*voxel,
+					float64(y)*voxel-2*margin, float64(y+1)*voxel)
+				if !ok {
+					continue
+				}
+				column := y*mask.width + x
+				lows[column] = math.Min(lows[column], lo)
+				highs[column] = math.Max(highs[column], hi)
+			}
+		}
+	}
+
+	for i := range lows {
+		if lows[i] > highs[i] {
+			continue
+		}
+		mask.bottoms[i] = int(math.Floor(lows[i] / voxel))
+		mask.tops[i] = minInt(mask.height, int(math.Ceil((highs[i]+2*margin)/voxel)))
+	}
+	return mask
+}
+
+// clippedHeights returns the lowest and highest point of the part of a triangle that lies over a
+// rectangle
+func clippedHeights(triangle [3]Vector, x0, x1, y0, y1 float64) (float64, float64, bool) {
+	// a triangle that is clipped by four sides has at most seven corners
+	var buffers [2][8]Vector
+	polygon := buffers[0][:0]
+	polygon = append(polygon, triangle[:]...)
+
+	for side, bound := range [4]float64{x0, x1, y0, y1} {
+		// the distance inside of the side
+		inside := func(v Vector) float64 {
+			switch side {
+			case 0:
+				return v.X - bound
+			case 1:
+				return bound - v.X
+			case 2:
+				return v.Y - bound
+			default:
+				return bound - v.Y
+			}
+		}
+
+		clipped := buffers[(side+1)%2][:0]
+		for i, p := range polygon {
+			q := polygon[(i+1)%len(polygon)]
+			dp, dq := inside(p), inside(q)
+			if dp >= 0 {
+				clipped = append(clipped, p)
+			}
+			if (dp >= 0) != (dq >= 0) {
+				clipped = append(clipped, p.Add(q.Sub(p).MulScalar(dp/(dp-dq))))
+			}
+		}
+		polygon = clipped
+		if len(polygon) == 0 {
+			return 0, 0, false
+		}
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range polygon {
+		lo, hi = math.Min(lo, p.Z), math.Max(hi, p.Z)
+	}
+	return lo, hi, true
+}
+
+// nestHeights is the first free voxel of every column of the container. Columns outside of a
+// cylinder are full.
+type nestHeights struct {
+	columns, rows, layers int
+	heights               []int
+}
+
+func newNestHeights(c Container, voxel float64) *nestHeights {
+	bed := c.footprint()
+	h := &nestHeights{
+		columns: int(bed.X / voxel),
+		rows:    int(bed.Y / voxel),
+		layers:  int(c.Size.Z / voxel),
+	}
+	h.heights = make([]int, h.columns*h.rows)
+	if !c.Cylinder {
+		return h
+	}
+
+	// columns with a corner outside of the cylinder can not be used
+	radius := c.Size.X / 2
+	for y := 0; y < h.rows; y++ {
+		for x := 0; x < h.columns; x++ {
+			for _, corner := range [4][2]int{{x, y}, {x + 1, y}, {x, y + 1}, {x + 1, y + 1}} {
+				dx, dy := float64(corner[0])*voxel-radius, float64(corner[1])*voxel-radius
+				if dx*dx+dy*dy > radius*radius {
+					h.heights[y*h.columns+x] = h.layers
+				}
+			}
+		}
+	}
+	return h
+}
+
+// find returns the position that a mask comes to rest at when it is dropped into the container,
+// out of the positions where its top stays lowest, the frontmost and then leftmost one
+func (h *nestHeights) find(mask nestMask) (int, int, int, bool) {
+	found := false
+	var bestX, bestY int
+	// positions that rest higher than the best one so far are given up on early
+	bestZ := h.layers - mask.height + 1
+	for y := 0; y+mask.depth <= h.rows; y++ {
+	positions:
+		for x := 0; x+mask.width <= h.columns; x++ {
+			z := 0
+			for row := 0; row < mask.depth; row++ {
+				line := h.heights[(y+row)*h.columns+x:]
+				for column := 0; column < mask.width; column++ {
+					i := row*mask.width + column
+					if mask.tops[i] == 0 {
+						continue
+					}
+					if rest := line[column] - mask.bottoms[i]; rest > z {
+						if rest >= bestZ {
+							continue positions
+						}
+						z = rest
+					}
+				}
+			}
+
+			found, bestX, bestY, bestZ = true, x, y, z
+			// nothing rests lower than the floor
+			if z == 0 {
+				return bestX, bestY, bestZ, true
+			}
+		}
+	}
+	return bestX, bestY, bestZ, found
+}
+
+// fill takes the voxels of a mask at a position and everything below them
+func (h *nestHeights) fill(mask nestMask, x, y, z int) {
+	for row := 0; row < mask.depth; row++ {
+		for column := 0; column < mask.width; column++ {
+			i := row*mask.width + column
+			if mask.tops[i] == 0 {
+				continue
+			}
+			cell := (y+row)*h.columns + x + column
+			if top := z + mask.tops[i]; top > h.heights[cell] {
+				h.heights[cell] = top
+			}
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,104 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// gap returns the distance between two boxes along the axis that separates them most
+func gap(a, b mesh.Box) float64 {
+	return math.Max(math.Max(
+		math.Max(b.Min.X-a.Max.X, a.Min.X-b.Max.X),
+		math.Max(b.Min.Y-a.Max.Y, a.Min.Y-b.Max.Y)),
+		math.Max(b.Min.Z-a.Max.Z, a.Min.Z-b.Max.Z))
+}
+
+func TestNest(t *testing.T) {
+	t.Run("box", func(t *testing.T) {
+		parts := make([]*mesh.Mesh, 8)
+		for i := range parts {
+			parts[i] = cube(10)
+		}
+		c := mesh.Container{Size: mesh.Vector{X: 25, Y: 25, Z: 25}}
+
+		placements, err := mesh.Nest(parts, c, 2, false)
+
+		require.NoError(t, err)
+		require.Len(t, placements, 8)
+		var bounds []mesh.Box
+		for i, p := range placements {
+			b := parts[i].Transform(p).Bounds()
+			assert.GreaterOrEqual(t, b.Min.X, 1-1e-9)
+			assert.GreaterOrEqual(t, b.Min.Y, 1-1e-9)
+			assert.GreaterOrEqual(t, b.Min.Z, 1-1e-9)
+			assert.LessOrEqual(t, b.Max.X, 24+1e-9)
+			assert.LessOrEqual(t, b.Max.Y, 24+1e-9)
+			assert.LessOrEqual(t, b.Max.Z, 24+1e-9)
+			for _, other := range bounds {
+				assert.GreaterOrEqual(t, gap(b, other), 2-1e-9)
+			}
+			bounds = append(bounds, b)
+		}
+	})
+	t.Run("full", func(t *testing.T) {
+		parts := make([]*mesh.Mesh, 9)
+		for i := range parts {
+			parts[i] = cube(10)
+		}
+		c := mesh.Container{Size: mesh.Vector{X: 25, Y: 25, Z: 25}}
+
+		_, err := mesh.Nest(parts, c, 2, false)
+
+		assert.Equal(t, mesh.ErrDoesNotFit, err)
+	})
+	t.Run("cylinder", func(t *testing.T) {
+		// the diagonal of the cube is a little more than 14mm
+		_, err := mesh.Nest([]*mesh.Mesh{cube(10)}, mesh.Container{Size: mesh.Vector{X: 14, Z: 20}, Cylinder: true}, 0, false)
+		assert.Equal(t, mesh.ErrDoesNotFit, err)
+
+		placements, err := mesh.Nest([]*mesh.Mesh{cube(10)}, mesh.Container{Size: mesh.Vector{X: 15, Z: 20}, Cylinder: true}, 0, false)
+
+		require.NoError(t, err)
+		for _, v := range cube(10).Transform(placements[0]).Vertices {
+			assert.LessOrEqual(t, math.Hypot(v.X-7.5, v.Y-7.5), 7.5+1e-9)
+		}
+	})
+	t.Run("rotate", func(t *testing.T) {
+		// a long rod only fits standing up
+		rod := box(mesh.Vector{}, mesh.Vector{X: 30, Y: 5, Z: 5}, false)
+		c := mesh.Container{Size: mesh.Vector{X: 10, Y: 10, Z: 40}}
+
+		_, err := mesh.Nest([]*mesh.Mesh{rod}, c, 1, false)
+		assert.Equal(t, mesh.ErrDoesNotFit, err)
+
+		placements, err := mesh.Nest([]*mesh.Mesh{rod}, c, 1, true)
+
+		require.NoError(t, err)
+		placed := rod.Transform(placements[0])
+		assert.InDelta(t, 30, placed.Bounds().Size().Z, 1e-9)
+		assert.InDelta(t, 30*5*5, placed.Properties().Volume, 1e-6)
+	})
+	t.Run("stacked", func(t *testing.T) {
+		// plates that cover the floor are stacked on top of each other
+		parts := make([]*mesh.Mesh, 3)
+		for i := range parts {
+			parts[i] = box(mesh.Vector{}, mesh.Vector{X: 18, Y: 18, Z: 4}, false)
+		}
+		c := mesh.Container{Size: mesh.Vector{X: 20, Y: 20, Z: 20}}
+
+		placements, err := mesh.Nest(parts, c, 1, false)
+
+		require.NoError(t, err)
+		var top float64
+		for i, p := range placements {
+			top = math.Max(top, parts[i].Transform(p).Bounds().Max.Z)
+		}
+		assert.Less(t, top, 20.0)
+		assert.Greater(t, top, 14.0)
+	})
+}
@@ -0,0 +1,134 @@
+package mesh
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadOBJ parses the geometry of a Wavefront OBJ file into a mesh. Faces with more than three
+// corners are triangulated. Groups, objects and material references are accepted but only the
+// geometry is kept since the .mtl files referenced by the upload are not available.
+func ReadOBJ(r io.Reader) (*Mesh, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	m := &Mesh{}
+	var line string
+	for scanner.Scan() {
+		// a trailing backslash continues the statement on the next line
+		text := scanner.Text()
+		if strings.HasSuffix(text, "\\") {
+			line += strings.TrimSuffix(text, "\\") + " "
+			continue
+		}
+		line += text
+
+		fields := strings.Fields(line)
+		line = ""
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch fields[0] {
+		case "v":
+			v, err := parseOBJVertex(fields[1:])
+			if err != nil {
+				return nil, err
+			}
+			m.Vertices = append(m.Vertices, v)
+		case "f":
+			err := addOBJFace(m, fields[1:])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func parseOBJVertex(fields []string) (Vector, error) {
+	// an optional w coordinate or vertex color may follow the position
+	if len(fields) < 3 {
+		return Vector{}, ErrInvalidFormat
+	}
+
+	var coords [3]float64
+	for i := range coords {
+		f, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Vector{}, ErrInvalidFormat
+		}
+		coords[i] = f
+	}
+
+	v := Vector{coords[0], coords[1], coords[2]}
+	if !isFinite(v) {
+		return Vector{}, ErrInvalidFormat
+	}
+	return v, nil
+}
+
+// addOBJFace resolves the vertex references of a face and triangulates it into the mesh
+func addOBJFace(m *Mesh, fields []string) error {
+	if len(fields) < 3 {
+		return ErrInvalidFormat
+	}
+
+	indices := make([]int, len(fields))
+	for i, f := range fields {
+		// only the position index is needed from the v/vt/vn triplet
+		ref := strings.SplitN(f, "/", 2)[0]
+		n, err := strconv.Atoi(ref)
+		if err != nil {
+			return ErrInvalidFormat
+		}
+
+		// indices are 1-based and negative indices are relative to the end of the vertex list
+		switch {
+		case n > 0:
+			n--
+		case n < 0:
+			n += len(m.Vertices)
+		default:
+			return ErrInvalidFormat
+		}
+
+		if n < 0 || n >= len(m.Vertices) {
+			return ErrInvalidFormat
+		}
+		indices[i] = n
+	}
+
+	m.Triangles = append(m.Triangles, triangulate(m.Vertices, indices)...)
+	return nil
+}
+
+// WriteOBJ encodes the mesh as a Wavefront OBJ file. Vertex colors are written after the position
+// of each vertex, which is a widely supported extension of the format.
+func WriteOBJ(w io.Writer, m *Mesh) error {
+	bw := bufio.NewWriter(w)
+	hasColors := m.HasColors()
+
+	fmt.Fprintln(bw, "# rkmesh")
+	for i, v := range m.Vertices {
+		if hasColors {
+			c := m.Colors[i]
+			fmt.Fprintf(bw, "v %s %s %s %s\n", formatVector(v),
+				formatFloat(float64(c.R)/255), formatFloat(float64(c.G)/255), formatFloat(float64(c.B)/255))
+		} else {
+			fmt.Fprintf(bw, "v %s\n", formatVector(v))
+		}
+	}
+	for _, t := range m.Triangles {
+		fmt.Fprintf(bw, "f %d %d %d\n", t[0]+1, t[1]+1, t[2]+1)
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,103 @@
+package mesh_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+const objCube = `# unit cube split into two groups, away from the origin so that faces on every side add volume
+mtllib cube.mtl
+o cube
+v 5 5 5
+v 6 5 5
+v 6 6 5
+v 5 6 5
+v 5 5 6
+v 6 5 6
+v 6 6 6
+v 5 6 6
+vt 0 0
+vn 0 0 1
+g bottom
+usemtl grey
+f 1/1/1 4/1/1 3/1/1 2/1/1
+g sides
+f 1 2 6 5
+f 2 3 7 6
+f 3 4 8 7
+f -1 -5 -8 -4
+g top
+usemtl red
+f 5 6 \
+  7 8
+`
+
+func TestReadOBJ(t *testing.T) {
+	m, err := mesh.ReadOBJ(strings.NewReader(objCube))
+	require.NoError(t, err)
+
+	assert.Len(t, m.Vertices, 8)
+	assert.Len(t, m.Triangles, 12)
+
+	p := m.Properties()
+	assert.InDelta(t, 1, p.Volume, 1e-9)
+	assert.InDelta(t, 6, p.SurfaceArea, 1e-9)
+	assert.InDelta(t, 0, p.Centroid.Sub(mesh.Vector{X: 5.5, Y: 5.5, Z: 5.5}).Length(), 1e-9)
+	assert.Zero(t, m.Analyze().InconsistentlyWoundFaces)
+}
+
+func TestReadOBJKeepsWinding(t *testing.T) {
+	// a quad that faces down is clockwise when it is seen from above
+	obj := `
+v 0 0 0
+v 0 1 0
+v 1 1 0
+v 1 0 0
+f 1 2 3 4
+`
+	m, err := mesh.ReadOBJ(strings.NewReader(obj))
+	require.NoError(t, err)
+
+	for i := range m.Triangles {
+		assert.Less(t, m.Normal(i).Z, 0.0)
+	}
+}
+
+func TestReadOBJConcaveFace(t *testing.T) {
+	// an L shaped hexagon that would be triangulated incorrectly by a fan from its first corner
+	obj := `
+v 2 1 0
+v 1 1 0
+v 1 2 0
+v 0 2 0
+v 0 0 0
+v 2 0 0
+f 1 2 3 4 5 6
+`
+	m, err := mesh.ReadOBJ(strings.NewReader(obj))
+	require.NoError(t, err)
+
+	assert.Len(t, m.Triangles, 4)
+	assert.InDelta(t, 3, m.Properties().SurfaceArea, 1e-9)
+}
+
+func TestReadOBJInvalid(t *testing.T) {
+	tests := map[string]string{
+		"index-out-of-range": "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 4\n",
+		"zero-index":         "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 0 1 2\n",
+		"too-few-corners":    "v 0 0 0\nv 1 0 0\nf 1 2\n",
+		"bad-coordinate":     "v 0 0 x\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := mesh.ReadOBJ(strings.NewReader(input))
+			assert.Equal(t, mesh.ErrInvalidFormat, err)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package mesh
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+const (
+	// maxFaceCandidates is the number of the largest flat areas of a mesh that are tried as the side
+	// it stands on
+	maxFaceCandidates = 12
+	// minFaceFraction is the smallest fraction of the surface area that a flat area needs to be
+	// tried as the side a mesh stands on
+	minFaceFraction = 0.01
+	// sphereCandidates is the number of evenly spread directions that are tried in addition to the
+	// flat areas
+	sphereCandidates = 64
+	// minCandidateAngle is the smallest angle in degrees between two directions that are both tried
+	minCandidateAngle = 2.0
+)
+
+// weights of the properties of an orientation in its score
+const (
+	supportWeight  = 1.0
+	overhangWeight = 0.5
+	heightWeight   = 0.25
+	contactWeight  = 0.25
+)
+
+// Orientation is a way of placing a mesh on the build plate and how well it prints that way
+type Orientation struct {
+	// Down is the direction in the coordinates of the mesh that faces the build plate
+	Down Vector
+	// Rotation turns the mesh so that Down points along -Z
+	Rotation Matrix
+	// OverhangArea is the area of the faces that face down more steeply than the overhang angle
+	OverhangArea float64
+	// SupportVolume is the estimated volume of the supports below the overhangs
+	SupportVolume float64
+	// ContactArea is the area of the faces that lie flat on the build plate
+	ContactArea float64
+	// Height is how tall the mesh is
+	Height float64
+	// Score ranks the orientation against the others that were evaluated. Lower is better.
+	Score float64
+}
+
+// Orient tries placing the mesh on each of its largest flat areas and in evenly spread directions,
+// and returns the orientations from best to worst. Orientations are scored by their support volume,
+// overhang area and height, and how much of the mesh touches the build plate. Trying every
+// orientation of a large mesh takes a while, so it stops with the error of the context once the
+// context is done.
+func (m *Mesh) Orient(ctx context.Context, overhangAngle float64) ([]Orientation, error) {
+	candidates := m.orientationCandidates()
+	support := &supportAnalyzer{mesh: m}
+	res := make([]Orientation, len(candidates))
+	for i, down := range candidates {
+		o, err := m.evaluateOrientation(ctx, support, down, overhangAngle)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = o
+	}
+
+	// every property is scored relative to the worst of all orientations so that the score does not
+	// depend on the size of the mesh
+	var maxSupport, maxOverhang, maxHeight, maxContact float64
+	for _, o := range res {
+		maxSupport = math.Max(maxSupport, o.SupportVolume)
+		maxOverhang = math.Max(maxOverhang, o.OverhangArea)
+		maxHeight = math.Max(maxHeight, o.Height)
+		maxContact = math.Max(maxContact, o.ContactArea)
+	}
+	for i := range res {
+		o := &res[i]
+		o.Score = supportWeight*fraction(o.SupportVolume, maxSupport) +
+			overhangWeight*fraction(o.OverhangArea, maxOverhang) +
+			heightWeight*fraction(o.Height, maxHeight) -
+			contactWeight*fraction(o.ContactArea, maxContact)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Score < res[j].Score
+	})
+	return res, nil
+}
+
+func fraction(v, max float64) float64 {
+	if max == 0 {
+		return 0
+	}
+	return v / max
+}
+
+// evaluateOrientation measures how the mesh prints when it is placed with a direction facing down.
+// The overhangs and their support are found by the support analysis of the mesh.
+func (m *Mesh) evaluateOrientation(ctx context.Context, support *supportAnalyzer, down Vector, overhangAngle float64) (Orientation, error) {
+	down = down.Normalize()
+	up := down.MulScalar(-1)
+	o := Orientation{Down: down, Rotation: RotationBetween(down, Vector{Z: -1})}
+	if len(m.Triangles) == 0 {
+		return o, nil
+	}
+
+	floor, top := math.Inf(1), math.Inf(-1)
+	for _, v := range m.Vertices {
+		h := v.Dot(up)
+		floor = math.Min(floor, h)
+		top = math.Max(top, h)
+	}
+	o.Height = top - floor
+
+	a, err := support.analyze(ctx, up, overhangAngle)
+	if err != nil {
+		return Orientation{}, err
+	}
+	o.OverhangArea = a.OverhangArea
+	o.SupportVolume = a.SupportVolume
+	o.ContactArea = a.PlateArea
+	return o, nil
+}
+
+// orientationCandidates returns the directions that are tried as the side of the mesh that faces
+// down: the normals of its largest flat areas and directions spread evenly over a sphere
+func (m *Mesh) orientationCandidates() []Vector {
+	type flatArea struct {
+		normal Vector
+		area   float64
+	}
+
+	// faces are grouped by their normal so that flat areas made of many triangles are found
+	areas := make(map[[3]int64]*flatArea)
+	var total float64
+	for i := range m.Triangles {
+		v1, v2, v3 := m.Corners(i)
+		n := v2.Sub(v1).Cross(v3.Sub(v1))
+		area := n.Length() / 2
+		if area == 0 {
+			continue
+		}
+		total += area
+
+		unit := n.Normalize()
+		key := [3]int64{int64(math.Round(unit.X * 1000)), int64(math.Round(unit.Y * 1000)), int64(math.Round(unit.Z * 1000))}
+		a, ok := areas[key]
+		if !ok {
+			a = &flatArea{}
+			areas[key] = a
+		}
+		a.normal = a.normal.Add(unit.MulScalar(area))
+		a.area += area
+	}
+
+	sorted := make([]*flatArea, 0, len(areas))
+	for _, a := range areas {
+		sorted = append(sorted, a)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].area != sorted[j].area {
+			return sorted[i].area > sorted[j].area
+		}
+		// keep the order deterministic for areas of the same size
+		a, b := sorted[i].normal, sorted[j].normal
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.Z < b.Z
+	})
+
+	var candidates []Vector
+	minCos := math.Cos(minCandidateAngle * math.Pi / 180)
+	add := func(d Vector) {
+		d = d.Normalize()
+		for _, c := range candidates {
+			if c.Dot(d) > minCos {
+				return
+			}
+		}
+		candidates = append(candidates, d)
+	}
+
+	// straight down keeps the orientation that the mesh was uploaded in
+	add(Vector{Z: -1})
+	for i, a := range sorted {
+		if i >= maxFaceCandidates || a.area < minFaceFraction*total {
+			break
+		}
+		add(a.normal)
+	}
+
+	// a Fibonacci lattice spreads points evenly over the sphere
+	golden := math.Pi * (3 - math.Sqrt(5))
+	for i := 0; i < sphereCandidates; i++ {
+		z := 1 - (2*float64(i)+1)/sphereCandidates
+		r := math.Sqrt(1 - z*z)
+		phi := golden * float64(i)
+		add(Vector{X: r * math.Cos(phi), Y: r * math.Sin(phi), Z: z})
+	}
+	return candidates
+}
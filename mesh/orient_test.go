@@ -0,0 +1,84 @@
+package mesh_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestOrient(t *testing.T) {
+	// a thin plate that stands on its short edge
+	plate := box(mesh.Vector{}, mesh.Vector{X: 2, Y: 100, Z: 50}, false)
+
+	orientations, err := plate.Orient(context.Background(), mesh.DefaultOverhangAngle)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, orientations)
+	best := orientations[0]
+	// the plate is best printed lying on one of its large sides
+	assert.InDelta(t, 1, best.Down.X*best.Down.X, 1e-9)
+	assert.InDelta(t, 2, best.Height, 1e-9)
+	assert.InDelta(t, 5000, best.ContactArea, 1e-9)
+	assert.Equal(t, 0.0, best.SupportVolume)
+
+	// the rotation turns the side that faces down onto the build plate
+	down := best.Rotation.Apply(best.Down)
+	assert.InDelta(t, -1, down.Z, 1e-9)
+	rotated := plate.Transform(best.Rotation).Bounds().Size()
+	assert.InDelta(t, 2, rotated.Z, 1e-9)
+
+	for i := 1; i < len(orientations); i++ {
+		assert.LessOrEqual(t, orientations[i-1].Score, orientations[i].Score)
+	}
+}
+
+func TestOrientMatchesSupportAnalysis(t *testing.T) {
+	m := plateOverBase(1)
+
+	orientations, err := m.Orient(context.Background(), mesh.DefaultOverhangAngle)
+	require.NoError(t, err)
+
+	// every orientation is measured by the same support analysis as a single build direction
+	for _, o := range orientations {
+		a, err := m.AnalyzeSupport(context.Background(), o.Down.MulScalar(-1), mesh.DefaultOverhangAngle)
+		require.NoError(t, err)
+		assert.InDelta(t, a.SupportVolume, o.SupportVolume, 1e-9)
+		assert.InDelta(t, a.OverhangArea, o.OverhangArea, 1e-9)
+		assert.InDelta(t, a.PlateArea, o.ContactArea, 1e-9)
+	}
+}
+
+func TestOrientCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := plateOverBase(1).Orient(ctx, mesh.DefaultOverhangAngle)
+
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestRotationBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b mesh.Vector
+	}{
+		{"perpendicular", mesh.Vector{X: 1}, mesh.Vector{Z: -1}},
+		{"same", mesh.Vector{Z: -1}, mesh.Vector{Z: -1}},
+		{"opposite", mesh.Vector{Z: 1}, mesh.Vector{Z: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mesh.RotationBetween(tt.a, tt.b)
+			p := r.Apply(tt.a)
+
+			assert.InDelta(t, tt.b.X, p.X, 1e-9)
+			assert.InDelta(t, tt.b.Y, p.Y, 1e-9)
+			assert.InDelta(t, tt.b.Z, p.Z, 1e-9)
+			assert.InDelta(t, 1, r.Determinant(), 1e-9)
+		})
+	}
+}
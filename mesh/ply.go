@@ -0,0 +1,396 @@
+package mesh
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxPLYPolygonSize limits the number of corners of a face so that a corrupt length can not cause a
+// huge allocation
+const maxPLYPolygonSize = 1 << 16
+
+type plyProperty struct {
+	name string
+	typ  string
+	// list properties have a type for their length and a type for each item
+	isList    bool
+	countType string
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// plyValueReader reads the values of the body of a PLY file in one of the three encodings
+type plyValueReader interface {
+	read(typ string) (float64, error)
+}
+
+// ReadPLY parses an ASCII or binary (little or big endian) PLY file into a mesh. Per-vertex colors
+// are kept when the vertex element has red, green and blue properties.
+func ReadPLY(r io.Reader) (*Mesh, error) {
+	br := bufio.NewReader(r)
+
+	format, elements, err := readPLYHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	var values plyValueReader
+	switch format {
+	case "ascii":
+		scanner := bufio.NewScanner(br)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		scanner.Split(bufio.ScanWords)
+		values = &plyASCIIReader{scanner}
+	case "binary_little_endian":
+		values = &plyBinaryReader{br, binary.LittleEndian}
+	case "binary_big_endian":
+		values = &plyBinaryReader{br, binary.BigEndian}
+	default:
+		return nil, ErrInvalidFormat
+	}
+
+	m := &Mesh{}
+	var polygons [][]int
+	for _, e := range elements {
+		switch e.name {
+		case "vertex":
+			err = readPLYVertices(m, e, values)
+		case "face":
+			polygons, err = readPLYFaces(e, values)
+		default:
+			err = skipPLYElement(e, values)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range polygons {
+		for _, i := range p {
+			if i < 0 || i >= len(m.Vertices) {
+				return nil, ErrInvalidFormat
+			}
+		}
+		m.Triangles = append(m.Triangles, triangulate(m.Vertices, p)...)
+	}
+
+	return m, nil
+}
+
+func readPLYHeader(br *bufio.Reader) (string, []plyElement, error) {
+	magic, err := br.ReadString('\n')
+	if err != nil || strings.TrimSpace(magic) != "ply" {
+		return "", nil, ErrInvalidFormat
+	}
+
+	var format string
+	var elements []plyElement
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return "", nil, ErrInvalidFormat
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "format":
+			if len(fields) != 3 {
+				return "", nil, ErrInvalidFormat
+			}
+			format = fields[1]
+		case "element":
+			if len(fields) != 3 {
+				return "", nil, ErrInvalidFormat
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return "", nil, ErrInvalidFormat
+			}
+			elements = append(elements, plyElement{name: fields[1], count: count})
+		case "property":
+			if len(elements) == 0 {
+				return "", nil, ErrInvalidFormat
+			}
+			p, err := parsePLYProperty(fields[1:])
+			if err != nil {
+				return "", nil, err
+			}
+			e := &elements[len(elements)-1]
+			e.properties = append(e.properties, p)
+		case "end_header":
+			return format, elements, nil
+		}
+	}
+}
+
+func parsePLYProperty(fields []string) (plyProperty, error) {
+	if len(fields) == 4 && fields[0] == "list" {
+		if plyTypeSize(fields[1]) == 0 || plyTypeSize(fields[2]) == 0 {
+			return plyProperty{}, ErrInvalidFormat
+		}
+		return plyProperty{name: fields[3], typ: fields[2], isList: true, countType: fields[1]}, nil
+	}
+
+	if len(fields) != 2 || plyTypeSize(fields[0]) == 0 {
+		return plyProperty{}, ErrInvalidFormat
+	}
+	return plyProperty{name: fields[1], typ: fields[0]}, nil
+}
+
+// plyTypeSize returns the size in bytes of a PLY scalar type or 0 if the type is not known
+func plyTypeSize(typ string) int {
+	switch typ {
+	case "char", "uchar", "int8", "uint8":
+		return 1
+	case "short", "ushort", "int16", "uint16":
+		return 2
+	case "int", "uint", "float", "int32", "uint32", "float32":
+		return 4
+	case "double", "float64":
+		return 8
+	default:
+		return 0
+	}
+}
+
+func readPLYVertices(m *Mesh, e plyElement, values plyValueReader) error {
+	hasColor := hasPLYProperty(e, "red", "diffuse_red") &&
+		hasPLYProperty(e, "green", "diffuse_green") &&
+		hasPLYProperty(e, "blue", "diffuse_blue")
+
+	for i := 0; i < e.count; i++ {
+		var v Vector
+		c := Color{A: 255}
+		for _, p := range e.properties {
+			if p.isList {
+				if err := skipPLYList(p, values); err != nil {
+					return err
+				}
+				continue
+			}
+
+			f, err := values.read(p.typ)
+			if err != nil {
+				return err
+			}
+
+			switch p.name {
+			case "x":
+				v.X = f
+			case "y":
+				v.Y = f
+			case "z":
+				v.Z = f
+			case "red", "diffuse_red":
+				c.R = plyColorChannel(f, p.typ)
+			case "green", "diffuse_green":
+				c.G = plyColorChannel(f, p.typ)
+			case "blue", "diffuse_blue":
+				c.B = plyColorChannel(f, p.typ)
+			case "alpha":
+				c.A = plyColorChannel(f, p.typ)
+			}
+		}
+
+		if !isFinite(v) {
+			return ErrInvalidFormat
+		}
+		m.Vertices = append(m.Vertices, v)
+		if hasColor {
+			m.Colors = append(m.Colors, c)
+		}
+	}
+	return nil
+}
+
+// plyColorChannel converts a color value to 8 bits. Floating point colors are in the range 0 to 1.
+func plyColorChannel(f float64, typ string) uint8 {
+	if typ == "float" || typ == "float32" || typ == "double" || typ == "float64" {
+		f *= 255
+	}
+	return uint8(math.Max(0, math.Min(255, math.Round(f))))
+}
+
+func hasPLYProperty(e plyElement, names ...string) bool {
+	for _, p := range e.properties {
+		for _, n := range names {
+			if p.name == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func readPLYFaces(e plyElement, values plyValueReader) ([][]int, error) {
+	var polygons [][]int
+	for i := 0; i < e.count; i++ {
+		for _, p := range e.properties {
+			if !p.isList || (p.name != "vertex_indices" && p.name != "vertex_index") {
+				if err := skipPLYProperty(p, values); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			n, err := values.read(p.countType)
+			if err != nil {
+				return nil, err
+			}
+			if n < 3 || n > maxPLYPolygonSize {
+				return nil, ErrInvalidFormat
+			}
+
+			polygon := make([]int, int(n))
+			for j := range polygon {
+				idx, err := values.read(p.typ)
+				if err != nil {
+					return nil, err
+				}
+				polygon[j] = int(idx)
+			}
+			polygons = append(polygons, polygon)
+		}
+	}
+	return polygons, nil
+}
+
+func skipPLYElement(e plyElement, values plyValueReader) error {
+	for i := 0; i < e.count; i++ {
+		for _, p := range e.properties {
+			if err := skipPLYProperty(p, values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func skipPLYProperty(p plyProperty, values plyValueReader) error {
+	if p.isList {
+		return skipPLYList(p, values)
+	}
+	_, err := values.read(p.typ)
+	return err
+}
+
+func skipPLYList(p plyProperty, values plyValueReader) error {
+	n, err := values.read(p.countType)
+	if err != nil {
+		return err
+	}
+	for j := 0; j < int(n); j++ {
+		if _, err := values.read(p.typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type plyASCIIReader struct {
+	scanner *bufio.Scanner
+}
+
+func (a *plyASCIIReader) read(typ string) (float64, error) {
+	if !a.scanner.Scan() {
+		return 0, ErrInvalidFormat
+	}
+
+	f, err := strconv.ParseFloat(a.scanner.Text(), 64)
+	if err != nil {
+		return 0, ErrInvalidFormat
+	}
+	return f, nil
+}
+
+type plyBinaryReader struct {
+	r     io.Reader
+	order binary.ByteOrder
+}
+
+func (b *plyBinaryReader) read(typ string) (float64, error) {
+	var buf [8]byte
+	size := plyTypeSize(typ)
+	if _, err := io.ReadFull(b.r, buf[:size]); err != nil {
+		return 0, ErrInvalidFormat
+	}
+
+	switch typ {
+	case "char", "int8":
+		return float64(int8(buf[0])), nil
+	case "uchar", "uint8":
+		return float64(buf[0]), nil
+	case "short", "int16":
+		return float64(int16(b.order.Uint16(buf[:]))), nil
+	case "ushort", "uint16":
+		return float64(b.order.Uint16(buf[:])), nil
+	case "int", "int32":
+		return float64(int32(b.order.Uint32(buf[:]))), nil
+	case "uint", "uint32":
+		return float64(b.order.Uint32(buf[:])), nil
+	case "float", "float32":
+		return float64(math.Float32frombits(b.order.Uint32(buf[:]))), nil
+	default:
+		return math.Float64frombits(b.order.Uint64(buf[:])), nil
+	}
+}
+
+// WritePLY encodes the mesh as a binary little endian PLY file including any vertex colors
+func WritePLY(w io.Writer, m *Mesh) error {
+	bw := bufio.NewWriter(w)
+	hasColors := m.HasColors()
+
+	fmt.Fprintln(bw, "ply")
+	fmt.Fprintln(bw, "format binary_little_endian 1.0")
+	fmt.Fprintln(bw, "comment rkmesh")
+	fmt.Fprintf(bw, "element vertex %d\n", len(m.Vertices))
+	fmt.Fprintln(bw, "property float x")
+	fmt.Fprintln(bw, "property float y")
+	fmt.Fprintln(bw, "property float z")
+	if hasColors {
+		fmt.Fprintln(bw, "property uchar red")
+		fmt.Fprintln(bw, "property uchar green")
+		fmt.Fprintln(bw, "property uchar blue")
+		fmt.Fprintln(bw, "property uchar alpha")
+	}
+	fmt.Fprintf(bw, "element face %d\n", len(m.Triangles))
+	fmt.Fprintln(bw, "property list uchar int vertex_indices")
+	fmt.Fprintln(bw, "end_header")
+
+	buf := make([]byte, 16)
+	for i, v := range m.Vertices {
+		binary.LittleEndian.PutUint32(buf[0:], math.Float32bits(float32(v.X)))
+		binary.LittleEndian.PutUint32(buf[4:], math.Float32bits(float32(v.Y)))
+		binary.LittleEndian.PutUint32(buf[8:], math.Float32bits(float32(v.Z)))
+		n := 12
+		if hasColors {
+			c := m.Colors[i]
+			buf[12], buf[13], buf[14], buf[15] = c.R, c.G, c.B, c.A
+			n = 16
+		}
+		bw.Write(buf[:n])
+	}
+
+	for _, t := range m.Triangles {
+		buf[0] = 3
+		binary.LittleEndian.PutUint32(buf[1:], uint32(t[0]))
+		binary.LittleEndian.PutUint32(buf[5:], uint32(t[1]))
+		binary.LittleEndian.PutUint32(buf[9:], uint32(t[2]))
+		bw.Write(buf[:13])
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,138 @@
+package mesh_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+const asciiPLY = `ply
+format ascii 1.0
+comment a colored square
+element vertex 4
+property float x
+property float y
+property float z
+property uchar red
+property uchar green
+property uchar blue
+element face 1
+property list uchar int vertex_indices
+end_header
+0 0 0 255 0 0
+1 0 0 0 255 0
+1 1 0 0 0 255
+0 1 0 255 255 255
+4 0 1 2 3
+`
+
+func TestReadASCIIPLY(t *testing.T) {
+	m, err := mesh.ReadPLY(strings.NewReader(asciiPLY))
+	require.NoError(t, err)
+
+	assert.Len(t, m.Vertices, 4)
+	assert.Len(t, m.Triangles, 2)
+	require.True(t, m.HasColors())
+	assert.Equal(t, mesh.Color{R: 255, A: 255}, m.Colors[0])
+	assert.Equal(t, mesh.Color{B: 255, A: 255}, m.Colors[2])
+}
+
+func TestReadPLYQuadCube(t *testing.T) {
+	// a unit cube of quads away from the origin, so that faces on every side add volume
+	ply := `ply
+format ascii 1.0
+element vertex 8
+property float x
+property float y
+property float z
+element face 6
+property list uchar int vertex_indices
+end_header
+5 5 5
+6 5 5
+6 6 5
+5 6 5
+5 5 6
+6 5 6
+6 6 6
+5 6 6
+4 0 3 2 1
+4 0 1 5 4
+4 1 2 6 5
+4 2 3 7 6
+4 3 0 4 7
+4 4 5 6 7
+`
+	m, err := mesh.ReadPLY(strings.NewReader(ply))
+	require.NoError(t, err)
+
+	assert.Len(t, m.Triangles, 12)
+	assert.InDelta(t, 1, m.Properties().Volume, 1e-9)
+	assert.Zero(t, m.Analyze().InconsistentlyWoundFaces)
+	assert.True(t, m.Analyze().Watertight)
+}
+
+func TestReadBinaryPLY(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(order.String(), func(t *testing.T) {
+			format := "binary_little_endian"
+			if order == binary.BigEndian {
+				format = "binary_big_endian"
+			}
+
+			// the properties are in an unusual order and include ones that are not used
+			b := new(bytes.Buffer)
+			b.WriteString("ply\nformat " + format + " 1.0\n" +
+				"element material 1\nproperty uchar id\n" +
+				"element vertex 3\nproperty double z\nproperty float confidence\nproperty float x\n" +
+				"property float y\nproperty float red\nproperty float green\nproperty float blue\n" +
+				"element face 1\nproperty uchar flags\nproperty list uchar uint vertex_index\n" +
+				"property list uchar float texcoord\nend_header\n")
+
+			b.WriteByte(7)
+			for _, v := range [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}} {
+				binary.Write(b, order, float64(v[2]))
+				binary.Write(b, order, float32(0.5))
+				binary.Write(b, order, v[0])
+				binary.Write(b, order, v[1])
+				binary.Write(b, order, []float32{1, 0.5, 0})
+			}
+			b.WriteByte(0)
+			b.WriteByte(3)
+			binary.Write(b, order, []uint32{0, 1, 2})
+			b.WriteByte(2)
+			binary.Write(b, order, []float32{0, 1})
+
+			m, err := mesh.ReadPLY(b)
+			require.NoError(t, err)
+
+			assert.Equal(t, []mesh.Vector{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, m.Vertices)
+			assert.Equal(t, []mesh.Triangle{{0, 1, 2}}, m.Triangles)
+			assert.Equal(t, mesh.Color{R: 255, G: 128, B: 0, A: 255}, m.Colors[1])
+		})
+	}
+}
+
+func TestReadPLYInvalid(t *testing.T) {
+	tests := map[string]string{
+		"missing-magic":    "format ascii 1.0\nend_header\n",
+		"unknown-format":   "ply\nformat binary_middle_endian 1.0\nend_header\n",
+		"unknown-type":     "ply\nformat ascii 1.0\nelement vertex 1\nproperty float128 x\nend_header\n",
+		"truncated-body":   "ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nend_header\n0 0 0\n",
+		"index-past-end":   "ply\nformat ascii 1.0\nelement vertex 3\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n0 0 0\n1 0 0\n0 1 0\n3 0 1 3\n",
+		"unterminated-hdr": "ply\nformat ascii 1.0\nelement vertex 3\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := mesh.ReadPLY(strings.NewReader(input))
+			assert.Equal(t, mesh.ErrInvalidFormat, err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package mesh
+
+import "math"
+
+// Properties are the mass properties of a mesh
+type Properties struct {
+	Volume        float64
+	SurfaceArea   float64
+	Bounds        Box
+	TriangleCount int
+	VertexCount   int
+	Centroid      Vector
+}
+
+// Properties computes the mass properties of the mesh. The volume and centroid are only meaningful
+// for closed meshes. If the mesh encloses no volume the area weighted centroid of its surface is
+// used instead.
+func (m *Mesh) Properties() Properties {
+	p := Properties{
+		Bounds:        m.Bounds(),
+		TriangleCount: len(m.Triangles),
+		VertexCount:   len(m.Vertices),
+	}
+
+	var volumeMoment, areaMoment Vector
+	var signedVolume float64
+	for i := range m.Triangles {
+		v1, v2, v3 := m.Corners(i)
+
+		// signed volume of the tetrahedron formed by the triangle and the origin
+		tetVolume := v1.Dot(v2.Cross(v3)) / 6
+		signedVolume += tetVolume
+		volumeMoment = volumeMoment.Add(v1.Add(v2).Add(v3).MulScalar(tetVolume / 4))
+
+		area := v2.Sub(v1).Cross(v3.Sub(v1)).Length() / 2
+		p.SurfaceArea += area
+		areaMoment = areaMoment.Add(v1.Add(v2).Add(v3).MulScalar(area / 3))
+	}
+
+	p.Volume = math.Abs(signedVolume)
+
+	switch {
+	case p.Volume > 0:
+		p.Centroid = volumeMoment.DivScalar(signedVolume)
+	case p.SurfaceArea > 0:
+		p.Centroid = areaMoment.DivScalar(p.SurfaceArea)
+	}
+
+	return p
+}
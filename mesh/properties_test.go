@@ -0,0 +1,41 @@
+package mesh_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestProperties(t *testing.T) {
+	m, err := mesh.ReadSTL(strings.NewReader(asciiTetrahedron))
+	require.NoError(t, err)
+
+	p := m.Properties()
+
+	assert.InDelta(t, 1.0/6, p.Volume, 1e-9)
+	assert.InDelta(t, 1.5+math.Sqrt(3)/2, p.SurfaceArea, 1e-9)
+	assert.Equal(t, 4, p.TriangleCount)
+	assert.Equal(t, 4, p.VertexCount)
+	assert.InDelta(t, 0.25, p.Centroid.X, 1e-9)
+	assert.InDelta(t, 0.25, p.Centroid.Y, 1e-9)
+	assert.InDelta(t, 0.25, p.Centroid.Z, 1e-9)
+}
+
+func TestPropertiesOpenSurface(t *testing.T) {
+	m := &mesh.Mesh{
+		Vertices:  []mesh.Vector{{0, 0, 0}, {2, 0, 0}, {2, 2, 0}, {0, 2, 0}},
+		Triangles: []mesh.Triangle{{0, 1, 2}, {0, 2, 3}},
+	}
+
+	p := m.Properties()
+
+	assert.Equal(t, 0.0, p.Volume)
+	assert.InDelta(t, 4, p.SurfaceArea, 1e-9)
+	assert.InDelta(t, 1, p.Centroid.X, 1e-9)
+	assert.InDelta(t, 1, p.Centroid.Y, 1e-9)
+}
@@ -0,0 +1,184 @@
+package mesh
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+const (
+	// renderSamples is the number of samples taken along each axis of a pixel to smooth the edges
+	// of the rendered triangles
+	renderSamples = 2
+	// renderMargin is the fraction of the image left empty on each side of the mesh
+	renderMargin = 0.05
+	// renderAmbient is the amount of light that reaches faces that point away from the light
+	renderAmbient = 0.3
+)
+
+// renderColor is the color of meshes that do not have vertex colors
+var renderColor = Color{111, 159, 216, 255}
+
+// Render draws a shaded isometric view of the mesh into a square image with a transparent
+// background. Faces are lit from both sides so that meshes with inconsistent winding still render.
+func (m *Mesh) Render(size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	if size <= 0 || len(m.Triangles) == 0 {
+		return img
+	}
+
+	// the camera looks down at the mesh from the front right corner with Z pointing up
+	forward := Vector{-1, 1, -1}.Normalize()
+	right := forward.Cross(Vector{0, 0, 1}).Normalize()
+	up := right.Cross(forward)
+	light := right.MulScalar(-0.3).Add(up.MulScalar(0.6)).Sub(forward).Normalize()
+
+	// project every vertex into the view plane and fit the result into the image
+	projected := make([]Vector, len(m.Vertices))
+	var bounds Box
+	for i, v := range m.Vertices {
+		p := Vector{v.Dot(right), v.Dot(up), -v.Dot(forward)}
+		projected[i] = p
+		if i == 0 {
+			bounds = Box{p, p}
+		}
+		bounds.Min = bounds.Min.Min(p)
+		bounds.Max = bounds.Max.Max(p)
+	}
+
+	s := size * renderSamples
+	extent := math.Max(bounds.Size().X, bounds.Size().Y)
+	scale := 1.0
+	if extent > 0 {
+		scale = float64(s) * (1 - 2*renderMargin) / extent
+	}
+	center := bounds.Min.Add(bounds.Max).DivScalar(2)
+	for i, p := range projected {
+		projected[i] = Vector{
+			X: float64(s)/2 + (p.X-center.X)*scale,
+			Y: float64(s)/2 - (p.Y-center.Y)*scale,
+			Z: p.Z,
+		}
+	}
+
+	r := &rasterizer{
+		size:   s,
+		depth:  make([]float64, s*s),
+		colors: make([]Color, s*s),
+	}
+	for i := range r.depth {
+		r.depth[i] = math.Inf(-1)
+	}
+
+	hasColors := m.HasColors()
+	for i, t := range m.Triangles {
+		shade := renderAmbient + (1-renderAmbient)*math.Abs(m.Normal(i).Dot(light))
+
+		var corners [3]Color
+		for j, v := range t {
+			corners[j] = renderColor
+			if hasColors {
+				corners[j] = m.Colors[v]
+			}
+		}
+
+		r.triangle(projected[t[0]], projected[t[1]], projected[t[2]], corners, shade)
+	}
+
+	r.downsample(img)
+	return img
+}
+
+// rasterizer draws triangles into a supersampled color buffer with a depth buffer
+type rasterizer struct {
+	size   int
+	depth  []float64
+	colors []Color
+}
+
+func (r *rasterizer) triangle(a, b, c Vector, corners [3]Color, shade float64) {
+	area := edgeFunction(a, b, c)
+	if area == 0 {
+		return
+	}
+
+	minX := clamp(int(math.Floor(math.Min(a.X, math.Min(b.X, c.X)))), 0, r.size-1)
+	maxX := clamp(int(math.Ceil(math.Max(a.X, math.Max(b.X, c.X)))), 0, r.size-1)
+	minY := clamp(int(math.Floor(math.Min(a.Y, math.Min(b.Y, c.Y)))), 0, r.size-1)
+	maxY := clamp(int(math.Ceil(math.Max(a.Y, math.Max(b.Y, c.Y)))), 0, r.size-1)
+
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			p := Vector{float64(x) + 0.5, float64(y) + 0.5, 0}
+
+			// barycentric weights are all positive inside the triangle whichever way it is wound
+			w0 := edgeFunction(b, c, p) / area
+			w1 := edgeFunction(c, a, p) / area
+			w2 := edgeFunction(a, b, p) / area
+			if w0 < 0 || w1 < 0 || w2 < 0 {
+				continue
+			}
+
+			i := y*r.size + x
+			z := w0*a.Z + w1*b.Z + w2*c.Z
+			if z <= r.depth[i] {
+				continue
+			}
+			r.depth[i] = z
+
+			r.colors[i] = Color{
+				R: shadeChannel(w0, w1, w2, corners[0].R, corners[1].R, corners[2].R, shade),
+				G: shadeChannel(w0, w1, w2, corners[0].G, corners[1].G, corners[2].G, shade),
+				B: shadeChannel(w0, w1, w2, corners[0].B, corners[1].B, corners[2].B, shade),
+				A: 255,
+			}
+		}
+	}
+}
+
+// downsample averages the samples of each pixel into the image
+func (r *rasterizer) downsample(img *image.RGBA) {
+	size := r.size / renderSamples
+	n := uint32(renderSamples * renderSamples)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			var red, green, blue, alpha uint32
+			for sy := 0; sy < renderSamples; sy++ {
+				for sx := 0; sx < renderSamples; sx++ {
+					c := r.colors[(y*renderSamples+sy)*r.size+x*renderSamples+sx]
+					if c.A == 0 {
+						continue
+					}
+					red += uint32(c.R)
+					green += uint32(c.G)
+					blue += uint32(c.B)
+					alpha += uint32(c.A)
+				}
+			}
+
+			// empty samples are transparent black so the sums are already premultiplied
+			img.SetRGBA(x, y, color.RGBA{uint8(red / n), uint8(green / n), uint8(blue / n), uint8(alpha / n)})
+		}
+	}
+}
+
+// edgeFunction returns twice the signed area of the triangle a, b, p in the XY plane
+func edgeFunction(a, b, p Vector) float64 {
+	return (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+}
+
+func shadeChannel(w0, w1, w2 float64, c0, c1, c2 uint8, shade float64) uint8 {
+	v := (w0*float64(c0) + w1*float64(c1) + w2*float64(c2)) * shade
+	return uint8(math.Min(255, math.Max(0, math.Round(v))))
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
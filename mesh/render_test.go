@@ -0,0 +1,36 @@
+package mesh_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestRender(t *testing.T) {
+	img := cube(10).Render(64)
+
+	assert.Equal(t, 64, img.Bounds().Dx())
+	assert.Equal(t, 64, img.Bounds().Dy())
+
+	// the background is transparent and the mesh is drawn in the middle of the image
+	assert.Equal(t, uint8(0), img.RGBAAt(0, 0).A)
+	assert.Equal(t, uint8(255), img.RGBAAt(32, 32).A)
+
+	// the top of the cube faces the light more than its sides so it is shaded differently
+	assert.NotEqual(t, img.RGBAAt(32, 12), img.RGBAAt(32, 48))
+}
+
+func TestRenderColors(t *testing.T) {
+	m := cube(10)
+	for range m.Vertices {
+		m.Colors = append(m.Colors, mesh.Color{R: 255, A: 255})
+	}
+
+	c := m.Render(32).RGBAAt(16, 16)
+
+	assert.NotZero(t, c.R)
+	assert.Zero(t, c.G)
+	assert.Zero(t, c.B)
+}
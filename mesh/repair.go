@@ -0,0 +1,273 @@
+package mesh
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// weldTolerance is the distance, relative to the size of the mesh, within which two vertices
+	// are considered to be the same vertex
+	weldTolerance = 1e-6
+	// maxHoleSize is the largest number of edges a hole can have to be filled. Larger openings are
+	// usually intentional or too complex to patch with a flat fill.
+	maxHoleSize = 256
+)
+
+// Repair returns a fixed copy of the mesh. It welds duplicate vertices, removes degenerate and
+// duplicate triangles, unifies the winding of each shell so that it faces outwards and fills simple
+// holes. Face normals are derived from the winding, so they are correct once the winding is.
+func (m *Mesh) Repair() *Mesh {
+	r := m.weld()
+	r.removeDegenerateTriangles()
+	r.unifyWinding()
+	r.fillHoles()
+	r.orientOutwards()
+	return r
+}
+
+// weld merges vertices that are closer than the weld tolerance. Vertices are bucketed into a grid
+// with cells the size of the tolerance so only the neighboring cells need to be searched.
+func (m *Mesh) weld() *Mesh {
+	size := m.Bounds().Size()
+	tolerance := weldTolerance * size.Length()
+	if tolerance == 0 {
+		tolerance = weldTolerance
+	}
+
+	r := &Mesh{}
+	hasColors := m.HasColors()
+	cells := make(map[[3]int64][]int)
+	remap := make([]int, len(m.Vertices))
+
+	for i, v := range m.Vertices {
+		cell := [3]int64{
+			int64(math.Floor(v.X / tolerance)),
+			int64(math.Floor(v.Y / tolerance)),
+			int64(math.Floor(v.Z / tolerance)),
+		}
+
+		match := -1
+	search:
+		for dx := int64(-1); dx <= 1; dx++ {
+			for dy := int64(-1); dy <= 1; dy++ {
+				for dz := int64(-1); dz <= 1; dz++ {
+					for _, j := range cells[[3]int64{cell[0] + dx, cell[1] + dy, cell[2] + dz}] {
+						if r.Vertices[j].Sub(v).Length() <= tolerance {
+							match = j
+							break search
+						}
+					}
+				}
+			}
+		}
+
+		if match == -1 {
+			match = len(r.Vertices)
+			r.Vertices = append(r.Vertices, v)
+			if hasColors {
+				r.Colors = append(r.Colors, m.Colors[i])
+			}
+			cells[cell] = append(cells[cell], match)
+		}
+		remap[i] = match
+	}
+
+	r.Triangles = make([]Triangle, len(m.Triangles))
+	for i, t := range m.Triangles {
+		r.Triangles[i] = Triangle{remap[t[0]], remap[t[1]], remap[t[2]]}
+	}
+	return r
+}
+
+func (m *Mesh) removeDegenerateTriangles() {
+	t := newTopology(m)
+	size := m.Bounds().Size()
+	tolerance := degenerateAreaTolerance * size.Dot(size)
+
+	seen := make(map[Triangle]bool)
+	kept := m.Triangles[:0]
+	for i, tri := range m.Triangles {
+		if t.isCollapsed(m, i) {
+			continue
+		}
+
+		v1, v2, v3 := m.Corners(i)
+		if v2.Sub(v1).Cross(v3.Sub(v1)).Length()/2 <= tolerance {
+			continue
+		}
+
+		key := tri
+		sort.Ints(key[:])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		kept = append(kept, tri)
+	}
+	m.Triangles = kept
+}
+
+// unifyWinding flips triangles so that every shell is wound consistently with its first triangle
+func (m *Mesh) unifyWinding() {
+	t := newTopology(m)
+	neighbors := t.neighbors(len(m.Triangles))
+	visited := make([]bool, len(m.Triangles))
+
+	for _, shell := range m.Shells() {
+		visited[shell[0]] = true
+		stack := []int{shell[0]}
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			for _, n := range neighbors[i] {
+				if visited[n.triangle] {
+					continue
+				}
+				visited[n.triangle] = true
+
+				// flipping a triangle changes whether it is consistent with all of its neighbors
+				if !n.consistent {
+					m.flip(n.triangle)
+					for _, nn := range neighbors[n.triangle] {
+						flipConsistency(neighbors, n.triangle, nn.triangle)
+					}
+				}
+				stack = append(stack, n.triangle)
+			}
+		}
+	}
+}
+
+// orientOutwards flips every shell that encloses a negative volume so that it faces outwards
+func (m *Mesh) orientOutwards() {
+	for _, shell := range m.Shells() {
+		var volume float64
+		for _, i := range shell {
+			v1, v2, v3 := m.Corners(i)
+			volume += v1.Dot(v2.Cross(v3)) / 6
+		}
+		if volume < 0 {
+			for _, i := range shell {
+				m.flip(i)
+			}
+		}
+	}
+}
+
+func (m *Mesh) flip(i int) {
+	t := m.Triangles[i]
+	m.Triangles[i] = Triangle{t[0], t[2], t[1]}
+}
+
+// flipConsistency updates the neighbor relation between a and b in both directions after one of
+// them has been flipped
+func flipConsistency(neighbors [][]neighbor, a, b int) {
+	for _, pair := range [][2]int{{a, b}, {b, a}} {
+		list := neighbors[pair[0]]
+		for k := range list {
+			if list[k].triangle == pair[1] {
+				list[k].consistent = !list[k].consistent
+			}
+		}
+	}
+}
+
+// fillHoles closes boundary loops that visit each of their vertices once by triangulating them.
+// Flat sheets are left open since their whole outline is a boundary and filling it would only add
+// a second face on top of the first.
+func (m *Mesh) fillHoles() {
+	t := newTopology(m)
+
+	shellOf := make([]int, len(m.Triangles))
+	shells := m.Shells()
+	for s, shell := range shells {
+		for _, i := range shell {
+			shellOf[i] = s
+		}
+	}
+
+	// a boundary edge a->b of a triangle is traversed b->a by the patch that fills the hole
+	next := make(map[int]int)
+	triangleAt := make(map[int]int)
+	branching := make(map[int]bool)
+	for e, uses := range t.edges {
+		if len(uses) != 1 {
+			continue
+		}
+		a, b := e[0], e[1]
+		if !uses[0].forward {
+			a, b = b, a
+		}
+		if _, ok := next[b]; ok {
+			branching[b] = true
+		}
+		next[b] = a
+		triangleAt[b] = uses[0].triangle
+	}
+
+	starts := make([]int, 0, len(next))
+	for v := range next {
+		starts = append(starts, v)
+	}
+	sort.Ints(starts)
+
+	visited := make(map[int]bool)
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+
+		loop := []int{start}
+		visited[start] = true
+		simple := !branching[start]
+		v, ok := next[start]
+		for ok && v != start {
+			if visited[v] || branching[v] {
+				simple = false
+				break
+			}
+			visited[v] = true
+			loop = append(loop, v)
+			v, ok = next[v]
+		}
+
+		if !ok || !simple || len(loop) < 3 || len(loop) > maxHoleSize {
+			continue
+		}
+		if m.isFlat(shells[shellOf[triangleAt[start]]], loop) {
+			continue
+		}
+		m.fillLoop(loop)
+	}
+}
+
+// isFlat reports whether all of the triangles of a shell lie in the plane of a loop
+func (m *Mesh) isFlat(shell []int, loop []int) bool {
+	normal := newellNormal(m.Vertices, loop).Normalize()
+	origin := m.Vertices[loop[0]]
+	size := m.Bounds().Size()
+	tolerance := weldTolerance * size.Length()
+
+	for _, i := range shell {
+		for _, v := range m.Triangles[i] {
+			if math.Abs(m.Vertices[v].Sub(origin).Dot(normal)) > tolerance {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (m *Mesh) fillLoop(loop []int) {
+	normal := newellNormal(m.Vertices, loop)
+	for _, tri := range triangulate(m.Vertices, loop) {
+		m.Triangles = append(m.Triangles, tri)
+		// the patch has to follow the direction of the loop to match the surrounding triangles
+		if m.Normal(len(m.Triangles)-1).Dot(normal) < 0 {
+			m.flip(len(m.Triangles) - 1)
+		}
+	}
+}
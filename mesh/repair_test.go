@@ -0,0 +1,87 @@
+package mesh_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// cube returns a closed, consistently wound cube with its minimum corner at the origin
+func cube(size float64) *mesh.Mesh {
+	m := &mesh.Mesh{}
+	for i := 0; i < 8; i++ {
+		m.Vertices = append(m.Vertices, mesh.Vector{
+			X: float64(i&1) * size,
+			Y: float64(i>>1&1) * size,
+			Z: float64(i>>2&1) * size,
+		})
+	}
+	m.Triangles = []mesh.Triangle{
+		{0, 2, 1}, {1, 2, 3}, // bottom
+		{4, 5, 6}, {5, 7, 6}, // top
+		{0, 1, 4}, {1, 5, 4}, // front
+		{2, 6, 3}, {3, 6, 7}, // back
+		{0, 4, 2}, {2, 4, 6}, // left
+		{1, 3, 5}, {3, 7, 5}, // right
+	}
+	return m
+}
+
+func TestRepair(t *testing.T) {
+	broken := cube(10)
+
+	// split the vertices of one triangle with a small offset, as exporters often do
+	n := len(broken.Vertices)
+	v1, v2, v3 := broken.Corners(0)
+	broken.Vertices = append(broken.Vertices,
+		v1.Add(mesh.Vector{X: 1e-7}), v2.Add(mesh.Vector{Y: 1e-7}), v3)
+	broken.Triangles[0] = mesh.Triangle{n, n + 1, n + 2}
+
+	// flip a triangle, remove one, then add a duplicate and a degenerate triangle
+	broken.Triangles[4] = mesh.Triangle{0, 4, 1}
+	broken.Triangles = append(broken.Triangles[:11], mesh.Triangle{4, 5, 6}, mesh.Triangle{1, 1, 2})
+
+	before := broken.Analyze()
+	require.False(t, before.Watertight)
+	require.NotZero(t, before.Holes)
+
+	repaired := broken.Repair()
+	after := repaired.Analyze()
+
+	assert.Equal(t, mesh.Analysis{Shells: 1, Watertight: true}, after)
+	assert.Len(t, repaired.Vertices, 8)
+	assert.Len(t, repaired.Triangles, 12)
+	assert.InDelta(t, 1000, repaired.Properties().Volume, 1e-3)
+}
+
+func TestRepairOrientsInsideOutShells(t *testing.T) {
+	m := cube(1)
+	for i, tri := range m.Triangles {
+		m.Triangles[i] = mesh.Triangle{tri[0], tri[2], tri[1]}
+	}
+
+	repaired := m.Repair()
+
+	// the signed volume is only positive when every triangle faces outwards
+	var signed float64
+	for i := range repaired.Triangles {
+		v1, v2, v3 := repaired.Corners(i)
+		signed += v1.Dot(v2.Cross(v3)) / 6
+	}
+	assert.InDelta(t, 1, signed, 1e-9)
+}
+
+func TestRepairLeavesFlatSheetsOpen(t *testing.T) {
+	m := &mesh.Mesh{
+		Vertices:  []mesh.Vector{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+		Triangles: []mesh.Triangle{{0, 1, 2}, {0, 2, 3}},
+	}
+
+	repaired := m.Repair()
+
+	assert.Len(t, repaired.Triangles, 2)
+	assert.Equal(t, 1, repaired.Analyze().Holes)
+}
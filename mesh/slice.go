@@ -0,0 +1,315 @@
+package mesh
+
+import (
+	"math"
+	"sort"
+)
+
+// collinearTolerance is the sine of the angle below which three points are considered to be on a
+// line
+const collinearTolerance = 1e-9
+
+// Vector2 is a point in the plane of a slice
+type Vector2 struct {
+	X, Y float64
+}
+
+// Polygon is a connected region of a slice. Its outline is counter-clockwise and its holes are
+// clockwise when viewed from above. Islands inside of a hole are separate polygons.
+type Polygon struct {
+	Outline []Vector2
+	Holes   [][]Vector2
+}
+
+// Layer is the cross section of a mesh with a horizontal plane
+type Layer struct {
+	Z        float64
+	Polygons []Polygon
+}
+
+// Slice intersects the mesh with the horizontal plane at height z
+func (m *Mesh) Slice(z float64) Layer {
+	t := newTopology(m)
+	triangles := make([]int, len(m.Triangles))
+	for i := range triangles {
+		triangles[i] = i
+	}
+	return m.slice(t, triangles, z)
+}
+
+// Slices cuts the mesh into layers of the given height from the bottom of its bounding box. Each
+// layer is sliced through its middle, as slicers for 3D printers do.
+func (m *Mesh) Slices(layerHeight float64) []Layer {
+	if layerHeight <= 0 || len(m.Triangles) == 0 {
+		return nil
+	}
+
+	t := newTopology(m)
+	bounds := m.Bounds()
+
+	// sweep the planes upwards over the triangles sorted by their lowest corner so that each plane
+	// only has to be tested against the triangles that span its height
+	order := make([]int, len(m.Triangles))
+	low := make([]float64, len(m.Triangles))
+	high := make([]float64, len(m.Triangles))
+	for i := range m.Triangles {
+		order[i] = i
+		v1, v2, v3 := m.Corners(i)
+		low[i] = math.Min(v1.Z, math.Min(v2.Z, v3.Z))
+		high[i] = math.Max(v1.Z, math.Max(v2.Z, v3.Z))
+	}
+	sort.Slice(order, func(a, b int) bool { return low[order[a]] < low[order[b]] })
+
+	var layers []Layer
+	var active []int
+	next := 0
+	for k := 0; ; k++ {
+		z := bounds.Min.Z + (float64(k)+0.5)*layerHeight
+		if z > bounds.Max.Z {
+			break
+		}
+
+		for next < len(order) && low[order[next]] <= z {
+			active = append(active, order[next])
+			next++
+		}
+		kept := active[:0]
+		for _, i := range active {
+			if high[i] >= z {
+				kept = append(kept, i)
+			}
+		}
+		active = kept
+
+		layers = append(layers, m.slice(t, active, z))
+	}
+	return layers
+}
+
+// segment is the intersection of a triangle with a plane. Its ends lie on two edges of the
+// triangle, which connect it to the segments of the neighboring triangles.
+type segment struct {
+	edges  [2]edge
+	points [2]Vector2
+}
+
+func (m *Mesh) slice(t *topology, triangles []int, z float64) Layer {
+	var segments []segment
+	links := make(map[edge][]int)
+
+	for _, i := range triangles {
+		if t.isCollapsed(m, i) {
+			continue
+		}
+		c := t.corners(m, i)
+
+		// corners that lie exactly on the plane count as above it so that every triangle that
+		// crosses the plane is cut along exactly two of its edges
+		var s segment
+		n := 0
+		for j := 0; j < 3; j++ {
+			a, b := c[j], c[(j+1)%3]
+			if (m.Vertices[a].Z >= z) == (m.Vertices[b].Z >= z) {
+				continue
+			}
+			s.edges[n] = newEdge(a, b)
+			s.points[n] = m.intersectEdge(s.edges[n], z)
+			n++
+		}
+		if n != 2 {
+			continue
+		}
+
+		for _, e := range s.edges {
+			links[e] = append(links[e], len(segments))
+		}
+		segments = append(segments, s)
+	}
+
+	return Layer{Z: z, Polygons: nestLoops(stitch(segments, links))}
+}
+
+// intersectEdge returns the point where an edge crosses the plane at height z. The point is always
+// computed from the lower vertex index so that both triangles of the edge get the same point.
+func (m *Mesh) intersectEdge(e edge, z float64) Vector2 {
+	a, b := m.Vertices[e[0]], m.Vertices[e[1]]
+	t := (z - a.Z) / (b.Z - a.Z)
+	return Vector2{a.X + (b.X-a.X)*t, a.Y + (b.Y-a.Y)*t}
+}
+
+// stitch joins segments that share an edge into loops. Chains that do not close, which happens
+// when the mesh has holes, are closed with a straight line.
+func stitch(segments []segment, links map[edge][]int) [][]Vector2 {
+	visited := make([]bool, len(segments))
+
+	// start with the ends of open chains so that they are followed from one end to the other
+	type start struct {
+		segment int
+		end     int
+	}
+	var starts []start
+	for i, s := range segments {
+		for end, e := range s.edges {
+			if len(links[e]) == 1 {
+				starts = append(starts, start{i, end})
+			}
+		}
+	}
+	for i := range segments {
+		starts = append(starts, start{i, 0})
+	}
+
+	var loops [][]Vector2
+	for _, st := range starts {
+		if visited[st.segment] {
+			continue
+		}
+
+		current, end := st.segment, st.end
+		first := segments[current].edges[end]
+		loop := []Vector2{segments[current].points[end]}
+		for {
+			visited[current] = true
+			exit := 1 - end
+			e := segments[current].edges[exit]
+
+			next := -1
+			for _, candidate := range links[e] {
+				if !visited[candidate] {
+					next = candidate
+					break
+				}
+			}
+			if next == -1 {
+				if e != first {
+					loop = append(loop, segments[current].points[exit])
+				}
+				break
+			}
+
+			loop = append(loop, segments[current].points[exit])
+			current = next
+			end = 0
+			if segments[next].edges[0] != e {
+				end = 1
+			}
+		}
+
+		loop = removeCollinearPoints(removeDuplicatePoints(loop))
+		if len(loop) >= 3 && polygonArea(loop) != 0 {
+			loops = append(loops, loop)
+		}
+	}
+	return loops
+}
+
+func removeDuplicatePoints(loop []Vector2) []Vector2 {
+	kept := loop[:0]
+	for _, p := range loop {
+		if len(kept) > 0 && kept[len(kept)-1] == p {
+			continue
+		}
+		kept = append(kept, p)
+	}
+	for len(kept) > 1 && kept[0] == kept[len(kept)-1] {
+		kept = kept[:len(kept)-1]
+	}
+	return kept
+}
+
+// removeCollinearPoints drops the points of a loop that lie on the line between their neighbors,
+// which are left behind where the plane crosses the diagonals of flat faces
+func removeCollinearPoints(loop []Vector2) []Vector2 {
+	for removed := true; removed && len(loop) >= 3; {
+		removed = false
+		kept := make([]Vector2, 0, len(loop))
+		for i, p := range loop {
+			prev := loop[(i+len(loop)-1)%len(loop)]
+			if len(kept) > 0 {
+				prev = kept[len(kept)-1]
+			}
+			next := loop[(i+1)%len(loop)]
+
+			a := Vector2{p.X - prev.X, p.Y - prev.Y}
+			b := Vector2{next.X - p.X, next.Y - p.Y}
+			cross := a.X*b.Y - a.Y*b.X
+			if math.Abs(cross) <= collinearTolerance*math.Hypot(a.X, a.Y)*math.Hypot(b.X, b.Y) && a.X*b.X+a.Y*b.Y > 0 {
+				removed = true
+				continue
+			}
+			kept = append(kept, p)
+		}
+		loop = kept
+	}
+	return loop
+}
+
+// nestLoops sorts loops into polygons by how deeply they are nested. Loops inside an even number of
+// other loops are outlines and the rest are holes of the loop that directly contains them.
+func nestLoops(loops [][]Vector2) []Polygon {
+	// a loop can only be inside of a larger loop
+	sort.Slice(loops, func(a, b int) bool {
+		return math.Abs(polygonArea(loops[a])) > math.Abs(polygonArea(loops[b]))
+	})
+
+	parent := make([]int, len(loops))
+	depth := make([]int, len(loops))
+	for i := range loops {
+		parent[i] = -1
+		for j := i - 1; j >= 0; j-- {
+			if pointInPolygon(loops[i][0], loops[j]) {
+				parent[i] = j
+				depth[i] = depth[j] + 1
+				break
+			}
+		}
+	}
+
+	var polygons []Polygon
+	index := make(map[int]int)
+	for i, loop := range loops {
+		if depth[i]%2 == 0 {
+			if polygonArea(loop) < 0 {
+				reversePoints(loop)
+			}
+			index[i] = len(polygons)
+			polygons = append(polygons, Polygon{Outline: loop})
+			continue
+		}
+
+		if polygonArea(loop) > 0 {
+			reversePoints(loop)
+		}
+		p := &polygons[index[parent[i]]]
+		p.Holes = append(p.Holes, loop)
+	}
+	return polygons
+}
+
+// polygonArea returns the signed area of a polygon, which is positive when it is counter-clockwise
+func polygonArea(points []Vector2) float64 {
+	var area float64
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	return area / 2
+}
+
+// pointInPolygon tests whether a point is inside of a polygon with the even-odd rule
+func pointInPolygon(p Vector2, polygon []Vector2) bool {
+	inside := false
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func reversePoints(points []Vector2) {
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+}
@@ -0,0 +1,92 @@
+package mesh_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// box returns a closed box between two corners. Inverted boxes face inwards, which is how a cavity
+// inside of another box is modeled.
+func box(min, max mesh.Vector, inverted bool) *mesh.Mesh {
+	m := cube(1)
+	size := max.Sub(min)
+	for i, v := range m.Vertices {
+		m.Vertices[i] = mesh.Vector{X: min.X + v.X*size.X, Y: min.Y + v.Y*size.Y, Z: min.Z + v.Z*size.Z}
+	}
+	if inverted {
+		for i, t := range m.Triangles {
+			m.Triangles[i] = mesh.Triangle{t[0], t[2], t[1]}
+		}
+	}
+	return m
+}
+
+func area(points []mesh.Vector2) float64 {
+	var a float64
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+	return a / 2
+}
+
+func TestSlice(t *testing.T) {
+	layer := cube(10).Slice(5)
+
+	assert.Equal(t, 5.0, layer.Z)
+	require.Len(t, layer.Polygons, 1)
+	assert.Len(t, layer.Polygons[0].Outline, 4)
+	assert.InDelta(t, 100, area(layer.Polygons[0].Outline), 1e-9)
+	assert.Empty(t, layer.Polygons[0].Holes)
+}
+
+func TestSliceThroughVertices(t *testing.T) {
+	// the plane passes through the top face of the cube
+	layer := cube(10).Slice(10)
+
+	require.Len(t, layer.Polygons, 1)
+	assert.InDelta(t, 100, area(layer.Polygons[0].Outline), 1e-9)
+}
+
+func TestSliceNesting(t *testing.T) {
+	// a box with a cavity that has an island inside of it
+	m := box(mesh.Vector{}, mesh.Vector{X: 10, Y: 10, Z: 10}, false)
+	m.Append(box(mesh.Vector{X: 2, Y: 2, Z: 2}, mesh.Vector{X: 8, Y: 8, Z: 8}, true))
+	m.Append(box(mesh.Vector{X: 4, Y: 4, Z: 4}, mesh.Vector{X: 6, Y: 6, Z: 6}, false))
+
+	layer := m.Slice(5)
+
+	require.Len(t, layer.Polygons, 2)
+	outer, island := layer.Polygons[0], layer.Polygons[1]
+	assert.InDelta(t, 100, area(outer.Outline), 1e-9)
+	require.Len(t, outer.Holes, 1)
+	assert.InDelta(t, -36, area(outer.Holes[0]), 1e-9)
+	assert.InDelta(t, 4, area(island.Outline), 1e-9)
+	assert.Empty(t, island.Holes)
+}
+
+func TestSlices(t *testing.T) {
+	layers := cube(10).Slices(2)
+
+	require.Len(t, layers, 5)
+	assert.Equal(t, 1.0, layers[0].Z)
+	assert.Equal(t, 9.0, layers[4].Z)
+	for _, l := range layers {
+		assert.Len(t, l.Polygons, 1)
+	}
+}
+
+func TestSliceOpenMesh(t *testing.T) {
+	// a cube without its right side still slices into a closed outline
+	m := cube(10)
+	m.Triangles = m.Triangles[:10]
+
+	layer := m.Slice(5)
+
+	require.Len(t, layer.Polygons, 1)
+	assert.InDelta(t, 100, area(layer.Polygons[0].Outline), 1e-9)
+}
@@ -0,0 +1,213 @@
+package mesh
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+)
+
+const (
+	stlHeaderSize   = 80
+	stlTriangleSize = 50
+)
+
+// ReadSTL parses a binary or ASCII STL file into a mesh
+func ReadSTL(r io.Reader) (*Mesh, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBinarySTL(data) {
+		return readBinarySTL(data)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return readASCIISTL(data)
+	}
+
+	return nil, ErrInvalidFormat
+}
+
+// isBinarySTL checks whether the triangle count in the binary header matches the size of the file.
+// Some binary exporters start their header with the word "solid" so the ASCII keyword can not be
+// relied on to tell the two encodings apart
+func isBinarySTL(data []byte) bool {
+	if len(data) < stlHeaderSize+4 {
+		return false
+	}
+
+	count := binary.LittleEndian.Uint32(data[stlHeaderSize:])
+	return uint64(len(data)) == stlHeaderSize+4+uint64(count)*stlTriangleSize
+}
+
+func readBinarySTL(data []byte) (*Mesh, error) {
+	count := int(binary.LittleEndian.Uint32(data[stlHeaderSize:]))
+	b := newBuilder()
+
+	offset := stlHeaderSize + 4
+	for i := 0; i < count; i++ {
+		// skip the 12 byte facet normal since it is recomputed from the vertices when needed
+		p := offset + 12
+
+		var corners [3]Vector
+		for j := range corners {
+			v, ok := readBinaryVector(data[p+j*12:])
+			if !ok {
+				return nil, ErrInvalidFormat
+			}
+			corners[j] = v
+		}
+		b.triangle(corners[0], corners[1], corners[2])
+
+		offset += stlTriangleSize
+	}
+
+	return b.mesh, nil
+}
+
+func readBinaryVector(data []byte) (Vector, bool) {
+	v := Vector{
+		X: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[0:]))),
+		Y: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[4:]))),
+		Z: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[8:]))),
+	}
+	return v, isFinite(v)
+}
+
+func readASCIISTL(data []byte) (*Mesh, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+	scanner.Split(bufio.ScanWords)
+
+	b := newBuilder()
+	var corners []Vector
+	inFacet := false
+
+	for scanner.Scan() {
+		switch scanner.Text() {
+		case "facet":
+			if inFacet {
+				return nil, ErrInvalidFormat
+			}
+			inFacet = true
+			corners = corners[:0]
+		case "vertex":
+			if !inFacet {
+				return nil, ErrInvalidFormat
+			}
+			v, err := scanASCIIVector(scanner)
+			if err != nil {
+				return nil, err
+			}
+			corners = append(corners, v)
+		case "endfacet":
+			if !inFacet || len(corners) != 3 {
+				return nil, ErrInvalidFormat
+			}
+			b.triangle(corners[0], corners[1], corners[2])
+			inFacet = false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if inFacet {
+		return nil, ErrInvalidFormat
+	}
+
+	return b.mesh, nil
+}
+
+func scanASCIIVector(scanner *bufio.Scanner) (Vector, error) {
+	var coords [3]float64
+	for i := range coords {
+		if !scanner.Scan() {
+			return Vector{}, ErrInvalidFormat
+		}
+
+		f, err := strconv.ParseFloat(scanner.Text(), 64)
+		if err != nil {
+			return Vector{}, ErrInvalidFormat
+		}
+		coords[i] = f
+	}
+
+	v := Vector{coords[0], coords[1], coords[2]}
+	if !isFinite(v) {
+		return Vector{}, ErrInvalidFormat
+	}
+	return v, nil
+}
+
+func isFinite(v Vector) bool {
+	for _, f := range []float64{v.X, v.Y, v.Z} {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteSTL encodes the mesh as a binary STL file
+func WriteSTL(w io.Writer, m *Mesh) error {
+	bw := bufio.NewWriter(w)
+
+	header := make([]byte, stlHeaderSize+4)
+	copy(header, "rkmesh")
+	binary.LittleEndian.PutUint32(header[stlHeaderSize:], uint32(len(m.Triangles)))
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	buf := make([]byte, stlTriangleSize)
+	for i := range m.Triangles {
+		v1, v2, v3 := m.Corners(i)
+		for j, v := range []Vector{m.Normal(i), v1, v2, v3} {
+			binary.LittleEndian.PutUint32(buf[j*12:], math.Float32bits(float32(v.X)))
+			binary.LittleEndian.PutUint32(buf[j*12+4:], math.Float32bits(float32(v.Y)))
+			binary.LittleEndian.PutUint32(buf[j*12+8:], math.Float32bits(float32(v.Z)))
+		}
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// WriteASCIISTL encodes the mesh as an ASCII STL file
+func WriteASCIISTL(w io.Writer, m *Mesh) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "solid rkmesh")
+	for i := range m.Triangles {
+		n := m.Normal(i)
+		v1, v2, v3 := m.Corners(i)
+
+		fmt.Fprintf(bw, "  facet normal %s\n", formatVector(n))
+		fmt.Fprintln(bw, "    outer loop")
+		for _, v := range []Vector{v1, v2, v3} {
+			fmt.Fprintf(bw, "      vertex %s\n", formatVector(v))
+		}
+		fmt.Fprintln(bw, "    endloop")
+		fmt.Fprintln(bw, "  endfacet")
+	}
+	fmt.Fprintln(bw, "endsolid rkmesh")
+
+	return bw.Flush()
+}
+
+func formatVector(v Vector) string {
+	return formatFloat(v.X) + " " + formatFloat(v.Y) + " " + formatFloat(v.Z)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,116 @@
+package mesh_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+const asciiTetrahedron = `solid tetrahedron
+  facet normal 0 0 -1
+    outer loop
+      vertex 0 0 0
+      vertex 0 1 0
+      vertex 1 0 0
+    endloop
+  endfacet
+  facet normal 0 -1 0
+    outer loop
+      vertex 0 0 0
+      vertex 1 0 0
+      vertex 0 0 1
+    endloop
+  endfacet
+  facet normal -1 0 0
+    outer loop
+      vertex 0 0 0
+      vertex 0 0 1
+      vertex 0 1 0
+    endloop
+  endfacet
+  facet normal 1 1 1
+    outer loop
+      vertex 1 0 0
+      vertex 0 1 0
+      vertex 0 0 1
+    endloop
+  endfacet
+endsolid tetrahedron
+`
+
+func TestReadASCIISTL(t *testing.T) {
+	m, err := mesh.ReadSTL(strings.NewReader(asciiTetrahedron))
+	require.NoError(t, err)
+
+	assert.Len(t, m.Triangles, 4)
+	// shared corners are merged into a single vertex
+	assert.Len(t, m.Vertices, 4)
+	assert.Equal(t, mesh.Box{Max: mesh.Vector{X: 1, Y: 1, Z: 1}}, m.Bounds())
+}
+
+func TestReadBinarySTL(t *testing.T) {
+	ascii, err := mesh.ReadSTL(strings.NewReader(asciiTetrahedron))
+	require.NoError(t, err)
+
+	// a header starting with "solid" should not be mistaken for an ASCII file
+	data := binarySTL("solid but actually binary", ascii)
+
+	m, err := mesh.ReadSTL(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, ascii.Vertices, m.Vertices)
+	assert.Equal(t, ascii.Triangles, m.Triangles)
+}
+
+func TestReadSTLInvalid(t *testing.T) {
+	tests := map[string]string{
+		"not-an-stl":      "\xff\xd8\xff\xe0 JFIF this is a jpeg",
+		"missing-vertex":  "solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nendloop\nendfacet\nendsolid x",
+		"bad-coordinate":  "solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 zero\nendloop\nendfacet\nendsolid x",
+		"unclosed-facet":  "solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nvertex 0 1 0\n",
+		"nan-coordinate":  "solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 NaN\nvertex 1 0 0\nvertex 0 1 0\nendloop\nendfacet\nendsolid x",
+		"truncated-input": "",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := mesh.ReadSTL(strings.NewReader(input))
+			assert.Equal(t, mesh.ErrInvalidFormat, err)
+		})
+	}
+}
+
+func TestReadSTLEmpty(t *testing.T) {
+	m, err := mesh.ReadSTL(strings.NewReader("solid empty\nendsolid empty\n"))
+	require.NoError(t, err)
+	assert.Len(t, m.Triangles, 0)
+}
+
+// binarySTL encodes a mesh in the binary STL format
+func binarySTL(header string, m *mesh.Mesh) []byte {
+	b := new(bytes.Buffer)
+
+	h := make([]byte, 80)
+	copy(h, header)
+	b.Write(h)
+	binary.Write(b, binary.LittleEndian, uint32(len(m.Triangles)))
+
+	for i := range m.Triangles {
+		v1, v2, v3 := m.Corners(i)
+		for _, v := range []mesh.Vector{{}, v1, v2, v3} {
+			for _, f := range []float64{v.X, v.Y, v.Z} {
+				binary.Write(b, binary.LittleEndian, math.Float32bits(float32(f)))
+			}
+		}
+		binary.Write(b, binary.LittleEndian, uint16(0))
+	}
+
+	return b.Bytes()
+}
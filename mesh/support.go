@@ -0,0 +1,158 @@
+package mesh
+
+import (
+	"context"
+	"math"
+)
+
+// DefaultOverhangAngle is the steepest angle in degrees from vertical that most printers can print
+// without support
+const DefaultOverhangAngle = 45.0
+
+const (
+	// supportResolution is the number of support columns that fit along the diagonal of the bounds
+	// of a mesh
+	supportResolution = 100
+	// maxSupportDivisions is the most times that the sides of an overhanging face are divided into
+	// support columns
+	maxSupportDivisions = 16
+	// contactTolerance is how close to the build plate a face has to be to rest on it, as a fraction
+	// of the height of the mesh
+	contactTolerance = 1e-3
+	// maxContactAngle is the largest angle in degrees between the normal of a face and straight
+	// down at which it still lies flat on the build plate
+	maxContactAngle = 1.0
+)
+
+// SupportAnalysis describes the support that a mesh needs to print in a build direction
+type SupportAnalysis struct {
+	// Overhangs marks the triangles that need support, in the order of the triangles of the mesh
+	Overhangs []bool
+	// OverhangArea is the area of the triangles that need support
+	OverhangArea float64
+	// SupportVolume is the volume of the columns of support below the overhangs
+	SupportVolume float64
+	// ContactArea is the area where support touches the mesh, both below the overhangs and where
+	// columns land on the mesh instead of the build plate
+	ContactArea float64
+	// PlateArea is the area of the faces that lie flat on the build plate
+	PlateArea float64
+}
+
+// SupportVolume estimates the volume of support material that is needed to print the mesh in its
+// current orientation
+func (m *Mesh) SupportVolume(ctx context.Context, overhangAngle float64) (float64, error) {
+	a, err := m.AnalyzeSupport(ctx, Vector{Z: 1}, overhangAngle)
+	return a.SupportVolume, err
+}
+
+// AnalyzeSupport finds the triangles that face down more steeply than the overhang angle from
+// vertical when the mesh is built up along a direction. Every overhang is supported by columns that
+// reach down to whatever is below them, either the build plate or the mesh itself. Analyzing a
+// large mesh takes a while, so it stops with the error of the context once the context is done.
+func (m *Mesh) AnalyzeSupport(ctx context.Context, direction Vector, overhangAngle float64) (SupportAnalysis, error) {
+	return (&supportAnalyzer{mesh: m}).analyze(ctx, direction, overhangAngle)
+}
+
+// supportAnalyzer analyzes the support of a mesh in any number of build directions. The tree that
+// finds where support columns land is built once and shared by all of them.
+type supportAnalyzer struct {
+	mesh *Mesh
+	tree *bvh
+}
+
+func (s *supportAnalyzer) analyze(ctx context.Context, direction Vector, overhangAngle float64) (SupportAnalysis, error) {
+	m := s.mesh
+	res := SupportAnalysis{Overhangs: make([]bool, len(m.Triangles))}
+	if len(m.Triangles) == 0 || direction.Length() == 0 {
+		return res, nil
+	}
+
+	up := direction.Normalize()
+	down := up.MulScalar(-1)
+
+	floor, top := math.Inf(1), math.Inf(-1)
+	for _, v := range m.Vertices {
+		h := v.Dot(up)
+		floor = math.Min(floor, h)
+		top = math.Max(top, h)
+	}
+
+	bounds := m.Bounds().Size()
+	spacing := bounds.Length() / supportResolution
+	tolerance := contactTolerance * (top - floor)
+	threshold := -math.Sin(overhangAngle * math.Pi / 180)
+	flat := -math.Cos(maxContactAngle * math.Pi / 180)
+
+	for i := range m.Triangles {
+		v1, v2, v3 := m.Corners(i)
+		n := v2.Sub(v1).Cross(v3.Sub(v1))
+		length := n.Length()
+		if length == 0 {
+			continue
+		}
+		cos := n.Dot(up) / length
+		if cos >= threshold {
+			continue
+		}
+
+		area := length / 2
+		h1, h2, h3 := v1.Dot(up)-floor, v2.Dot(up)-floor, v3.Dot(up)-floor
+		if h1 <= tolerance && h2 <= tolerance && h3 <= tolerance {
+			// faces on the build plate are held up by it
+			if cos <= flat {
+				res.PlateArea += area
+			}
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return SupportAnalysis{}, err
+		}
+
+		// the tree is only built once it is clear that the mesh has overhangs
+		if s.tree == nil {
+			s.tree = newBVH(m)
+		}
+
+		res.Overhangs[i] = true
+		res.OverhangArea += area
+		res.ContactArea += area
+
+		// large faces are divided into smaller columns so that they can land on different surfaces
+		divisions := int(math.Ceil(math.Sqrt(area) / spacing))
+		divisions = int(math.Max(1, math.Min(maxSupportDivisions, float64(divisions))))
+		projected := -cos * area / float64(divisions*divisions)
+		for _, p := range subdivisionCentroids(v1, v2, v3, divisions) {
+			height := p.Dot(up) - floor
+			if t, _, ok := s.tree.intersect(p, down, i); ok && t < height {
+				height = t
+				res.ContactArea += projected
+			}
+			res.SupportVolume += projected * height
+		}
+	}
+	return res, nil
+}
+
+// subdivisionCentroids divides a triangle into divisions² equal triangles and returns their
+// centroids
+func subdivisionCentroids(v1, v2, v3 Vector, divisions int) []Vector {
+	e1, e2 := v2.Sub(v1), v3.Sub(v1)
+	at := func(u, v float64) Vector {
+		return v1.Add(e1.MulScalar(u / float64(divisions))).Add(e2.MulScalar(v / float64(divisions)))
+	}
+
+	points := make([]Vector, 0, divisions*divisions)
+	for i := 0; i < divisions; i++ {
+		for j := 0; i+j < divisions; j++ {
+			points = append(points, at(float64(i)+1.0/3, float64(j)+1.0/3))
+			// every triangle that points the same way as the original is followed by one that points the
+			// other way, except along the last diagonal
+			if i+j < divisions-1 {
+				points = append(points, at(float64(i)+2.0/3, float64(j)+2.0/3))
+			}
+		}
+	}
+	return points
+}
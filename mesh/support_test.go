@@ -0,0 +1,88 @@
+package mesh_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// plateOverBase returns a 10x10x2 base with a 1mm thick plate floating 3mm above it. The plate is
+// as long as the base along X times the overhang.
+func plateOverBase(overhang float64) *mesh.Mesh {
+	base := box(mesh.Vector{}, mesh.Vector{X: 10, Y: 10, Z: 2}, false)
+	base.Append(box(mesh.Vector{Z: 5}, mesh.Vector{X: 10 * overhang, Y: 10, Z: 6}, false))
+	return base
+}
+
+func TestSupportVolume(t *testing.T) {
+	// a cube stands on its bottom face so it does not need any support
+	v, err := cube(10).SupportVolume(context.Background(), mesh.DefaultOverhangAngle)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, v)
+
+	// the plate is held up by a 10x10x3 column of support that stands on the base
+	v, err = plateOverBase(1).SupportVolume(context.Background(), mesh.DefaultOverhangAngle)
+	require.NoError(t, err)
+	assert.InDelta(t, 300, v, 1e-9)
+}
+
+func TestAnalyzeSupport(t *testing.T) {
+	m := plateOverBase(1)
+	a, err := m.AnalyzeSupport(context.Background(), mesh.Vector{Z: 1}, mesh.DefaultOverhangAngle)
+	require.NoError(t, err)
+
+	// only the two triangles of the bottom of the plate need support
+	overhangs := 0
+	for i, overhang := range a.Overhangs {
+		if overhang {
+			overhangs++
+			assert.InDelta(t, -1, m.Normal(i).Z, 1e-9)
+		}
+	}
+	assert.Len(t, a.Overhangs, len(m.Triangles))
+	assert.Equal(t, 2, overhangs)
+	assert.InDelta(t, 100, a.OverhangArea, 1e-9)
+	assert.InDelta(t, 300, a.SupportVolume, 1e-9)
+	// support touches the bottom of the plate and the top of the base
+	assert.InDelta(t, 200, a.ContactArea, 1e-9)
+	// the base stands on the build plate
+	assert.InDelta(t, 100, a.PlateArea, 1e-9)
+}
+
+func TestAnalyzeSupportPartlyOverBuildPlate(t *testing.T) {
+	// half of the plate is above the base and the other half is supported from the build plate
+	a, err := plateOverBase(2).AnalyzeSupport(context.Background(), mesh.Vector{Z: 1}, mesh.DefaultOverhangAngle)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 200, a.OverhangArea, 1e-9)
+	assert.InDelta(t, 100*3+100*5, a.SupportVolume, 20)
+	assert.InDelta(t, 200+100, a.ContactArea, 10)
+}
+
+func TestAnalyzeSupportBuildDirection(t *testing.T) {
+	// upside down the plate stands on the build plate and the base hangs 3mm below it
+	a, err := plateOverBase(1).AnalyzeSupport(context.Background(), mesh.Vector{Z: -1}, mesh.DefaultOverhangAngle)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 100, a.OverhangArea, 1e-9)
+	assert.InDelta(t, 300, a.SupportVolume, 1e-9)
+	assert.InDelta(t, 200, a.ContactArea, 1e-9)
+
+	// faces that are steeper than the overhang angle do not need support
+	a, err = sphere(10, 16, 32).AnalyzeSupport(context.Background(), mesh.Vector{Z: 1}, 89)
+	require.NoError(t, err)
+	assert.Zero(t, a.OverhangArea)
+}
+
+func TestAnalyzeSupportCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := plateOverBase(1).AnalyzeSupport(ctx, mesh.Vector{Z: 1}, mesh.DefaultOverhangAngle)
+
+	assert.Equal(t, context.Canceled, err)
+}
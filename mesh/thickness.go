@@ -0,0 +1,140 @@
+package mesh
+
+import (
+	"math"
+	"sort"
+)
+
+// ThicknessAnalysis describes how thick the walls of a mesh are
+type ThicknessAnalysis struct {
+	// Vertices is the thickness of the wall at every vertex, measured along the inverted vertex
+	// normal. It is +Inf where the ray leaves the mesh without hitting the other side of the wall.
+	Vertices []float64
+	// Thinnest is the thinnest wall that was measured
+	Thinnest float64
+	// ThinArea is the area of the triangles that are thinner than the minimum
+	ThinArea float64
+	// Regions are the connected areas of triangles that are thinner than the minimum, from largest
+	// to smallest
+	Regions []ThinRegion
+}
+
+// ThinRegion is a connected area of triangles whose wall is thinner than the minimum
+type ThinRegion struct {
+	Triangles []int
+	Area      float64
+	Thinnest  float64
+	Bounds    Box
+}
+
+// AnalyzeThickness measures the walls of the mesh by casting rays inwards from every vertex and the
+// centre of every triangle to the opposite side of the wall, and finds the regions that are
+// thinner than a minimum. The mesh has to be closed and wound outwards.
+func (m *Mesh) AnalyzeThickness(min float64) ThicknessAnalysis {
+	res := ThicknessAnalysis{Vertices: make([]float64, len(m.Vertices)), Thinnest: math.Inf(1)}
+	if len(m.Triangles) == 0 {
+		return res
+	}
+
+	tree := newBVH(m)
+	measure := func(p, normal Vector, skip int) float64 {
+		if normal.Length() == 0 {
+			return math.Inf(1)
+		}
+		if t, _, ok := tree.intersect(p, normal.Normalize().MulScalar(-1), skip); ok {
+			return t
+		}
+		return math.Inf(1)
+	}
+
+	// vertex normals are the average of the normals of the triangles around them, weighted by the
+	// angle of each triangle at the vertex so that they do not depend on how faces are triangulated
+	normals := make([]Vector, len(m.Vertices))
+	for i, tri := range m.Triangles {
+		n := m.Normal(i)
+		for j, v := range tri {
+			a := m.Vertices[tri[(j+1)%3]].Sub(m.Vertices[v]).Normalize()
+			b := m.Vertices[tri[(j+2)%3]].Sub(m.Vertices[v]).Normalize()
+			angle := math.Acos(math.Max(-1, math.Min(1, a.Dot(b))))
+			normals[v] = normals[v].Add(n.MulScalar(angle))
+		}
+	}
+	for i, v := range m.Vertices {
+		res.Vertices[i] = measure(v, normals[i], -1)
+		res.Thinnest = math.Min(res.Thinnest, res.Vertices[i])
+	}
+
+	// a triangle is as thin as the thinnest of its corners and its centre
+	faces := make([]float64, len(m.Triangles))
+	for i, tri := range m.Triangles {
+		v1, v2, v3 := m.Corners(i)
+		faces[i] = measure(v1.Add(v2).Add(v3).DivScalar(3), v2.Sub(v1).Cross(v3.Sub(v1)), i)
+		res.Thinnest = math.Min(res.Thinnest, faces[i])
+		for _, v := range tri {
+			faces[i] = math.Min(faces[i], res.Vertices[v])
+		}
+	}
+
+	res.Regions = m.thinRegions(faces, min)
+	for _, r := range res.Regions {
+		res.ThinArea += r.Area
+	}
+	return res
+}
+
+// thinRegions groups the triangles that are thinner than a minimum into areas that share edges
+func (m *Mesh) thinRegions(faces []float64, min float64) []ThinRegion {
+	thin := func(i int) bool { return faces[i] < min }
+
+	parent := make([]int, len(m.Triangles))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	for _, uses := range newTopology(m).edges {
+		for _, a := range uses {
+			for _, b := range uses {
+				if thin(a.triangle) && thin(b.triangle) {
+					parent[find(b.triangle)] = find(a.triangle)
+				}
+			}
+		}
+	}
+
+	index := make(map[int]int)
+	var regions []ThinRegion
+	for i := range m.Triangles {
+		if !thin(i) {
+			continue
+		}
+
+		root := find(i)
+		r, ok := index[root]
+		if !ok {
+			r = len(regions)
+			index[root] = r
+			v, _, _ := m.Corners(i)
+			regions = append(regions, ThinRegion{Thinnest: math.Inf(1), Bounds: Box{Min: v, Max: v}})
+		}
+
+		region := &regions[r]
+		v1, v2, v3 := m.Corners(i)
+		region.Triangles = append(region.Triangles, i)
+		region.Area += v2.Sub(v1).Cross(v3.Sub(v1)).Length() / 2
+		region.Thinnest = math.Min(region.Thinnest, faces[i])
+		region.Bounds.Min = region.Bounds.Min.Min(v1).Min(v2).Min(v3)
+		region.Bounds.Max = region.Bounds.Max.Max(v1).Max(v2).Max(v3)
+	}
+
+	sort.SliceStable(regions, func(i, j int) bool {
+		return regions[i].Area > regions[j].Area
+	})
+	return regions
+}
@@ -0,0 +1,64 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestAnalyzeThickness(t *testing.T) {
+	a := cube(10).AnalyzeThickness(0.8)
+
+	// rays from the corners cross the cube diagonally and rays from the faces straight through
+	assert.InDelta(t, 10, a.Thinnest, 1e-9)
+	for _, v := range a.Vertices {
+		assert.InDelta(t, 10*math.Sqrt(3), v, 1e-9)
+	}
+	assert.Empty(t, a.Regions)
+	assert.Zero(t, a.ThinArea)
+}
+
+func TestAnalyzeThicknessThinWall(t *testing.T) {
+	// a 0.5mm plate stands next to a thick block
+	m := box(mesh.Vector{}, mesh.Vector{X: 10, Y: 10, Z: 10}, false)
+	m.Append(box(mesh.Vector{X: 20}, mesh.Vector{X: 30, Y: 10, Z: 0.5}, false))
+
+	// the top and bottom of the plate are thin on their own
+	a := m.AnalyzeThickness(0.8)
+	require.Len(t, a.Regions, 2)
+	assert.InDelta(t, 200, a.ThinArea, 1e-9)
+
+	// rays from the corners of the plate cross it diagonally, which joins its sides into one region
+	a = m.AnalyzeThickness(1)
+
+	assert.Len(t, a.Vertices, len(m.Vertices))
+	assert.InDelta(t, 0.5, a.Thinnest, 1e-9)
+	require.Len(t, a.Regions, 1)
+	r := a.Regions[0]
+	// every face of the plate is part of the thin region
+	assert.Len(t, r.Triangles, 12)
+	assert.InDelta(t, 2*100+4*10*0.5, r.Area, 1e-9)
+	assert.InDelta(t, r.Area, a.ThinArea, 1e-9)
+	assert.InDelta(t, 0.5, r.Thinnest, 1e-9)
+	assert.Equal(t, mesh.Box{Min: mesh.Vector{X: 20}, Max: mesh.Vector{X: 30, Y: 10, Z: 0.5}}, r.Bounds)
+}
+
+func TestAnalyzeThicknessOpenMesh(t *testing.T) {
+	m := &mesh.Mesh{
+		Vertices:  []mesh.Vector{{}, {X: 1}, {Y: 1}},
+		Triangles: []mesh.Triangle{{0, 1, 2}},
+	}
+
+	// rays from a single triangle never hit the other side of a wall
+	a := m.AnalyzeThickness(0.8)
+
+	assert.True(t, math.IsInf(a.Thinnest, 1))
+	for _, v := range a.Vertices {
+		assert.True(t, math.IsInf(v, 1))
+	}
+	assert.Empty(t, a.Regions)
+}
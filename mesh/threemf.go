@@ -0,0 +1,469 @@
+package mesh
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	threeMFDefaultModelPath = "3D/3dmodel.model"
+	threeMFRelsPath         = "_rels/.rels"
+	threeMFModelRelType     = "http://schemas.microsoft.com/3dmanufacturing/2013/01/3dmodel"
+	// max3MFTriangles is the most triangles that the build items of a 3MF package can expand to
+	max3MFTriangles = 10000000
+)
+
+// ThreeMF is the content of a 3MF package with all components and transforms resolved
+type ThreeMF struct {
+	// Unit is the unit of the model coordinates as defined by the 3MF specification (millimeter,
+	// micron, centimeter, inch, foot or meter)
+	Unit string
+	// Metadata holds the document metadata such as Title, Designer and Description
+	Metadata  map[string]string
+	Materials []BaseMaterial
+	Items     []BuildItem
+}
+
+// BaseMaterial is a named material with a display color in the sRGB "#RRGGBB[AA]" form
+type BaseMaterial struct {
+	Name  string
+	Color string
+}
+
+// BuildItem is an object placed on the build plate. Its mesh is already transformed into build
+// plate coordinates.
+type BuildItem struct {
+	Name string
+	Mesh *Mesh
+}
+
+// Mesh merges all build items into a single mesh
+func (t *ThreeMF) Mesh() *Mesh {
+	m := &Mesh{}
+	for _, item := range t.Items {
+		m.Append(item.Mesh)
+	}
+	return m
+}
+
+// Read3MF parses a 3MF package into a single mesh containing every build item
+func Read3MF(r io.Reader) (*Mesh, error) {
+	t, err := Read3MFPackage(r)
+	if err != nil {
+		return nil, err
+	}
+	return t.Mesh(), nil
+}
+
+// Read3MFPackage parses a 3MF package and resolves its build items into meshes
+func Read3MFPackage(r io.Reader) (*ThreeMF, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	modelFile, ok := files[threeMFModelPath(files)]
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+
+	var doc threeMFModel
+	if err := decodeZipXML(modelFile, &doc); err != nil {
+		return nil, ErrInvalidFormat
+	}
+
+	return doc.resolve()
+}
+
+// threeMFModelPath finds the root model part from the package relationships, falling back to the
+// conventional location
+func threeMFModelPath(files map[string]*zip.File) string {
+	relsFile, ok := files[threeMFRelsPath]
+	if !ok {
+		return threeMFDefaultModelPath
+	}
+
+	var rels struct {
+		Relationships []struct {
+			Target string `xml:"Target,attr"`
+			Type   string `xml:"Type,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeZipXML(relsFile, &rels); err != nil {
+		return threeMFDefaultModelPath
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.Type == threeMFModelRelType {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+	}
+	return threeMFDefaultModelPath
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+type threeMFModel struct {
+	Unit     string `xml:"unit,attr"`
+	Metadata []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"metadata"`
+	Resources struct {
+		BaseMaterials []struct {
+			ID    int `xml:"id,attr"`
+			Bases []struct {
+				Name         string `xml:"name,attr"`
+				DisplayColor string `xml:"displaycolor,attr"`
+			} `xml:"base"`
+		} `xml:"basematerials"`
+		Objects []threeMFObject `xml:"object"`
+	} `xml:"resources"`
+	Build struct {
+		Items []struct {
+			ObjectID  int    `xml:"objectid,attr"`
+			Transform string `xml:"transform,attr"`
+		} `xml:"item"`
+	} `xml:"build"`
+}
+
+type threeMFObject struct {
+	ID   int    `xml:"id,attr"`
+	Type string `xml:"type,attr"`
+	Name string `xml:"name,attr"`
+	Mesh *struct {
+		Vertices []struct {
+			X float64 `xml:"x,attr"`
+			Y float64 `xml:"y,attr"`
+			Z float64 `xml:"z,attr"`
+		} `xml:"vertices>vertex"`
+		Triangles []struct {
+			V1 int `xml:"v1,attr"`
+			V2 int `xml:"v2,attr"`
+			V3 int `xml:"v3,attr"`
+		} `xml:"triangles>triangle"`
+	} `xml:"mesh"`
+	Components []struct {
+		ObjectID  int    `xml:"objectid,attr"`
+		Transform string `xml:"transform,attr"`
+	} `xml:"components>component"`
+}
+
+func (doc *threeMFModel) resolve() (*ThreeMF, error) {
+	t := &ThreeMF{
+		Unit:     doc.Unit,
+		Metadata: make(map[string]string),
+	}
+	if t.Unit == "" {
+		t.Unit = "millimeter"
+	}
+
+	for _, md := range doc.Metadata {
+		t.Metadata[md.Name] = strings.TrimSpace(md.Value)
+	}
+
+	for _, group := range doc.Resources.BaseMaterials {
+		for _, base := range group.Bases {
+			t.Materials = append(t.Materials, BaseMaterial{Name: base.Name, Color: base.DisplayColor})
+		}
+	}
+
+	r := &threeMFResolver{
+		objects:   make(map[int]*threeMFObject),
+		meshes:    make(map[int]*Mesh),
+		triangles: make(map[int]int),
+		visiting:  make(map[int]bool),
+	}
+	for i := range doc.Resources.Objects {
+		o := &doc.Resources.Objects[i]
+		r.objects[o.ID] = o
+	}
+
+	// components can reference the same object many times over, so the size of the expanded build
+	// is counted before any of it is built
+	total := 0
+	for _, item := range doc.Build.Items {
+		n, err := r.count(item.ObjectID)
+		if err != nil {
+			return nil, err
+		}
+		total += n
+		if total > max3MFTriangles {
+			return nil, ErrTooLarge
+		}
+	}
+
+	for _, item := range doc.Build.Items {
+		transform, err := parse3MFTransform(item.Transform)
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := r.mesh(item.ObjectID)
+		if err != nil {
+			return nil, err
+		}
+		m = m.Transform(transform)
+		for _, v := range m.Vertices {
+			if !isFinite(v) {
+				return nil, ErrInvalidFormat
+			}
+		}
+
+		t.Items = append(t.Items, BuildItem{Name: r.objects[item.ObjectID].Name, Mesh: m})
+	}
+
+	return t, nil
+}
+
+// threeMFResolver builds the meshes of the objects in a 3MF model. The mesh of every object is
+// built once in its own coordinates and reused by every component that references it.
+type threeMFResolver struct {
+	objects   map[int]*threeMFObject
+	meshes    map[int]*Mesh
+	triangles map[int]int
+	// visiting tracks the objects on the current path to reject packages with circular references
+	visiting map[int]bool
+}
+
+// count returns the number of triangles of an object once all of its components are expanded. The
+// count stops growing once it passes max3MFTriangles so that it can not overflow.
+func (r *threeMFResolver) count(id int) (int, error) {
+	if n, ok := r.triangles[id]; ok {
+		return n, nil
+	}
+	o, ok := r.objects[id]
+	if !ok || r.visiting[id] {
+		return 0, ErrInvalidFormat
+	}
+	r.visiting[id] = true
+	defer delete(r.visiting, id)
+
+	n := 0
+	// support geometry is generated by the slicer and is not part of the model itself
+	if o.Type != "support" && o.Type != "other" {
+		if o.Mesh != nil {
+			n = len(o.Mesh.Triangles)
+		}
+		for _, c := range o.Components {
+			cn, err := r.count(c.ObjectID)
+			if err != nil {
+				return 0, err
+			}
+			n += cn
+			if n > max3MFTriangles {
+				n = max3MFTriangles + 1
+			}
+		}
+	}
+
+	r.triangles[id] = n
+	return n, nil
+}
+
+// mesh returns the mesh of an object with all of its components, in the coordinates of the object
+func (r *threeMFResolver) mesh(id int) (*Mesh, error) {
+	if m, ok := r.meshes[id]; ok {
+		return m, nil
+	}
+	o, ok := r.objects[id]
+	if !ok || r.visiting[id] {
+		return nil, ErrInvalidFormat
+	}
+	r.visiting[id] = true
+	defer delete(r.visiting, id)
+
+	m := &Mesh{}
+	if o.Type != "support" && o.Type != "other" {
+		if o.Mesh != nil {
+			for _, v := range o.Mesh.Vertices {
+				m.Vertices = append(m.Vertices, Vector{v.X, v.Y, v.Z})
+			}
+			for _, tri := range o.Mesh.Triangles {
+				for _, v := range []int{tri.V1, tri.V2, tri.V3} {
+					if v < 0 || v >= len(m.Vertices) {
+						return nil, ErrInvalidFormat
+					}
+				}
+				m.Triangles = append(m.Triangles, Triangle{tri.V1, tri.V2, tri.V3})
+			}
+		}
+
+		for _, c := range o.Components {
+			transform, err := parse3MFTransform(c.Transform)
+			if err != nil {
+				return nil, err
+			}
+
+			cm, err := r.mesh(c.ObjectID)
+			if err != nil {
+				return nil, err
+			}
+			// Transform also reverses the winding of mirrored components
+			m.Append(cm.Transform(transform))
+		}
+	}
+
+	r.meshes[id] = m
+	return m, nil
+}
+
+// parse3MFTransform parses the 12 values of a 3MF transform. 3MF stores the matrix in row-vector
+// form with the translation in the last row, so it is transposed into the column-vector form used
+// by Matrix.
+func parse3MFTransform(s string) (Matrix, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return Identity(), nil
+	}
+	if len(fields) != 12 {
+		return Matrix{}, ErrInvalidFormat
+	}
+
+	var v [12]float64
+	for i, f := range fields {
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return Matrix{}, ErrInvalidFormat
+		}
+		v[i] = n
+	}
+
+	return Matrix{
+		{v[0], v[3], v[6], v[9]},
+		{v[1], v[4], v[7], v[10]},
+		{v[2], v[5], v[8], v[11]},
+		{0, 0, 0, 1},
+	}, nil
+}
+
+const threeMFContentTypes = `<?xml version="1.0" encoding="UTF-8"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+  <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml" />
+  <Default Extension="model" ContentType="application/vnd.ms-package.3dmanufacturing-3dmodel+xml" />
+</Types>`
+
+const threeMFRels = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Target="/` + threeMFDefaultModelPath + `" Id="rel0" Type="` + threeMFModelRelType + `" />
+</Relationships>`
+
+// Write3MF encodes the mesh as a 3MF package with a single build item
+func Write3MF(w io.Writer, m *Mesh) error {
+	return Write3MFPackage(w, &ThreeMF{Items: []BuildItem{{Mesh: m}}})
+}
+
+// Write3MFPackage encodes each build item as its own object in a 3MF package. The item meshes are
+// already in build plate coordinates so no transforms are written.
+func Write3MFPackage(w io.Writer, t *ThreeMF) error {
+	archive := zip.NewWriter(w)
+
+	for _, part := range [][2]string{
+		{"[Content_Types].xml", threeMFContentTypes},
+		{threeMFRelsPath, threeMFRels},
+	} {
+		f, err := archive.Create(part[0])
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part[1]); err != nil {
+			return err
+		}
+	}
+
+	f, err := archive.Create(threeMFDefaultModelPath)
+	if err != nil {
+		return err
+	}
+	if err := writeThreeMFModel(f, t); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func writeThreeMFModel(w io.Writer, t *ThreeMF) error {
+	bw := bufio.NewWriter(w)
+
+	unit := t.Unit
+	if unit == "" {
+		unit = "millimeter"
+	}
+
+	fmt.Fprintln(bw, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintf(bw, `<model unit="%s" xml:lang="en-US" xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02">`+"\n", xmlEscape(unit))
+
+	names := make([]string, 0, len(t.Metadata))
+	for name := range t.Metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(bw, "  <metadata name=\"%s\">%s</metadata>\n", xmlEscape(name), xmlEscape(t.Metadata[name]))
+	}
+
+	fmt.Fprintln(bw, "  <resources>")
+	for i, item := range t.Items {
+		fmt.Fprintf(bw, "    <object id=\"%d\" type=\"model\"", i+1)
+		if item.Name != "" {
+			fmt.Fprintf(bw, " name=\"%s\"", xmlEscape(item.Name))
+		}
+		fmt.Fprintln(bw, ">")
+		fmt.Fprintln(bw, "      <mesh>")
+		fmt.Fprintln(bw, "        <vertices>")
+		for _, v := range item.Mesh.Vertices {
+			fmt.Fprintf(bw, "          <vertex x=\"%s\" y=\"%s\" z=\"%s\" />\n", formatFloat(v.X), formatFloat(v.Y), formatFloat(v.Z))
+		}
+		fmt.Fprintln(bw, "        </vertices>")
+		fmt.Fprintln(bw, "        <triangles>")
+		for _, tri := range item.Mesh.Triangles {
+			fmt.Fprintf(bw, "          <triangle v1=\"%d\" v2=\"%d\" v3=\"%d\" />\n", tri[0], tri[1], tri[2])
+		}
+		fmt.Fprintln(bw, "        </triangles>")
+		fmt.Fprintln(bw, "      </mesh>")
+		fmt.Fprintln(bw, "    </object>")
+	}
+	fmt.Fprintln(bw, "  </resources>")
+
+	fmt.Fprintln(bw, "  <build>")
+	for i := range t.Items {
+		fmt.Fprintf(bw, "    <item objectid=\"%d\" />\n", i+1)
+	}
+	fmt.Fprintln(bw, "  </build>")
+	fmt.Fprintln(bw, "</model>")
+
+	return bw.Flush()
+}
+
+func xmlEscape(s string) string {
+	b := new(bytes.Buffer)
+	xml.EscapeText(b, []byte(s))
+	return b.String()
+}
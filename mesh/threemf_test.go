@@ -0,0 +1,157 @@
+package mesh_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+const threeMFModel = `<?xml version="1.0" encoding="UTF-8"?>
+<model unit="millimeter" xml:lang="en-US" xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02">
+  <metadata name="Title">Bracket</metadata>
+  <metadata name="Designer">Ryan</metadata>
+  <resources>
+    <basematerials id="1">
+      <base name="PLA Red" displaycolor="#FF0000" />
+    </basematerials>
+    <object id="2" type="model" pid="1" pindex="0">
+      <mesh>
+        <vertices>
+          <vertex x="0" y="0" z="0" />
+          <vertex x="1" y="0" z="0" />
+          <vertex x="0" y="1" z="0" />
+          <vertex x="0" y="0" z="1" />
+        </vertices>
+        <triangles>
+          <triangle v1="0" v2="2" v3="1" />
+          <triangle v1="0" v2="1" v3="3" />
+          <triangle v1="0" v2="3" v3="2" />
+          <triangle v1="1" v2="2" v3="3" />
+        </triangles>
+      </mesh>
+    </object>
+    <object id="3" type="model" name="pair">
+      <components>
+        <component objectid="2" />
+        <component objectid="2" transform="1 0 0 0 1 0 0 0 1 5 0 0" />
+      </components>
+    </object>
+  </resources>
+  <build>
+    <item objectid="3" transform="2 0 0 0 2 0 0 0 2 0 0 10" />
+    <item objectid="2" />
+  </build>
+</model>`
+
+func TestRead3MFPackage(t *testing.T) {
+	pkg, err := mesh.Read3MFPackage(bytes.NewReader(threeMF(t, "3D/3dmodel.model", threeMFModel)))
+	require.NoError(t, err)
+
+	assert.Equal(t, "millimeter", pkg.Unit)
+	assert.Equal(t, "Bracket", pkg.Metadata["Title"])
+	assert.Equal(t, "Ryan", pkg.Metadata["Designer"])
+	assert.Equal(t, []mesh.BaseMaterial{{Name: "PLA Red", Color: "#FF0000"}}, pkg.Materials)
+
+	require.Len(t, pkg.Items, 2)
+	assert.Equal(t, "pair", pkg.Items[0].Name)
+
+	// the second component is translated by 5 and then the whole item is scaled by 2 and raised by 10
+	pair := pkg.Items[0].Mesh
+	assert.Len(t, pair.Triangles, 8)
+	assert.Equal(t, mesh.Box{
+		Min: mesh.Vector{X: 0, Y: 0, Z: 10},
+		Max: mesh.Vector{X: 12, Y: 2, Z: 12},
+	}, pair.Bounds())
+
+	m := pkg.Mesh()
+	assert.Len(t, m.Triangles, 12)
+	assert.InDelta(t, 2*8.0/6+1.0/6, m.Properties().Volume, 1e-9)
+}
+
+func TestRead3MFModelPathFromRelationships(t *testing.T) {
+	rels := `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Target="/3D/part.model" Id="rel0" Type="http://schemas.microsoft.com/3dmanufacturing/2013/01/3dmodel" />
+</Relationships>`
+
+	data := threeMF(t, "3D/part.model", threeMFModel, "_rels/.rels", rels)
+	m, err := mesh.Read3MF(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Len(t, m.Triangles, 12)
+}
+
+func TestRead3MFMirrored(t *testing.T) {
+	// the second component of the pair is mirrored in X and the lone item is mirrored in Z
+	model := strings.Replace(threeMFModel, `transform="1 0 0 0 1 0 0 0 1 5 0 0"`, `transform="-1 0 0 0 1 0 0 0 1 5 0 0"`, 1)
+	model = strings.Replace(model, `<item objectid="2" />`, `<item objectid="2" transform="1 0 0 0 1 0 0 0 -1 0 0 0" />`, 1)
+
+	pkg, err := mesh.Read3MFPackage(bytes.NewReader(threeMF(t, "3D/3dmodel.model", model)))
+	require.NoError(t, err)
+
+	// mirrored triangles keep facing outwards, so no volume is subtracted
+	for _, item := range pkg.Items {
+		assert.Zero(t, item.Mesh.Analyze().InconsistentlyWoundFaces)
+	}
+	assert.InDelta(t, 2*8.0/6, pkg.Items[0].Mesh.Properties().Volume, 1e-9)
+	assert.InDelta(t, 1.0/6, pkg.Items[1].Mesh.Properties().Volume, 1e-9)
+}
+
+func TestRead3MFTooLarge(t *testing.T) {
+	// every object references the one before it twice, so the build doubles with every level
+	model := new(strings.Builder)
+	model.WriteString(`<model><resources><object id="1"><mesh><vertices>
+  <vertex x="0" y="0" z="0" /><vertex x="1" y="0" z="0" /><vertex x="0" y="1" z="0" />
+</vertices><triangles><triangle v1="0" v2="1" v3="2" /></triangles></mesh></object>`)
+	for id := 2; id <= 64; id++ {
+		fmt.Fprintf(model, `<object id="%d"><components><component objectid="%d" /><component objectid="%d" /></components></object>`, id, id-1, id-1)
+	}
+	model.WriteString(`</resources><build><item objectid="64" /></build></model>`)
+
+	_, err := mesh.Read3MF(bytes.NewReader(threeMF(t, "3D/3dmodel.model", model.String())))
+	assert.Equal(t, mesh.ErrTooLarge, err)
+}
+
+func TestRead3MFInvalid(t *testing.T) {
+	circular := `<model><resources>
+  <object id="1"><components><component objectid="2" /></components></object>
+  <object id="2"><components><component objectid="1" /></components></object>
+</resources><build><item objectid="1" /></build></model>`
+
+	tests := map[string][]byte{
+		"not-a-zip":     []byte("solid x"),
+		"missing-model": threeMF(t, "3D/other.model", threeMFModel),
+		"circular":      threeMF(t, "3D/3dmodel.model", circular),
+		"bad-transform": threeMF(t, "3D/3dmodel.model", `<model><build><item objectid="1" transform="1 0 0" /></build></model>`),
+		"nan-vertex":    threeMF(t, "3D/3dmodel.model", strings.Replace(threeMFModel, `<vertex x="0" y="0" z="1" />`, `<vertex x="NaN" y="0" z="Inf" />`, 1)),
+		"inf-transform": threeMF(t, "3D/3dmodel.model", strings.Replace(threeMFModel, `transform="2 0 0 0 2 0 0 0 2 0 0 10"`, `transform="2 0 0 0 2 0 0 0 2 0 0 +Inf"`, 1)),
+		"overflow":      threeMF(t, "3D/3dmodel.model", strings.Replace(threeMFModel, `<vertex x="0" y="0" z="1" />`, `<vertex x="0" y="0" z="1e308" />`, 1)),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := mesh.Read3MF(bytes.NewReader(data))
+			assert.Equal(t, mesh.ErrInvalidFormat, err)
+		})
+	}
+}
+
+// threeMF zips pairs of file names and contents into a 3MF package
+func threeMF(t *testing.T, files ...string) []byte {
+	b := new(bytes.Buffer)
+	w := zip.NewWriter(b)
+	for i := 0; i < len(files); i += 2 {
+		f, err := w.Create(files[i])
+		require.NoError(t, err)
+		_, err = f.Write([]byte(files[i+1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
@@ -0,0 +1,164 @@
+package mesh
+
+// edge is an undirected edge between two vertices with the smaller index first
+type edge [2]int
+
+func newEdge(a, b int) edge {
+	if a > b {
+		a, b = b, a
+	}
+	return edge{a, b}
+}
+
+// edgeUse is a triangle that has an edge. forward is true when the triangle traverses the edge from
+// its smaller vertex index to its larger one.
+type edgeUse struct {
+	triangle int
+	forward  bool
+}
+
+// topology describes how the triangles of a mesh are connected. Vertices that share the exact same
+// position are treated as one vertex so that files which split vertices, for example along texture
+// seams, are still considered connected.
+type topology struct {
+	// vertex maps every vertex onto the first vertex with the same position
+	vertex []int
+	edges  map[edge][]edgeUse
+}
+
+func newTopology(m *Mesh) *topology {
+	t := &topology{
+		vertex: make([]int, len(m.Vertices)),
+		edges:  make(map[edge][]edgeUse),
+	}
+
+	first := make(map[Vector]int)
+	for i, v := range m.Vertices {
+		if j, ok := first[v]; ok {
+			t.vertex[i] = j
+		} else {
+			first[v] = i
+			t.vertex[i] = i
+		}
+	}
+
+	for i := range m.Triangles {
+		if t.isCollapsed(m, i) {
+			continue
+		}
+
+		c := t.corners(m, i)
+		for j := 0; j < 3; j++ {
+			a, b := c[j], c[(j+1)%3]
+			e := newEdge(a, b)
+			t.edges[e] = append(t.edges[e], edgeUse{triangle: i, forward: a < b})
+		}
+	}
+
+	return t
+}
+
+// corners returns the welded vertex indices of a triangle
+func (t *topology) corners(m *Mesh, i int) Triangle {
+	tri := m.Triangles[i]
+	return Triangle{t.vertex[tri[0]], t.vertex[tri[1]], t.vertex[tri[2]]}
+}
+
+// isCollapsed reports whether a triangle uses the same vertex for more than one of its corners
+func (t *topology) isCollapsed(m *Mesh, i int) bool {
+	c := t.corners(m, i)
+	return c[0] == c[1] || c[1] == c[2] || c[2] == c[0]
+}
+
+// neighbors returns, for each triangle, the triangles it shares a manifold edge with and whether
+// the pair is wound consistently across that edge
+func (t *topology) neighbors(triangleCount int) [][]neighbor {
+	n := make([][]neighbor, triangleCount)
+	for _, uses := range t.edges {
+		if len(uses) != 2 {
+			continue
+		}
+
+		a, b := uses[0], uses[1]
+		// two consistently wound triangles traverse their shared edge in opposite directions
+		consistent := a.forward != b.forward
+		n[a.triangle] = append(n[a.triangle], neighbor{b.triangle, consistent})
+		n[b.triangle] = append(n[b.triangle], neighbor{a.triangle, consistent})
+	}
+	return n
+}
+
+type neighbor struct {
+	triangle   int
+	consistent bool
+}
+
+// Shells splits the triangles of the mesh into connected components, where two triangles are
+// connected if they share an edge. Each shell is a list of triangle indices. Triangles that
+// reference the same vertex more than once have no edges and are left out.
+func (m *Mesh) Shells() [][]int {
+	t := newTopology(m)
+
+	parent := make([]int, len(m.Triangles))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	for _, uses := range t.edges {
+		for _, u := range uses[1:] {
+			parent[find(u.triangle)] = find(uses[0].triangle)
+		}
+	}
+
+	index := make(map[int]int)
+	var shells [][]int
+	for i := range m.Triangles {
+		if t.isCollapsed(m, i) {
+			continue
+		}
+
+		root := find(i)
+		s, ok := index[root]
+		if !ok {
+			s = len(shells)
+			index[root] = s
+			shells = append(shells, nil)
+		}
+		shells[s] = append(shells[s], i)
+	}
+	return shells
+}
+
+// Parts splits the mesh into a mesh for every shell, in the order of Shells. Each part holds only
+// the vertices that its triangles use, together with their colors.
+func (m *Mesh) Parts() []*Mesh {
+	shells := m.Shells()
+	parts := make([]*Mesh, len(shells))
+	for s, shell := range shells {
+		part := &Mesh{Triangles: make([]Triangle, len(shell))}
+		index := make(map[int]int)
+		for i, t := range shell {
+			for j, v := range m.Triangles[t] {
+				k, ok := index[v]
+				if !ok {
+					k = len(part.Vertices)
+					index[v] = k
+					part.Vertices = append(part.Vertices, m.Vertices[v])
+					if m.HasColors() {
+						part.Colors = append(part.Colors, m.Colors[v])
+					}
+				}
+				part.Triangles[i][j] = k
+			}
+		}
+		parts[s] = part
+	}
+	return parts
+}
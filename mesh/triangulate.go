@@ -0,0 +1,133 @@
+package mesh
+
+import "math"
+
+// triangulate splits a planar polygon, given as indices into vertices, into triangles using ear
+// clipping so that concave faces are handled correctly. If the polygon is too degenerate to find an
+// ear it falls back to a simple fan.
+func triangulate(vertices []Vector, polygon []int) []Triangle {
+	if len(polygon) == 3 {
+		return []Triangle{{polygon[0], polygon[1], polygon[2]}}
+	}
+
+	// project the polygon onto the plane where its normal has the largest component
+	normal := newellNormal(vertices, polygon)
+	ax, ay := projectionAxes(normal)
+	points := make([][2]float64, len(polygon))
+	for i, idx := range polygon {
+		v := [3]float64{vertices[idx].X, vertices[idx].Y, vertices[idx].Z}
+		points[i] = [2]float64{v[ax], v[ay]}
+	}
+
+	// keep the projected polygon counter-clockwise. Triangles of a reversed polygon are turned back
+	// so that they keep the winding of the face.
+	remaining := make([]int, len(polygon))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	reversed := signedArea2D(points, remaining) < 0
+	if reversed {
+		for i, j := 0, len(remaining)-1; i < j; i, j = i+1, j-1 {
+			remaining[i], remaining[j] = remaining[j], remaining[i]
+		}
+	}
+
+	var triangles []Triangle
+	emit := func(a, b, c int) {
+		if reversed {
+			a, c = c, a
+		}
+		triangles = append(triangles, Triangle{polygon[a], polygon[b], polygon[c]})
+	}
+	for len(remaining) > 3 {
+		ear := -1
+		for i := range remaining {
+			if isEar(points, remaining, i) {
+				ear = i
+				break
+			}
+		}
+		if ear == -1 {
+			return fan(polygon)
+		}
+
+		prev := remaining[(ear+len(remaining)-1)%len(remaining)]
+		next := remaining[(ear+1)%len(remaining)]
+		emit(prev, remaining[ear], next)
+		remaining = append(remaining[:ear], remaining[ear+1:]...)
+	}
+	emit(remaining[0], remaining[1], remaining[2])
+
+	return triangles
+}
+
+func fan(polygon []int) []Triangle {
+	triangles := make([]Triangle, 0, len(polygon)-2)
+	for i := 1; i+1 < len(polygon); i++ {
+		triangles = append(triangles, Triangle{polygon[0], polygon[i], polygon[i+1]})
+	}
+	return triangles
+}
+
+// newellNormal computes a robust normal for a possibly non-convex polygon
+func newellNormal(vertices []Vector, polygon []int) Vector {
+	var n Vector
+	for i := range polygon {
+		a := vertices[polygon[i]]
+		b := vertices[polygon[(i+1)%len(polygon)]]
+		n.X += (a.Y - b.Y) * (a.Z + b.Z)
+		n.Y += (a.Z - b.Z) * (a.X + b.X)
+		n.Z += (a.X - b.X) * (a.Y + b.Y)
+	}
+	return n
+}
+
+// projectionAxes returns the two coordinate axes that span the plane most perpendicular to n
+func projectionAxes(n Vector) (int, int) {
+	x, y, z := math.Abs(n.X), math.Abs(n.Y), math.Abs(n.Z)
+	switch {
+	case x >= y && x >= z:
+		return 1, 2
+	case y >= z:
+		return 2, 0
+	default:
+		return 0, 1
+	}
+}
+
+func signedArea2D(points [][2]float64, polygon []int) float64 {
+	var area float64
+	for i := range polygon {
+		a := points[polygon[i]]
+		b := points[polygon[(i+1)%len(polygon)]]
+		area += a[0]*b[1] - b[0]*a[1]
+	}
+	return area / 2
+}
+
+func cross2D(o, a, b [2]float64) float64 {
+	return (a[0]-o[0])*(b[1]-o[1]) - (a[1]-o[1])*(b[0]-o[0])
+}
+
+func isEar(points [][2]float64, polygon []int, i int) bool {
+	n := len(polygon)
+	a := points[polygon[(i+n-1)%n]]
+	b := points[polygon[i]]
+	c := points[polygon[(i+1)%n]]
+
+	// reflex corners can not be ears
+	if cross2D(a, b, c) <= 0 {
+		return false
+	}
+
+	for j := range polygon {
+		if j == i || j == (i+n-1)%n || j == (i+1)%n {
+			continue
+		}
+		p := points[polygon[j]]
+		if cross2D(a, b, p) >= 0 && cross2D(b, c, p) >= 0 && cross2D(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package mesh
+
+// Unit is the length that one unit of the coordinates of a mesh stands for. Most mesh formats do not
+// store it, so it is either detected or given by whoever uploads the mesh.
+type Unit string
+
+const (
+	UnitMillimeter Unit = "mm"
+	UnitCentimeter Unit = "cm"
+	UnitMeter      Unit = "m"
+	UnitInch       Unit = "inch"
+)
+
+// units maps every unit onto its length in millimeters
+var units = map[Unit]float64{
+	UnitMillimeter: 1,
+	UnitCentimeter: 10,
+	UnitMeter:      1000,
+	UnitInch:       25.4,
+}
+
+// threeMFUnits maps the units of the 3MF specification onto the supported units
+var threeMFUnits = map[string]Unit{
+	"millimeter": UnitMillimeter,
+	"centimeter": UnitCentimeter,
+	"inch":       UnitInch,
+	"meter":      UnitMeter,
+}
+
+const (
+	// minMillimeterSize is the smallest size in mm of the largest side of a mesh that is assumed to
+	// be modelled in mm. Smaller meshes are too small to print in mm and more likely to be modelled in
+	// inches.
+	minMillimeterSize = 1
+	// minInchSize is the smallest size in inches of the largest side of a mesh that is assumed to be
+	// modelled in inches. Smaller meshes are more likely to be modelled in meters.
+	minInchSize = 0.5
+)
+
+// ParseUnit returns the unit with a name, and false if the unit is not supported
+func ParseUnit(s string) (Unit, bool) {
+	u := Unit(s)
+	_, ok := units[u]
+	return u, ok
+}
+
+// Parse3MFUnit returns the unit with a name from the 3MF specification, and false if the unit is not
+// supported
+func Parse3MFUnit(name string) (Unit, bool) {
+	u, ok := threeMFUnits[name]
+	return u, ok
+}
+
+// Millimeters returns the length of the unit in millimeters
+func (u Unit) Millimeters() float64 {
+	return units[u]
+}
+
+// ThreeMFName returns the name of the unit in the 3MF specification, or an empty string if the unit
+// is not supported
+func (u Unit) ThreeMFName() string {
+	for name, unit := range threeMFUnits {
+		if unit == u {
+			return name
+		}
+	}
+	return ""
+}
+
+// DetectUnit guesses the unit of a mesh from the size of its bounding box. Most meshes are modelled
+// in mm, so any mesh that is printable in mm is assumed to be in mm. Only a mesh that is less than a
+// unit across is too small for that, and is likely in inches or, when it is even smaller, in meters.
+// Centimeters are never detected because the sizes overlap with mm.
+func DetectUnit(b Box) Unit {
+	size := b.Size()
+	largest := size.X
+	if size.Y > largest {
+		largest = size.Y
+	}
+	if size.Z > largest {
+		largest = size.Z
+	}
+
+	switch {
+	case largest >= minMillimeterSize:
+		return UnitMillimeter
+	case largest >= minInchSize:
+		return UnitInch
+	case largest > 0:
+		return UnitMeter
+	default:
+		return UnitMillimeter
+	}
+}
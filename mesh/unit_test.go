@@ -0,0 +1,50 @@
+package mesh_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+func TestDetectUnit(t *testing.T) {
+	tests := []struct {
+		name string
+		size mesh.Vector
+		unit mesh.Unit
+	}{
+		{"calibration-cube", mesh.Vector{X: 20, Y: 20, Z: 20}, mesh.UnitMillimeter},
+		{"small-cube", mesh.Vector{X: 10, Y: 10, Z: 10}, mesh.UnitMillimeter},
+		{"small-part", mesh.Vector{X: 4, Y: 2, Z: 0.25}, mesh.UnitMillimeter},
+		{"large-part", mesh.Vector{X: 180, Y: 40, Z: 3}, mesh.UnitMillimeter},
+		{"inch-washer", mesh.Vector{X: 0.75, Y: 0.75, Z: 0.06}, mesh.UnitInch},
+		{"meter-part", mesh.Vector{X: 0.12, Y: 0.08, Z: 0.05}, mesh.UnitMeter},
+		{"empty", mesh.Vector{}, mesh.UnitMillimeter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.unit, mesh.DetectUnit(mesh.Box{Max: tt.size}))
+		})
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	u, ok := mesh.ParseUnit("inch")
+	assert.True(t, ok)
+	assert.Equal(t, 25.4, u.Millimeters())
+
+	_, ok = mesh.ParseUnit("furlong")
+	assert.False(t, ok)
+
+	u, ok = mesh.Parse3MFUnit("centimeter")
+	assert.True(t, ok)
+	assert.Equal(t, mesh.UnitCentimeter, u)
+
+	_, ok = mesh.Parse3MFUnit("micron")
+	assert.False(t, ok)
+
+	assert.Equal(t, "inch", mesh.UnitInch.ThreeMFName())
+	assert.Equal(t, "millimeter", mesh.UnitMillimeter.ThreeMFName())
+	assert.Equal(t, "", mesh.Unit("furlong").ThreeMFName())
+}
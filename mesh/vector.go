@@ -0,0 +1,57 @@
+package mesh
+
+import "math"
+
+// Vector is a point or direction in 3D space
+type Vector struct {
+	X, Y, Z float64
+}
+
+func (a Vector) Add(b Vector) Vector {
+	return Vector{a.X + b.X, a.Y + b.Y, a.Z + b.Z}
+}
+
+func (a Vector) Sub(b Vector) Vector {
+	return Vector{a.X - b.X, a.Y - b.Y, a.Z - b.Z}
+}
+
+func (a Vector) MulScalar(s float64) Vector {
+	return Vector{a.X * s, a.Y * s, a.Z * s}
+}
+
+func (a Vector) DivScalar(s float64) Vector {
+	return Vector{a.X / s, a.Y / s, a.Z / s}
+}
+
+func (a Vector) Dot(b Vector) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func (a Vector) Cross(b Vector) Vector {
+	return Vector{
+		a.Y*b.Z - a.Z*b.Y,
+		a.Z*b.X - a.X*b.Z,
+		a.X*b.Y - a.Y*b.X,
+	}
+}
+
+func (a Vector) Length() float64 {
+	return math.Sqrt(a.Dot(a))
+}
+
+// Normalize returns a unit length copy of the vector. The zero vector is returned unchanged.
+func (a Vector) Normalize() Vector {
+	l := a.Length()
+	if l == 0 {
+		return a
+	}
+	return a.DivScalar(l)
+}
+
+func (a Vector) Min(b Vector) Vector {
+	return Vector{math.Min(a.X, b.X), math.Min(a.Y, b.Y), math.Min(a.Z, b.Z)}
+}
+
+func (a Vector) Max(b Vector) Vector {
+	return Vector{math.Max(a.X, b.X), math.Max(a.Y, b.Y), math.Max(a.Z, b.Z)}
+}
@@ -0,0 +1,13 @@
+ALTER TABLE models DROP COLUMN IF EXISTS volume;
+ALTER TABLE models DROP COLUMN IF EXISTS surface_area;
+ALTER TABLE models DROP COLUMN IF EXISTS min_x;
+ALTER TABLE models DROP COLUMN IF EXISTS min_y;
+ALTER TABLE models DROP COLUMN IF EXISTS min_z;
+ALTER TABLE models DROP COLUMN IF EXISTS max_x;
+ALTER TABLE models DROP COLUMN IF EXISTS max_y;
+ALTER TABLE models DROP COLUMN IF EXISTS max_z;
+ALTER TABLE models DROP COLUMN IF EXISTS triangle_count;
+ALTER TABLE models DROP COLUMN IF EXISTS vertex_count;
+ALTER TABLE models DROP COLUMN IF EXISTS centroid_x;
+ALTER TABLE models DROP COLUMN IF EXISTS centroid_y;
+ALTER TABLE models DROP COLUMN IF EXISTS centroid_z;
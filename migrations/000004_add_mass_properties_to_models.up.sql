@@ -0,0 +1,14 @@
+-- Store the mass properties that are computed from each models mesh when it is uploaded
+ALTER TABLE models ADD COLUMN IF NOT EXISTS volume DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS surface_area DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS min_x DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS min_y DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS min_z DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS max_x DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS max_y DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS max_z DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS triangle_count INT NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS vertex_count INT NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS centroid_x DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS centroid_y DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS centroid_z DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Store the file format that each model was uploaded in
+ALTER TABLE models ADD COLUMN IF NOT EXISTS format TEXT NOT NULL DEFAULT 'stl';
@@ -0,0 +1,2 @@
+ALTER TABLE models DROP COLUMN IF EXISTS description;
+ALTER TABLE models DROP COLUMN IF EXISTS designer;
@@ -0,0 +1,3 @@
+-- Store the description and designer that can be read from the metadata of some file formats
+ALTER TABLE models ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE models ADD COLUMN IF NOT EXISTS designer TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,13 @@
+-- Cache the integrity analysis of each model since it never changes after upload
+CREATE TABLE IF NOT EXISTS model_analyses (
+  model_id INT PRIMARY KEY REFERENCES models (id) ON DELETE CASCADE,
+  non_manifold_edges INT NOT NULL,
+  boundary_edges INT NOT NULL,
+  holes INT NOT NULL,
+  inconsistently_wound_faces INT NOT NULL,
+  degenerate_triangles INT NOT NULL,
+  duplicate_triangles INT NOT NULL,
+  shells INT NOT NULL,
+  watertight BOOLEAN NOT NULL,
+  created_at TIMESTAMP DEFAULT NULL
+);
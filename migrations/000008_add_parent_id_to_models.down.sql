@@ -0,0 +1,2 @@
+ALTER TABLE models DROP CONSTRAINT IF EXISTS models_parent_id_fkey;
+ALTER TABLE models DROP COLUMN IF EXISTS parent_id;
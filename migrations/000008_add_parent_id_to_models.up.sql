@@ -0,0 +1,3 @@
+-- Link models that are derived from another model, such as repaired copies, to their source
+ALTER TABLE models ADD COLUMN IF NOT EXISTS parent_id INT DEFAULT NULL;
+ALTER TABLE models ADD FOREIGN KEY (parent_id) REFERENCES models (id) ON DELETE SET NULL;
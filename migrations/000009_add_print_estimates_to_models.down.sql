@@ -0,0 +1,4 @@
+ALTER TABLE models DROP COLUMN IF EXISTS print_time;
+ALTER TABLE models DROP COLUMN IF EXISTS filament_length;
+ALTER TABLE models DROP COLUMN IF EXISTS filament_weight;
+ALTER TABLE models DROP COLUMN IF EXISTS layer_count;
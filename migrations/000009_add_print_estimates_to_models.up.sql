@@ -0,0 +1,5 @@
+-- Store the print estimates that are computed from G-code models when they are uploaded
+ALTER TABLE models ADD COLUMN IF NOT EXISTS print_time DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS filament_length DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS filament_weight DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS layer_count INT NOT NULL DEFAULT 0;
@@ -0,0 +1,18 @@
+-- The catalog of materials that models can be quoted in
+CREATE TABLE IF NOT EXISTS materials (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  process TEXT NOT NULL,
+  density DOUBLE PRECISION NOT NULL,
+  cost_per_kg DOUBLE PRECISION NOT NULL,
+  colour TEXT NOT NULL DEFAULT '',
+  updated_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NULL
+);
+
+INSERT INTO materials (name, process, density, cost_per_kg, colour, updated_at, created_at) VALUES
+  ('PLA', 'fdm', 1.24, 25, 'white', NOW(), NOW()),
+  ('PETG', 'fdm', 1.27, 30, 'black', NOW(), NOW()),
+  ('ABS', 'fdm', 1.04, 28, 'grey', NOW(), NOW()),
+  ('Standard Resin', 'sla', 1.12, 90, 'grey', NOW(), NOW()),
+  ('PA12', 'sls', 1.01, 80, 'white', NOW(), NOW());
@@ -0,0 +1,15 @@
+-- What it costs to run the machines of each printing process. Markup and support density are
+-- fractions.
+CREATE TABLE IF NOT EXISTS machine_rates (
+  process TEXT PRIMARY KEY,
+  hourly_rate DOUBLE PRECISION NOT NULL,
+  build_rate DOUBLE PRECISION NOT NULL,
+  support_density DOUBLE PRECISION NOT NULL,
+  setup_fee DOUBLE PRECISION NOT NULL,
+  markup DOUBLE PRECISION NOT NULL
+);
+
+INSERT INTO machine_rates (process, hourly_rate, build_rate, support_density, setup_fee, markup) VALUES
+  ('fdm', 4, 12, 0.15, 5, 0.3),
+  ('sla', 8, 20, 0.25, 10, 0.3),
+  ('sls', 15, 80, 0, 25, 0.3);
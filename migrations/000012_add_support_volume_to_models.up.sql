@@ -0,0 +1,2 @@
+-- Store the estimated support volume of each model in the orientation that it was uploaded in
+ALTER TABLE models ADD COLUMN IF NOT EXISTS support_volume DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Store the unit that the coordinates of each model are in. Existing models are assumed to be in mm.
+ALTER TABLE models ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT 'mm';
@@ -0,0 +1,14 @@
+-- The machines that each user prints on
+CREATE TABLE IF NOT EXISTS printers (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  user_id INT NOT NULL REFERENCES users (id),
+  technology TEXT NOT NULL,
+  build_x DOUBLE PRECISION NOT NULL,
+  build_y DOUBLE PRECISION NOT NULL,
+  build_z DOUBLE PRECISION NOT NULL,
+  nozzle_sizes DOUBLE PRECISION[] NOT NULL DEFAULT '{}',
+  material_ids INT[] NOT NULL DEFAULT '{}',
+  updated_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NULL
+);
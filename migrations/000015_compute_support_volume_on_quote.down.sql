@@ -0,0 +1,3 @@
+UPDATE models SET support_volume = 0 WHERE support_volume IS NULL;
+ALTER TABLE models ALTER COLUMN support_volume SET DEFAULT 0;
+ALTER TABLE models ALTER COLUMN support_volume SET NOT NULL;
@@ -0,0 +1,5 @@
+-- The support volume of a model is estimated the first time that the model is quoted. Models without
+-- an estimate are NULL, including the ones that were stored before support was estimated at all.
+ALTER TABLE models ALTER COLUMN support_volume DROP NOT NULL;
+ALTER TABLE models ALTER COLUMN support_volume DROP DEFAULT;
+UPDATE models SET support_volume = NULL WHERE support_volume = 0;
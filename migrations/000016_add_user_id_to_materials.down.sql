@@ -0,0 +1 @@
+ALTER TABLE materials DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,3 @@
+-- Materials that users add are only available to them. The catalog that every user can quote in has
+-- no user.
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS user_id INT REFERENCES users (id);
@@ -1,8 +1,10 @@
 package model
 
 import (
+	"bytes"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/labstack/echo/v4"
@@ -10,12 +12,23 @@ import (
 	validator "gopkg.in/go-playground/validator.v9"
 
 	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/mesh"
 )
 
 type responseError struct {
 	Message string `json:"message"`
 }
 
+// contentTypes maps the formats that a model can be converted to onto their media type
+var contentTypes = map[mesh.Format]string{
+	mesh.FormatSTL:      "model/stl",
+	mesh.FormatSTLASCII: "model/stl",
+	mesh.FormatOBJ:      "model/obj",
+	mesh.FormatPLY:      "application/x-ply",
+	mesh.Format3MF:      "model/3mf",
+	mesh.FormatGLB:      "model/gltf-binary",
+}
+
 type ModelHandler struct {
 	Service domain.ModelService
 }
@@ -29,11 +42,44 @@ func NewModelHandler(e *echo.Group, s domain.ModelService) {
 	// /models...
 	e.GET("", handler.GetAll)
 	e.POST("", handler.Store)
+	e.POST("/boolean", handler.Boolean)
 	e.GET("/:id", handler.GetByID)
 	e.GET("/:id/content", handler.GetFileContent)
+	e.GET("/:id/analysis", handler.GetAnalysis)
+	e.GET("/:id/analysis/supports", handler.GetSupportAnalysis)
+	e.GET("/:id/analysis/thickness", handler.GetThicknessAnalysis)
+	e.GET("/:id/diff/:otherId", handler.GetDiff)
+	e.POST("/:id/repair", handler.Repair)
+	e.POST("/:id/split", handler.Split)
+	e.POST("/:id/scale", handler.Scale)
+	e.POST("/:id/transform", handler.Transform)
+	e.POST("/:id/orient", handler.Orient)
+	e.GET("/:id/thumbnail", handler.GetThumbnail)
+	e.GET("/:id/slices", handler.GetSlices)
+	e.POST("/:id/slice", handler.Slice)
 	e.DELETE("/:id", handler.Delete)
 }
 
+// NewPlateHandler will initialize the /plates resources endpoints
+func NewPlateHandler(e *echo.Group, s domain.ModelService) {
+	handler := &ModelHandler{
+		Service: s,
+	}
+
+	// /plates...
+	e.POST("", handler.ArrangePlate)
+}
+
+// NewNestHandler will initialize the /nests resources endpoints
+func NewNestHandler(e *echo.Group, s domain.ModelService) {
+	handler := &ModelHandler{
+		Service: s,
+	}
+
+	// /nests...
+	e.POST("", handler.Nest)
+}
+
 func (m *ModelHandler) GetAll(c echo.Context) error {
 	ctx := c.Request().Context()
 	userID := getUserIDFromRequest(c)
@@ -75,6 +121,24 @@ func (m *ModelHandler) GetFileContent(c echo.Context) error {
 	ctx := c.Request().Context()
 	userID := getUserIDFromRequest(c)
 
+	// convert the model when a format or level of detail is requested, otherwise send the originally
+	// uploaded file. Levels of detail are meant for the web viewer so they default to GLB.
+	format, lod := c.QueryParam("format"), c.QueryParam("lod")
+	if format != "" || lod != "" {
+		if format == "" {
+			format = string(mesh.FormatGLB)
+		}
+
+		data, err := m.Service.GetContent(ctx, id, userID, format, lod)
+		if err != nil {
+			return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+		}
+
+		filename := strconv.FormatInt(id, 10) + mesh.Format(format).Extension()
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+		return c.Blob(http.StatusOK, contentTypes[mesh.Format(format)], data)
+	}
+
 	downloadURL, err := m.Service.GetDirectDownloadURL(ctx, id, userID)
 	if err != nil {
 		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
@@ -83,6 +147,450 @@ func (m *ModelHandler) GetFileContent(c echo.Context) error {
 	return c.Redirect(http.StatusFound, downloadURL)
 }
 
+func (m *ModelHandler) GetAnalysis(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	analysis, err := m.Service.GetAnalysis(ctx, id, userID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, analysis)
+}
+
+// GetSupportAnalysis sends the faces of a model that need support and estimates of the support. The
+// direction query param is the build direction as x,y,z and defaults to 0,0,1, and angle is the
+// overhang angle in degrees.
+func (m *ModelHandler) GetSupportAnalysis(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	direction := domain.Point{Z: 1}
+	if d := c.QueryParam("direction"); d != "" {
+		direction, err = parsePoint(d)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, responseError{Message: domain.ErrBadParamInput.Error()})
+		}
+	}
+
+	angle := mesh.DefaultOverhangAngle
+	if a := c.QueryParam("angle"); a != "" {
+		angle, err = strconv.ParseFloat(a, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, responseError{Message: domain.ErrBadParamInput.Error()})
+		}
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	analysis, err := m.Service.GetSupportAnalysis(ctx, id, userID, direction, angle)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, analysis)
+}
+
+// GetThicknessAnalysis sends the walls of a model that are too thin and the thickness at every
+// vertex. The min query param is the thinnest wall in mm, which otherwise defaults to the thinnest
+// wall that the process query param prints.
+func (m *ModelHandler) GetThicknessAnalysis(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var min float64
+	if s := c.QueryParam("min"); s != "" {
+		min, err = strconv.ParseFloat(s, 64)
+		if err != nil || min <= 0 {
+			return c.JSON(http.StatusBadRequest, responseError{Message: domain.ErrBadParamInput.Error()})
+		}
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	analysis, err := m.Service.GetThicknessAnalysis(ctx, id, userID, min, c.QueryParam("process"))
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, analysis)
+}
+
+// GetDiff returns the deviation between the surfaces of two models
+func (m *ModelHandler) GetDiff(c echo.Context) error {
+	// convert the url params 'id' and 'otherId' from strings to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	otherID, err := strconv.ParseInt(c.Param("otherId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var align bool
+	if s := c.QueryParam("align"); s != "" {
+		align, err = strconv.ParseBool(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, responseError{Message: domain.ErrBadParamInput.Error()})
+		}
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	diff, err := m.Service.GetDiff(ctx, id, otherID, userID, align)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, diff)
+}
+
+// parsePoint reads a point written as x,y,z
+func parsePoint(s string) (domain.Point, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return domain.Point{}, domain.ErrBadParamInput
+	}
+
+	var coords [3]float64
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return domain.Point{}, err
+		}
+		coords[i] = v
+	}
+	return domain.Point{X: coords[0], Y: coords[1], Z: coords[2]}, nil
+}
+
+// Repair stores a repaired copy of a model
+func (m *ModelHandler) Repair(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	model, err := m.Service.Repair(ctx, id, userID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, model)
+}
+
+// Split stores every disconnected part of a model as its own model
+func (m *ModelHandler) Split(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	parts, err := m.Service.Split(ctx, id, userID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, parts)
+}
+
+// GetThumbnail sends a PNG preview of a model. The size query param sets its width and height.
+func (m *ModelHandler) GetThumbnail(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	size := DefaultThumbnailSize
+	if s := c.QueryParam("size"); s != "" {
+		size, err = strconv.Atoi(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, responseError{Message: domain.ErrBadParamInput.Error()})
+		}
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	data, err := m.Service.GetThumbnail(ctx, id, userID, size)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.Blob(http.StatusOK, "image/png", data)
+}
+
+// GetSlices sends the cross sections of a model as JSON, or as an SVG drawing when format=svg. The
+// z query param selects a single plane and layer_height slices the whole model instead.
+func (m *ModelHandler) GetSlices(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var z, layerHeight float64
+	if h := c.QueryParam("layer_height"); h != "" {
+		layerHeight, err = strconv.ParseFloat(h, 64)
+		if err != nil || layerHeight <= 0 {
+			return c.JSON(http.StatusBadRequest, responseError{Message: domain.ErrBadParamInput.Error()})
+		}
+	} else {
+		z, err = strconv.ParseFloat(c.QueryParam("z"), 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, responseError{Message: domain.ErrBadParamInput.Error()})
+		}
+	}
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "svg" {
+		return c.JSON(http.StatusBadRequest, responseError{Message: domain.ErrBadParamInput.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	layers, err := m.Service.GetSlices(ctx, id, userID, z, layerHeight)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	if format == "svg" {
+		var buf bytes.Buffer
+		err = writeLayersSVG(&buf, layers)
+		if err != nil {
+			return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+		}
+		return c.Blob(http.StatusOK, "image/svg+xml", buf.Bytes())
+	}
+
+	return c.JSON(http.StatusOK, layers)
+}
+
+// Slice generates the G-code to print a model and stores it as a new model. Settings in the body
+// override the default print profile.
+func (m *ModelHandler) Slice(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	profile := domain.DefaultPrintProfile()
+	err = c.Bind(&profile)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	err = validator.New().Struct(profile)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	model, err := m.Service.Slice(ctx, id, userID, profile)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, model)
+}
+
+// scaleRequest either scales a model by a factor or converts it into another unit
+type scaleRequest struct {
+	Factor float64 `json:"factor"`
+	Unit   string  `json:"unit"`
+}
+
+// Scale stores a scaled copy of a model
+func (m *ModelHandler) Scale(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req scaleRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	model, err := m.Service.Scale(ctx, id, userID, req.Factor, req.Unit)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, model)
+}
+
+// Transform stores a copy of a model with a transform applied to it
+func (m *ModelHandler) Transform(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req domain.TransformRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	err = validator.New().Struct(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	model, err := m.Service.Transform(ctx, id, userID, req)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, model)
+}
+
+// Orient ranks the orientations that a model can be printed in
+func (m *ModelHandler) Orient(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	req := domain.OrientRequest{OverhangAngle: mesh.DefaultOverhangAngle}
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	err = validator.New().Struct(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	res, err := m.Service.Orient(ctx, id, userID, req)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	// a new model is only created when the best orientation is stored
+	if res.Model != nil {
+		return c.JSON(http.StatusCreated, res)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Boolean stores the union, difference or intersection of the solids of two or more models
+func (m *ModelHandler) Boolean(c echo.Context) error {
+	var req domain.BooleanRequest
+	err := c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	err = validator.New().Struct(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	model, err := m.Service.Boolean(ctx, userID, req)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, model)
+}
+
+// ArrangePlate packs copies of models onto the bed of a printer and stores the plate as a 3MF model
+func (m *ModelHandler) ArrangePlate(c echo.Context) error {
+	var req domain.PlateRequest
+	err := c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	err = validator.New().Struct(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	plate, err := m.Service.ArrangePlate(ctx, userID, req)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, plate)
+}
+
+// Nest packs copies of models into the build volume of a powder bed printer and stores the build as
+// a 3MF model
+func (m *ModelHandler) Nest(c echo.Context) error {
+	var req domain.NestRequest
+	err := c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	err = validator.New().Struct(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	nest, err := m.Service.Nest(ctx, userID, req)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, nest)
+}
+
 func isRequestValid(m *domain.Model) (bool, error) {
 	validate := validator.New()
 	err := validate.Struct(m)
@@ -106,7 +614,8 @@ func (m *ModelHandler) Store(c echo.Context) (err error) {
 	}
 	defer src.Close()
 
-	model := &domain.Model{}
+	// the unit is detected from the size of the model unless it is given
+	model := &domain.Model{Unit: c.FormValue("unit")}
 
 	ctx := c.Request().Context()
 	err = m.Service.Store(ctx, model, src, file.Filename, userID)
@@ -151,6 +660,8 @@ func getStatusCode(err error) int {
 		return http.StatusNotFound
 	case domain.ErrConflict:
 		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
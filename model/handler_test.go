@@ -116,6 +116,746 @@ func TestHandlerGetByID(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestHandlerGetFileContentConverted(t *testing.T) {
+	mockService := new(mocks.ModelService)
+	var mockUserID int64 = 1
+
+	mockService.On("GetContent", mock.Anything, int64(1), mockUserID, "glb", "").Return([]byte("glTF"), nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/content?format=glb", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/content")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.GetFileContent(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "model/gltf-binary", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename="1.glb"`, rec.Header().Get(echo.HeaderContentDisposition))
+	assert.Equal(t, "glTF", rec.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerGetFileContentLevelOfDetail(t *testing.T) {
+	mockService := new(mocks.ModelService)
+	var mockUserID int64 = 1
+
+	mockService.On("GetContent", mock.Anything, int64(1), mockUserID, "glb", "low").Return([]byte("glTF"), nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/content?lod=low", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/content")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.GetFileContent(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "model/gltf-binary", rec.Header().Get(echo.HeaderContentType))
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerGetFileContentOriginal(t *testing.T) {
+	mockService := new(mocks.ModelService)
+	var mockUserID int64 = 1
+
+	mockService.On("GetDirectDownloadURL", mock.Anything, int64(1), mockUserID).Return("http://files/test.stl", nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/content", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/content")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.GetFileContent(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "http://files/test.stl", rec.Header().Get(echo.HeaderLocation))
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerGetAnalysis(t *testing.T) {
+	mockService := new(mocks.ModelService)
+	var mockUserID int64 = 1
+
+	mockAnalysis := domain.MeshAnalysis{ModelID: 1, Shells: 1, Watertight: true}
+	mockService.On("GetAnalysis", mock.Anything, int64(1), mockUserID).Return(mockAnalysis, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/analysis", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/analysis")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.GetAnalysis(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"watertight":true`)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerGetSupportAnalysis(t *testing.T) {
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name      string
+		query     string
+		direction domain.Point
+		angle     float64
+	}{
+		{"defaults", "", domain.Point{Z: 1}, 45},
+		{"direction-and-angle", "direction=1,0,0&angle=60", domain.Point{X: 1}, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.ModelService)
+			mockAnalysis := domain.SupportAnalysis{ModelID: 1, OverhangFaces: 1, Mask: []bool{false, true}}
+			mockService.On("GetSupportAnalysis", mock.Anything, int64(1), mockUserID, tt.direction, tt.angle).Return(mockAnalysis, nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.GET, "/models/1/analysis/supports?"+tt.query, nil)
+			assert.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("models/:id/analysis/supports")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := model.ModelHandler{
+				Service: mockService,
+			}
+			err = handler.GetSupportAnalysis(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"mask":[false,true]`)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandlerGetSupportAnalysisInvalidDirection(t *testing.T) {
+	mockService := new(mocks.ModelService)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/analysis/supports?direction=0,1", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/analysis/supports")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(1))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.GetSupportAnalysis(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandlerGetThicknessAnalysis(t *testing.T) {
+	mockService := new(mocks.ModelService)
+	var mockUserID int64 = 1
+
+	thickness := 1.5
+	mockAnalysis := domain.ThicknessAnalysis{ModelID: 1, Process: "sla", MinThickness: 0.8, Thickness: []*float64{&thickness, nil}}
+	mockService.On("GetThicknessAnalysis", mock.Anything, int64(1), mockUserID, 0.8, "sla").Return(mockAnalysis, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/analysis/thickness?min=0.8&process=sla", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/analysis/thickness")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.GetThicknessAnalysis(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"thickness":[1.5,null]`)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerGetDiff(t *testing.T) {
+	mockService := new(mocks.ModelService)
+	var mockUserID int64 = 1
+
+	mockDiff := domain.Diff{ModelID: 1, OtherID: 2, Aligned: true, Hausdorff: 0.5, Deviation: []float64{0.5, -0.25}}
+	mockService.On("GetDiff", mock.Anything, int64(1), int64(2), mockUserID, true).Return(mockDiff, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/diff/2?align=true", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/diff/:otherId")
+	c.SetParamNames("id", "otherId")
+	c.SetParamValues("1", "2")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.GetDiff(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"deviation":[0.5,-0.25]`)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerRepair(t *testing.T) {
+	mockService := new(mocks.ModelService)
+	var mockUserID int64 = 1
+
+	var parentID int64 = 1
+	mockRepaired := domain.Model{ID: 2, Name: "test-repaired.stl", UserID: mockUserID, ParentID: &parentID}
+	mockService.On("Repair", mock.Anything, int64(1), mockUserID).Return(mockRepaired, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/models/1/repair", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/repair")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.Repair(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"parent_id":1`)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerSplit(t *testing.T) {
+	mockService := new(mocks.ModelService)
+	var mockUserID int64 = 1
+
+	var parentID int64 = 1
+	mockParts := []domain.Model{
+		{ID: 2, Name: "test-part-1.stl", UserID: mockUserID, ParentID: &parentID},
+		{ID: 3, Name: "test-part-2.stl", UserID: mockUserID, ParentID: &parentID},
+	}
+	mockService.On("Split", mock.Anything, int64(1), mockUserID).Return(mockParts, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/models/1/split", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/split")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.Split(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"test-part-2.stl"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerScale(t *testing.T) {
+	var mockUserID int64 = 1
+
+	var parentID int64 = 1
+	mockScaled := domain.Model{ID: 2, Name: "test-scaled.stl", UserID: mockUserID, Unit: "mm", ParentID: &parentID}
+	mockService := new(mocks.ModelService)
+	mockService.On("Scale", mock.Anything, int64(1), mockUserID, 0.0, "mm").Return(mockScaled, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/models/1/scale", strings.NewReader(`{"unit":"mm"}`))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/scale")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.Scale(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"unit":"mm"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerTransform(t *testing.T) {
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"operations", `{"operations":[{"type":"mirror","axis":"x"}],"drop_to_plate":true}`, http.StatusCreated},
+		{"unknown-operation", `{"operations":[{"type":"shear"}]}`, http.StatusBadRequest},
+		{"unknown-axis", `{"operations":[{"type":"rotate","axis":"w","angle":90}]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parentID int64 = 1
+			mockTransformed := domain.Model{ID: 2, Name: "test-transformed.stl", UserID: mockUserID, ParentID: &parentID}
+			expected := domain.TransformRequest{
+				Operations:  []domain.TransformOperation{{Type: "mirror", Axis: "x"}},
+				DropToPlate: true,
+			}
+			mockService := new(mocks.ModelService)
+			mockService.On("Transform", mock.Anything, int64(1), mockUserID, expected).Return(mockTransformed, nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/models/1/transform", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("models/:id/transform")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := model.ModelHandler{
+				Service: mockService,
+			}
+			err = handler.Transform(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code == http.StatusCreated {
+				assert.Contains(t, rec.Body.String(), `"parent_id":1`)
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestHandlerOrient(t *testing.T) {
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name     string
+		body     string
+		expected domain.OrientRequest
+		code     int
+	}{
+		{"default-angle", ``, domain.OrientRequest{OverhangAngle: 45}, http.StatusOK},
+		{"store", `{"overhang_angle":60,"store":true}`, domain.OrientRequest{OverhangAngle: 60, Store: true}, http.StatusCreated},
+		{"invalid-angle", `{"overhang_angle":90}`, domain.OrientRequest{}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := domain.OrientResult{Candidates: []domain.Orientation{{Down: domain.Point{Z: -1}, BuildHeight: 10}}}
+			if tt.expected.Store {
+				res.Model = &domain.Model{ID: 2, Name: "test-oriented.stl"}
+			}
+			mockService := new(mocks.ModelService)
+			mockService.On("Orient", mock.Anything, int64(1), mockUserID, tt.expected).Return(res, nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/models/1/orient", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("models/:id/orient")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := model.ModelHandler{
+				Service: mockService,
+			}
+			err = handler.Orient(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code != http.StatusBadRequest {
+				assert.Contains(t, rec.Body.String(), `"build_height":10`)
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestHandlerBoolean(t *testing.T) {
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"difference", `{"model_ids":[1,2],"operation":"difference"}`, http.StatusCreated},
+		{"single-model", `{"model_ids":[1],"operation":"difference"}`, http.StatusBadRequest},
+		{"unknown-operation", `{"model_ids":[1,2],"operation":"xor"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parentID int64 = 1
+			mockResult := domain.Model{ID: 3, Name: "test-difference.stl", UserID: mockUserID, ParentID: &parentID}
+			expected := domain.BooleanRequest{ModelIDs: []int64{1, 2}, Operation: "difference"}
+			mockService := new(mocks.ModelService)
+			mockService.On("Boolean", mock.Anything, mockUserID, expected).Return(mockResult, nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/models/boolean", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("models/boolean")
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := model.ModelHandler{
+				Service: mockService,
+			}
+			err = handler.Boolean(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code == http.StatusCreated {
+				assert.Contains(t, rec.Body.String(), `"parent_id":1`)
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "Boolean", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestHandlerArrangePlate(t *testing.T) {
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"free", `{"items":[{"model_id":1,"quantity":4}],"bed_x":250,"bed_y":210,"spacing":2,"rotation":"free"}`, http.StatusCreated},
+		{"no-items", `{"items":[],"bed_x":250,"bed_y":210}`, http.StatusBadRequest},
+		{"no-quantity", `{"items":[{"model_id":1}],"bed_x":250,"bed_y":210}`, http.StatusBadRequest},
+		{"no-bed", `{"items":[{"model_id":1,"quantity":4}]}`, http.StatusBadRequest},
+		{"unknown-rotation", `{"items":[{"model_id":1,"quantity":4}],"bed_x":250,"bed_y":210,"rotation":"45"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPlate := domain.Plate{Model: domain.Model{ID: 2, Name: "plate.3mf", UserID: mockUserID}}
+			expected := domain.PlateRequest{
+				Items:    []domain.PlateItem{{ModelID: 1, Quantity: 4}},
+				BedX:     250,
+				BedY:     210,
+				Spacing:  2,
+				Rotation: "free",
+			}
+			mockService := new(mocks.ModelService)
+			mockService.On("ArrangePlate", mock.Anything, mockUserID, expected).Return(mockPlate, nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/plates", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("plates")
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := model.ModelHandler{
+				Service: mockService,
+			}
+			err = handler.ArrangePlate(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code == http.StatusCreated {
+				assert.Contains(t, rec.Body.String(), `"name":"plate.3mf"`)
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ArrangePlate", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestHandlerNest(t *testing.T) {
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"cylinder", `{"items":[{"model_id":1,"quantity":20}],"shape":"cylinder","build_x":300,"build_z":300,"spacing":3}`, http.StatusCreated},
+		{"no-items", `{"items":[],"build_x":300,"build_z":300}`, http.StatusBadRequest},
+		{"no-height", `{"items":[{"model_id":1,"quantity":20}],"shape":"cylinder","build_x":300}`, http.StatusBadRequest},
+		{"unknown-shape", `{"items":[{"model_id":1,"quantity":20}],"shape":"sphere","build_x":300,"build_z":300}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockNest := domain.Nest{Model: domain.Model{ID: 2, Name: "build.3mf", UserID: mockUserID}}
+			expected := domain.NestRequest{
+				Items:   []domain.PlateItem{{ModelID: 1, Quantity: 20}},
+				Shape:   "cylinder",
+				BuildX:  300,
+				BuildZ:  300,
+				Spacing: 3,
+			}
+			mockService := new(mocks.ModelService)
+			mockService.On("Nest", mock.Anything, mockUserID, expected).Return(mockNest, nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/nests", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("nests")
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := model.ModelHandler{
+				Service: mockService,
+			}
+			err = handler.Nest(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code == http.StatusCreated {
+				assert.Contains(t, rec.Body.String(), `"name":"build.3mf"`)
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "Nest", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestHandlerGetThumbnail(t *testing.T) {
+	mockService := new(mocks.ModelService)
+	var mockUserID int64 = 1
+
+	mockService.On("GetThumbnail", mock.Anything, int64(1), mockUserID, 128).Return([]byte("\x89PNG"), nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/thumbnail?size=128", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/thumbnail")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(mockUserID))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.GetThumbnail(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerGetSlices(t *testing.T) {
+	mockLayers := []domain.Layer{{
+		Z: 1,
+		Polygons: []domain.Polygon{{
+			Outline: []domain.Point2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}},
+			Holes:   [][]domain.Point2D{{{X: 2, Y: 2}, {X: 2, Y: 8}, {X: 8, Y: 8}, {X: 8, Y: 2}}},
+		}},
+	}}
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name        string
+		query       string
+		z           float64
+		layerHeight float64
+		contentType string
+		body        string
+	}{
+		{"json", "z=1", 1, 0, echo.MIMEApplicationJSONCharsetUTF8, `"outline":[{"x":0,"y":0}`},
+		{"svg", "layer_height=2&format=svg", 0, 2, "image/svg+xml", `d="M0 10 L10 10 L10 0 L0 0 Z M2 8 L2 2 L8 2 L8 8 Z"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.ModelService)
+			mockService.On("GetSlices", mock.Anything, int64(1), mockUserID, tt.z, tt.layerHeight).Return(mockLayers, nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.GET, "/models/1/slices?"+tt.query, nil)
+			assert.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("models/:id/slices")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := model.ModelHandler{
+				Service: mockService,
+			}
+			err = handler.GetSlices(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.contentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Contains(t, rec.Body.String(), tt.body)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandlerGetSlicesMissingHeight(t *testing.T) {
+	mockService := new(mocks.ModelService)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/slices", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/slices")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(1))
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.GetSlices(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandlerSlice(t *testing.T) {
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"default-profile", ``, http.StatusCreated},
+		{"custom-profile", `{"layer_height":0.1,"infill_pattern":"grid"}`, http.StatusCreated},
+		{"invalid-profile", `{"infill_density":1.5}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := domain.DefaultPrintProfile()
+			if tt.name == "custom-profile" {
+				expected.LayerHeight = 0.1
+				expected.InfillPattern = "grid"
+			}
+
+			var parentID int64 = 1
+			mockGCode := domain.Model{ID: 2, Name: "test.gcode", Format: "gcode", ParentID: &parentID}
+			mockService := new(mocks.ModelService)
+			mockService.On("Slice", mock.Anything, int64(1), mockUserID, expected).Return(mockGCode, nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/models/1/slice", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("models/:id/slice")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			c.Set("user", mockTokenWithUserID(mockUserID))
+
+			handler := model.ModelHandler{
+				Service: mockService,
+			}
+			err = handler.Slice(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code == http.StatusCreated {
+				assert.Contains(t, rec.Body.String(), `"format":"gcode"`)
+				mockService.AssertExpectations(t)
+			}
+		})
+	}
+}
+
 func TestHandlerStore(t *testing.T) {
 	var mockModel domain.Model
 	err := faker.FakeData(&mockModel)
@@ -152,6 +892,36 @@ func TestHandlerStore(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestHandlerStoreInvalidFile(t *testing.T) {
+	mockService := new(mocks.ModelService)
+
+	var mockUserID int64 = 1
+
+	mockService.On("Store", mock.Anything, mock.AnythingOfType("*domain.Model"), mock.Anything, "test.stl", mockUserID).Return(domain.ErrBadParamInput)
+
+	e := echo.New()
+	formData, multipartBoundary, err := mockFormData()
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(echo.POST, "/models", formData)
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", multipartBoundary)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user", mockTokenWithUserID(mockUserID))
+	c.SetPath("/models")
+
+	handler := model.ModelHandler{
+		Service: mockService,
+	}
+	err = handler.Store(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestHandlerDelete(t *testing.T) {
 	var mockModel domain.Model
 	err := faker.FakeData(&mockModel)
@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package integration
 
 import (
@@ -14,7 +17,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/rknizzle/rkmesh/filestore"
 	"github.com/rknizzle/rkmesh/model"
-	"github.com/rknizzle/rkmesh/testFileStore"
+	"github.com/rknizzle/rkmesh/testFilestore"
 	"github.com/rknizzle/rkmesh/testdb"
 )
 
@@ -78,6 +81,9 @@ func runDatabaseMigrations(dbConn *sql.DB) error {
 		return err
 	}
 
-	mig.Steps(2)
+	err = mig.Up()
+	if err != nil && err != migrate.ErrNoChange {
+		return err
+	}
 	return nil
 }
@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package integration
 
 import (
@@ -28,9 +31,10 @@ var tdb testdb.TestDB
 
 // GET /models
 func TestGetAll(t *testing.T) {
-	tdb.Truncate()
+	err := tdb.Truncate()
+	require.NoError(t, err)
 
-	_, err := tdb.SeedUsers()
+	_, err = tdb.SeedUsers()
 	assert.NoError(t, err)
 
 	_, err = tdb.SeedModels()
@@ -46,6 +46,29 @@ func (p *postgresModelRepository) fetch(ctx context.Context, query string, args
 			&t.UpdatedAt,
 			&t.CreatedAt,
 			&t.UserID,
+			&t.Volume,
+			&t.SurfaceArea,
+			&t.BoundingBox.Min.X,
+			&t.BoundingBox.Min.Y,
+			&t.BoundingBox.Min.Z,
+			&t.BoundingBox.Max.X,
+			&t.BoundingBox.Max.Y,
+			&t.BoundingBox.Max.Z,
+			&t.TriangleCount,
+			&t.VertexCount,
+			&t.Centroid.X,
+			&t.Centroid.Y,
+			&t.Centroid.Z,
+			&t.Format,
+			&t.Description,
+			&t.Designer,
+			&t.ParentID,
+			&t.PrintTime,
+			&t.FilamentLength,
+			&t.FilamentWeight,
+			&t.LayerCount,
+			&t.SupportVolume,
+			&t.Unit,
 		)
 
 		if err != nil {
@@ -103,14 +126,30 @@ func (p *postgresModelRepository) GetByName(ctx context.Context, name string) (r
 }
 
 func (p *postgresModelRepository) Store(ctx context.Context, m *domain.Model) (err error) {
-	query := `INSERT INTO models (name, user_id, download_id, updated_at, created_at) VALUES ($1, $2, $3, NOW(), NOW()) RETURNING id`
+	query := `INSERT INTO models (name, user_id, download_id, updated_at, created_at,
+		volume, surface_area, min_x, min_y, min_z, max_x, max_y, max_z,
+		triangle_count, vertex_count, centroid_x, centroid_y, centroid_z, format,
+		description, designer, parent_id, print_time, filament_length, filament_weight, layer_count,
+		support_volume, unit)
+		VALUES ($1, $2, $3, NOW(), NOW(), $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+		$18, $19, $20, $21, $22, $23, $24, $25, $26)
+		RETURNING id`
 	stmt, err := p.Conn.PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
 	var ID int64
-	err = stmt.QueryRowContext(ctx, m.Name, m.UserID, m.DownloadID).Scan(&ID)
+	err = stmt.QueryRowContext(ctx, m.Name, m.UserID, m.DownloadID,
+		m.Volume, m.SurfaceArea,
+		m.BoundingBox.Min.X, m.BoundingBox.Min.Y, m.BoundingBox.Min.Z,
+		m.BoundingBox.Max.X, m.BoundingBox.Max.Y, m.BoundingBox.Max.Z,
+		m.TriangleCount, m.VertexCount,
+		m.Centroid.X, m.Centroid.Y, m.Centroid.Z, m.Format,
+		m.Description, m.Designer, m.ParentID,
+		m.PrintTime, m.FilamentLength, m.FilamentWeight, m.LayerCount,
+		m.SupportVolume, m.Unit,
+	).Scan(&ID)
 	if err != nil {
 		return
 	}
@@ -144,3 +183,62 @@ func (p *postgresModelRepository) Delete(ctx context.Context, id int64) (err err
 
 	return
 }
+
+func (p *postgresModelRepository) StoreSupportVolume(ctx context.Context, id int64, volume float64) (err error) {
+	query := `UPDATE models SET support_volume = $1, updated_at = NOW() WHERE id = $2`
+
+	stmt, err := p.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, volume, id)
+	return
+}
+
+func (p *postgresModelRepository) GetAnalysis(ctx context.Context, modelID int64) (res domain.MeshAnalysis, err error) {
+	query := `SELECT * FROM model_analyses WHERE model_id = $1`
+
+	err = p.Conn.QueryRowContext(ctx, query, modelID).Scan(
+		&res.ModelID,
+		&res.NonManifoldEdges,
+		&res.BoundaryEdges,
+		&res.Holes,
+		&res.InconsistentlyWoundFaces,
+		&res.DegenerateTriangles,
+		&res.DuplicateTriangles,
+		&res.Shells,
+		&res.Watertight,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.MeshAnalysis{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.MeshAnalysis{}, err
+	}
+
+	return
+}
+
+func (p *postgresModelRepository) StoreAnalysis(ctx context.Context, a *domain.MeshAnalysis) (err error) {
+	query := `INSERT INTO model_analyses (model_id, non_manifold_edges, boundary_edges, holes,
+		inconsistently_wound_faces, degenerate_triangles, duplicate_triangles, shells, watertight, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		ON CONFLICT (model_id) DO NOTHING
+		RETURNING created_at`
+	stmt, err := p.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	err = stmt.QueryRowContext(ctx, a.ModelID, a.NonManifoldEdges, a.BoundaryEdges, a.Holes,
+		a.InconsistentlyWoundFaces, a.DegenerateTriangles, a.DuplicateTriangles, a.Shells, a.Watertight,
+	).Scan(&a.CreatedAt)
+	// another request already cached the analysis of this model
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return
+}
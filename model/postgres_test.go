@@ -0,0 +1,125 @@
+package model_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/model"
+)
+
+var modelColumns = []string{
+	"id", "name", "download_id", "updated_at", "created_at", "user_id",
+	"volume", "surface_area", "min_x", "min_y", "min_z", "max_x", "max_y", "max_z",
+	"triangle_count", "vertex_count", "centroid_x", "centroid_y", "centroid_z", "format",
+	"description", "designer", "parent_id",
+	"print_time", "filament_length", "filament_weight", "layer_count",
+	"support_volume", "unit",
+}
+
+func TestPostgresGetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows(modelColumns).
+		AddRow(1, "test.stl", "xxx", time.Now(), time.Now(), 1,
+			2.5, 12.0, 0, 0, 0, 1, 2, 3,
+			12, 8, 0.5, 1, 1.5, "stl",
+			"A test part", "Ryan", nil,
+			0, 0, 0, 0,
+			1.5, "inch")
+
+	query := "[SELECT * FROM models WHERE id = $1 AND user_id = $2]"
+
+	mock.ExpectQuery(query).WillReturnRows(rows)
+	p := model.NewPostgresModelRepository(db)
+
+	m, err := p.GetByID(context.TODO(), 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, m.Volume)
+	assert.Equal(t, domain.Point{X: 1, Y: 2, Z: 3}, m.BoundingBox.Max)
+	assert.Equal(t, int64(12), m.TriangleCount)
+	assert.Equal(t, domain.Point{X: 0.5, Y: 1, Z: 1.5}, m.Centroid)
+	assert.Equal(t, "stl", m.Format)
+	assert.Equal(t, "A test part", m.Description)
+	assert.Equal(t, "Ryan", m.Designer)
+	assert.Nil(t, m.ParentID)
+	if assert.NotNil(t, m.SupportVolume) {
+		assert.Equal(t, 1.5, *m.SupportVolume)
+	}
+	assert.Equal(t, "inch", m.Unit)
+}
+
+func TestPostgresStore(t *testing.T) {
+	m := &domain.Model{
+		Name:          "test.stl",
+		UserID:        1,
+		DownloadID:    "xxx",
+		Format:        "stl",
+		Description:   "A test part",
+		Designer:      "Ryan",
+		Volume:        2.5,
+		SurfaceArea:   12,
+		BoundingBox:   domain.BoundingBox{Max: domain.Point{X: 1, Y: 2, Z: 3}},
+		TriangleCount: 12,
+		VertexCount:   8,
+		Centroid:      domain.Point{X: 0.5, Y: 1, Z: 1.5},
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	prep := mock.ExpectPrepare("INSERT INTO models")
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+	prep.ExpectQuery().WithArgs(m.Name, m.UserID, m.DownloadID,
+		m.Volume, m.SurfaceArea, 0.0, 0.0, 0.0, 1.0, 2.0, 3.0,
+		m.TriangleCount, m.VertexCount, 0.5, 1.0, 1.5, m.Format,
+		m.Description, m.Designer, m.ParentID,
+		m.PrintTime, m.FilamentLength, m.FilamentWeight, m.LayerCount,
+		m.SupportVolume, m.Unit,
+	).WillReturnRows(rows)
+
+	p := model.NewPostgresModelRepository(db)
+
+	err = p.Store(context.TODO(), m)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), m.ID)
+}
+
+func TestPostgresStoreSupportVolume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	prep := mock.ExpectPrepare("UPDATE models SET support_volume")
+	prep.ExpectExec().WithArgs(300.0, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	p := model.NewPostgresModelRepository(db)
+
+	err = p.StoreSupportVolume(context.TODO(), 1, 300)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresGetAnalysisNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"model_id"})
+	mock.ExpectQuery("SELECT (.+) FROM model_analyses").WithArgs(1).WillReturnRows(rows)
+
+	p := model.NewPostgresModelRepository(db)
+
+	_, err = p.GetAnalysis(context.TODO(), 1)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
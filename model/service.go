@@ -1,11 +1,88 @@
 package model
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"image/png"
 	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/gcode"
+	"github.com/rknizzle/rkmesh/mesh"
+	"github.com/rknizzle/rkmesh/slicer"
+)
+
+const (
+	// DefaultThumbnailSize is the width and height of the thumbnail that is rendered on upload
+	DefaultThumbnailSize = 256
+	minThumbnailSize     = 16
+	maxThumbnailSize     = 1024
+)
+
+// lodLevel is a reduced level of detail of a model
+type lodLevel struct {
+	// fraction is the part of the triangles of the original mesh that the level keeps
+	fraction float64
+	// from is the level that this level is reduced from, or empty to reduce the original mesh
+	from string
+}
+
+// lods are the reduced levels of detail that a model can be viewed at. Lower levels are reduced
+// from the level above them so that they do not have to decimate the whole original mesh.
+var lods = map[string]lodLevel{
+	"medium": {fraction: 0.1},
+	"low":    {fraction: 0.01, from: "medium"},
+}
+
+// minLODTriangles is the fewest triangles that a level of detail is reduced to. Small meshes are
+// already light enough to view and decimating them further only destroys their shape.
+const minLODTriangles = 1000
+
+// gcodeExt is the extension of G-code files
+const gcodeExt = ".gcode"
+
+// maxLayers is the most layers a model can be sliced into at once
+const maxLayers = 10000
+
+// maxOrientations is the number of the best orientations that are returned for a model
+const maxOrientations = 10
+
+// maxSplitParts is the most parts that a model can be split into
+const maxSplitParts = 100
+
+// minWallThickness is the thinnest wall in mm that each process prints reliably
+var minWallThickness = map[string]float64{
+	"fdm": 0.8,
+	"sla": 0.4,
+	"sls": 0.7,
+}
+
+// defaultProcess is the process that thickness is checked for when none is given
+const defaultProcess = "fdm"
+
+const (
+	// defaultPlateName is the name of plates that are stored without one
+	defaultPlateName = "plate"
+	// defaultNestName is the name of nested builds that are stored without one
+	defaultNestName = "build"
+	// maxPlateCopies is the most copies of models that can be arranged on a plate or nested in a
+	// build volume at once
+	maxPlateCopies = 1000
+)
+
+// shapes of the build volumes that models are nested in
+const (
+	boxShape      = "box"
+	cylinderShape = "cylinder"
 )
 
 type modelService struct {
@@ -31,6 +108,10 @@ func (m *modelService) GetAllUserModels(c context.Context, userID int64) (res []
 		return nil, err
 	}
 
+	for i := range res {
+		setThumbnailURL(&res[i])
+	}
+
 	return
 }
 
@@ -43,6 +124,7 @@ func (m *modelService) GetByID(c context.Context, id int64, userID int64) (res d
 		return
 	}
 
+	setThumbnailURL(&res)
 	return
 }
 
@@ -63,6 +145,739 @@ func (m *modelService) GetDirectDownloadURL(c context.Context, id int64, userID
 	return url, nil
 }
 
+// GetContent returns the mesh of a model converted to the requested format and optionally reduced
+// to one of the levels of detail. Converted files are cached in the filestore so that each format
+// and level of detail is only generated once per model, on the first request for it.
+func (m *modelService) GetContent(c context.Context, id int64, userID int64, format string, lod string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	f := mesh.Format(format)
+	if !f.CanWrite() {
+		return nil, domain.ErrBadParamInput
+	}
+	if _, ok := lods[lod]; lod != "" && !ok {
+		return nil, domain.ErrBadParamInput
+	}
+
+	model, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := m.download(ctx, contentFileID(model.DownloadID, f, lod))
+	if err == nil {
+		return data, nil
+	}
+	if err != domain.ErrNotFound {
+		return nil, err
+	}
+
+	parsed, err := m.loadMesh(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if lod != "" {
+		parsed, err = m.decimateLOD(ctx, model, parsed, lod)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m.storeContent(ctx, model, parsed, f, lod)
+}
+
+// GetAnalysis returns the integrity analysis of a model. The analysis is computed from the mesh the
+// first time it is requested and then cached in the database.
+func (m *modelService) GetAnalysis(c context.Context, id int64, userID int64) (domain.MeshAnalysis, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	model, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return domain.MeshAnalysis{}, err
+	}
+
+	analysis, err := m.modelRepo.GetAnalysis(ctx, model.ID)
+	if err != domain.ErrNotFound {
+		return analysis, err
+	}
+
+	parsed, err := m.loadMesh(ctx, model)
+	if err != nil {
+		return domain.MeshAnalysis{}, err
+	}
+
+	a := parsed.Analyze()
+	analysis = domain.MeshAnalysis{
+		ModelID:                  model.ID,
+		NonManifoldEdges:         int64(a.NonManifoldEdges),
+		BoundaryEdges:            int64(a.BoundaryEdges),
+		Holes:                    int64(a.Holes),
+		InconsistentlyWoundFaces: int64(a.InconsistentlyWoundFaces),
+		DegenerateTriangles:      int64(a.DegenerateTriangles),
+		DuplicateTriangles:       int64(a.DuplicateTriangles),
+		Shells:                   int64(a.Shells),
+		Watertight:               a.Watertight,
+	}
+
+	err = m.modelRepo.StoreAnalysis(ctx, &analysis)
+	if err != nil {
+		return domain.MeshAnalysis{}, err
+	}
+
+	return analysis, nil
+}
+
+// GetSupportAnalysis finds the faces of a model that need support when it is built up along a
+// direction and estimates the support that they need
+func (m *modelService) GetSupportAnalysis(c context.Context, id int64, userID int64, direction domain.Point, overhangAngle float64) (domain.SupportAnalysis, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	up := mesh.Vector{X: direction.X, Y: direction.Y, Z: direction.Z}
+	if up.Length() == 0 || math.IsNaN(up.Length()) || math.IsInf(up.Length(), 0) {
+		return domain.SupportAnalysis{}, domain.ErrBadParamInput
+	}
+	if !(overhangAngle > 0 && overhangAngle < 90) {
+		return domain.SupportAnalysis{}, domain.ErrBadParamInput
+	}
+
+	model, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return domain.SupportAnalysis{}, err
+	}
+
+	parsed, err := m.loadMesh(ctx, model)
+	if err != nil {
+		return domain.SupportAnalysis{}, err
+	}
+
+	a, err := parsed.AnalyzeSupport(ctx, up, overhangAngle)
+	if err != nil {
+		return domain.SupportAnalysis{}, err
+	}
+
+	res := domain.SupportAnalysis{
+		ModelID:       model.ID,
+		Direction:     toPoint(up.Normalize()),
+		OverhangAngle: overhangAngle,
+		OverhangArea:  a.OverhangArea,
+		SupportVolume: a.SupportVolume,
+		ContactArea:   a.ContactArea,
+		Mask:          a.Overhangs,
+	}
+	for _, overhang := range a.Overhangs {
+		if overhang {
+			res.OverhangFaces++
+		}
+	}
+	return res, nil
+}
+
+// GetThicknessAnalysis measures the walls of a model and finds the areas that are thinner than a
+// minimum. The minimum defaults to the thinnest wall that the process prints reliably.
+func (m *modelService) GetThicknessAnalysis(c context.Context, id int64, userID int64, minThickness float64, process string) (domain.ThicknessAnalysis, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	if process == "" {
+		process = defaultProcess
+	}
+	processMin, ok := minWallThickness[process]
+	if !ok {
+		return domain.ThicknessAnalysis{}, domain.ErrBadParamInput
+	}
+	if minThickness == 0 {
+		minThickness = processMin
+	}
+	if minThickness < 0 || math.IsNaN(minThickness) || math.IsInf(minThickness, 0) {
+		return domain.ThicknessAnalysis{}, domain.ErrBadParamInput
+	}
+
+	model, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return domain.ThicknessAnalysis{}, err
+	}
+
+	parsed, err := m.loadMesh(ctx, model)
+	if err != nil {
+		return domain.ThicknessAnalysis{}, err
+	}
+
+	// minimums are given in mm
+	if f := unitOf(model).Millimeters(); f != 1 {
+		parsed = parsed.Transform(mesh.Scaling(f, f, f))
+	}
+
+	a := parsed.AnalyzeThickness(minThickness)
+	res := domain.ThicknessAnalysis{
+		ModelID:      model.ID,
+		Process:      process,
+		MinThickness: minThickness,
+		Thinnest:     finite(a.Thinnest),
+		ThinArea:     a.ThinArea,
+		Regions:      make([]domain.ThinRegion, len(a.Regions)),
+		Thickness:    make([]*float64, len(a.Vertices)),
+	}
+	for i, r := range a.Regions {
+		res.Regions[i] = domain.ThinRegion{
+			Triangles: int64(len(r.Triangles)),
+			Area:      r.Area,
+			Thinnest:  r.Thinnest,
+			BoundingBox: domain.BoundingBox{
+				Min: toPoint(r.Bounds.Min),
+				Max: toPoint(r.Bounds.Max),
+			},
+		}
+	}
+	for i, t := range a.Vertices {
+		res.Thickness[i] = finite(t)
+	}
+	return res, nil
+}
+
+// GetDiff measures how far the surface of a model lies from the surface of another model, such as
+// an earlier revision of it. With align set the model is first fitted onto the other model so that
+// only changes in shape are measured and not a change in position.
+func (m *modelService) GetDiff(c context.Context, id int64, otherID int64, userID int64, align bool) (domain.Diff, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	model, parsed, scale, err := m.loadMillimeters(ctx, id, userID)
+	if err != nil {
+		return domain.Diff{}, err
+	}
+	_, other, _, err := m.loadMillimeters(ctx, otherID, userID)
+	if err != nil {
+		return domain.Diff{}, err
+	}
+
+	transform := scale
+	if align {
+		fit := parsed.AlignTo(other)
+		parsed = parsed.Transform(fit)
+		transform = fit.Mul(scale)
+	}
+
+	d := parsed.Deviation(other)
+	return domain.Diff{
+		ModelID:   model.ID,
+		OtherID:   otherID,
+		Aligned:   align,
+		Matrix:    toMatrix(transform),
+		Hausdorff: d.Max,
+		Mean:      d.Mean,
+		RMS:       d.RMS,
+		Deviation: d.Vertices,
+	}, nil
+}
+
+// Repair fixes the common defects of a models mesh and stores the result as a new model that is
+// linked to the original. The original upload is never modified.
+func (m *modelService) Repair(c context.Context, id int64, userID int64) (domain.Model, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	source, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	parsed, err := m.loadMesh(ctx, source)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	return m.storeDerived(ctx, source, parsed.Repair(), "repaired")
+}
+
+// Split stores every shell of a models mesh as a new model that is linked to the original, named
+// after it with -part-N. Models with a single shell, or with more than maxSplitParts, are not
+// split.
+func (m *modelService) Split(c context.Context, id int64, userID int64) ([]domain.Model, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	source, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := m.loadMesh(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := parsed.Parts()
+	if len(parts) < 2 || len(parts) > maxSplitParts {
+		return nil, domain.ErrBadParamInput
+	}
+
+	res := make([]domain.Model, len(parts))
+	for i, part := range parts {
+		res[i], err = m.storeDerived(ctx, source, part, fmt.Sprintf("part-%d", i+1))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Scale stores a copy of a model that is scaled by a factor. When a unit is given instead, the model
+// is converted into that unit so that it keeps its real size.
+func (m *modelService) Scale(c context.Context, id int64, userID int64, factor float64, unit string) (domain.Model, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	if (factor == 0) == (unit == "") {
+		return domain.Model{}, domain.ErrBadParamInput
+	}
+	if factor < 0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		return domain.Model{}, domain.ErrBadParamInput
+	}
+
+	source, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	// the scaled copy is stored in the unit that it is converted to
+	derived := source
+	derived.Unit = string(unitOf(source))
+	if unit != "" {
+		target, ok := mesh.ParseUnit(unit)
+		if !ok {
+			return domain.Model{}, domain.ErrBadParamInput
+		}
+		factor = unitOf(source).Millimeters() / target.Millimeters()
+		derived.Unit = string(target)
+	}
+
+	parsed, err := m.loadMesh(ctx, source)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	return m.storeDerived(ctx, derived, parsed.Transform(mesh.Scaling(factor, factor, factor)), "scaled")
+}
+
+// Transform stores a copy of a model that is rotated, moved, scaled or mirrored
+func (m *modelService) Transform(c context.Context, id int64, userID int64, req domain.TransformRequest) (domain.Model, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	matrix, err := transformMatrix(req)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	source, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	parsed, err := m.loadMesh(ctx, source)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	transformed := parsed.Transform(matrix)
+	if req.DropToPlate {
+		transformed = transformed.Transform(mesh.Translation(mesh.Vector{Z: -transformed.Bounds().Min.Z}))
+	}
+
+	return m.storeDerived(ctx, source, transformed, "transformed")
+}
+
+// Orient ranks the orientations that a model can be printed in and optionally stores a copy of the
+// model in the best one, standing on the build plate
+func (m *modelService) Orient(c context.Context, id int64, userID int64, req domain.OrientRequest) (domain.OrientResult, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	source, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return domain.OrientResult{}, err
+	}
+
+	parsed, err := m.loadMesh(ctx, source)
+	if err != nil {
+		return domain.OrientResult{}, err
+	}
+
+	orientations, err := parsed.Orient(ctx, req.OverhangAngle)
+	if err != nil {
+		return domain.OrientResult{}, err
+	}
+	if len(orientations) > maxOrientations {
+		orientations = orientations[:maxOrientations]
+	}
+
+	var res domain.OrientResult
+	for _, o := range orientations {
+		res.Candidates = append(res.Candidates, toOrientation(o))
+	}
+
+	if req.Store && len(orientations) > 0 {
+		oriented := parsed.Transform(orientations[0].Rotation)
+		oriented = oriented.Transform(mesh.Translation(mesh.Vector{Z: -oriented.Bounds().Min.Z}))
+
+		model, err := m.storeDerived(ctx, source, oriented, "oriented")
+		if err != nil {
+			return domain.OrientResult{}, err
+		}
+		res.Model = &model
+	}
+
+	return res, nil
+}
+
+// Boolean combines the solids of two or more models and stores the result as a new model that is
+// linked to the first one. The other models are converted into the unit of the first, and every
+// model has to be closed.
+func (m *modelService) Boolean(c context.Context, userID int64, req domain.BooleanRequest) (domain.Model, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	if len(req.ModelIDs) < 2 {
+		return domain.Model{}, domain.ErrBadParamInput
+	}
+	op := mesh.BooleanOperation(req.Operation)
+
+	var source domain.Model
+	var res *mesh.Mesh
+	for i, id := range req.ModelIDs {
+		model, err := m.modelRepo.GetByID(ctx, id, userID)
+		if err != nil {
+			return domain.Model{}, err
+		}
+
+		parsed, err := m.loadMesh(ctx, model)
+		if err != nil {
+			return domain.Model{}, err
+		}
+		// the inside of a mesh with holes is not defined
+		if !parsed.Analyze().Watertight {
+			return domain.Model{}, domain.ErrBadParamInput
+		}
+
+		if i == 0 {
+			source, res = model, parsed
+			continue
+		}
+
+		f := unitOf(model).Millimeters() / unitOf(source).Millimeters()
+		res, err = mesh.Boolean(op, res, parsed.Transform(mesh.Scaling(f, f, f)))
+		if err == mesh.ErrUnknownOperation {
+			return domain.Model{}, domain.ErrBadParamInput
+		}
+		if err != nil {
+			return domain.Model{}, err
+		}
+	}
+
+	// nothing is left when the models do not overlap or one removes the other completely
+	if len(res.Triangles) == 0 {
+		return domain.Model{}, domain.ErrBadParamInput
+	}
+
+	return m.storeDerived(ctx, source, res, req.Operation)
+}
+
+// ArrangePlate packs copies of models onto the bed of a printer and stores the plate as a 3MF model
+// with a build item for every copy. Models are converted into mm and stand on the bed in the
+// orientation that they are in, turned only around the vertical axis.
+func (m *modelService) ArrangePlate(c context.Context, userID int64, req domain.PlateRequest) (domain.Plate, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	if !validCopies(req.Items) || req.BedX <= 0 || req.BedY <= 0 || req.Spacing < 0 {
+		return domain.Plate{}, domain.ErrBadParamInput
+	}
+
+	mode := mesh.RotationMode(req.Rotation)
+	if mode == "" {
+		mode = mesh.RotateRightAngles
+	}
+
+	var footprints [][]mesh.Vector2
+	var items []mesh.BuildItem
+	// drops convert every copy into mm and stand it on the bed
+	var drops []mesh.Matrix
+	var modelIDs []int64
+	for _, item := range req.Items {
+		model, parsed, drop, err := m.loadMillimeters(ctx, item.ModelID, userID)
+		if err != nil {
+			return domain.Plate{}, err
+		}
+
+		stand := mesh.Translation(mesh.Vector{Z: -parsed.Bounds().Min.Z})
+		parsed = parsed.Transform(stand)
+		drop = stand.Mul(drop)
+
+		footprint := parsed.Footprint()
+		for i := 0; i < item.Quantity; i++ {
+			footprints = append(footprints, footprint)
+			items = append(items, mesh.BuildItem{Name: model.Name, Mesh: parsed})
+			drops = append(drops, drop)
+			modelIDs = append(modelIDs, model.ID)
+		}
+	}
+
+	placements, err := mesh.Arrange(footprints, mesh.Vector2{X: req.BedX, Y: req.BedY}, req.Spacing, mode)
+	if err == mesh.ErrDoesNotFit || err == mesh.ErrUnknownRotation {
+		return domain.Plate{}, domain.ErrBadParamInput
+	}
+	if err != nil {
+		return domain.Plate{}, err
+	}
+
+	var res domain.Plate
+	var area float64
+	for i, p := range placements {
+		items[i].Mesh = items[i].Mesh.Transform(p.Matrix())
+		area += mesh.FootprintArea(footprints[i])
+		res.Placements = append(res.Placements, domain.PlacedModel{
+			ModelID: modelIDs[i],
+			Matrix:  toMatrix(p.Matrix().Mul(drops[i])),
+			Angle:   p.Angle * 180 / math.Pi,
+		})
+	}
+	res.Density = area / (req.BedX * req.BedY)
+
+	var buf bytes.Buffer
+	err = mesh.Write3MFPackage(&buf, &mesh.ThreeMF{Items: items})
+	if err != nil {
+		return domain.Plate{}, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = defaultPlateName
+	}
+	res.Model = domain.Model{Unit: string(mesh.UnitMillimeter)}
+	err = m.Store(ctx, &res.Model, &buf, name+mesh.Format3MF.Extension(), userID)
+	if err != nil {
+		return domain.Plate{}, err
+	}
+
+	return res, nil
+}
+
+// Nest packs copies of models into the build volume of a powder bed printer and stores the build as
+// a 3MF model with a build item for every copy. Models are converted into mm and turned onto any of
+// their sides unless their orientation is kept.
+func (m *modelService) Nest(c context.Context, userID int64, req domain.NestRequest) (domain.Nest, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	if req.Shape != "" && req.Shape != boxShape && req.Shape != cylinderShape {
+		return domain.Nest{}, domain.ErrBadParamInput
+	}
+	container := mesh.Container{
+		Size:     mesh.Vector{X: req.BuildX, Y: req.BuildY, Z: req.BuildZ},
+		Cylinder: req.Shape == cylinderShape,
+	}
+	if !validCopies(req.Items) || req.BuildX <= 0 || req.BuildZ <= 0 || (!container.Cylinder && req.BuildY <= 0) || req.Spacing < 0 {
+		return domain.Nest{}, domain.ErrBadParamInput
+	}
+
+	var parts []*mesh.Mesh
+	var items []mesh.BuildItem
+	// scales convert every copy into mm
+	var scales []mesh.Matrix
+	var modelIDs []int64
+	var volume float64
+	for _, item := range req.Items {
+		model, parsed, scale, err := m.loadMillimeters(ctx, item.ModelID, userID)
+		if err != nil {
+			return domain.Nest{}, err
+		}
+
+		for i := 0; i < item.Quantity; i++ {
+			parts = append(parts, parsed)
+			items = append(items, mesh.BuildItem{Name: model.Name})
+			scales = append(scales, scale)
+			modelIDs = append(modelIDs, model.ID)
+		}
+		volume += math.Abs(parsed.Properties().Volume) * float64(item.Quantity)
+	}
+
+	placements, err := mesh.Nest(parts, container, req.Spacing, !req.KeepOrientation)
+	if err == mesh.ErrDoesNotFit {
+		return domain.Nest{}, domain.ErrBadParamInput
+	}
+	if err != nil {
+		return domain.Nest{}, err
+	}
+
+	var res domain.Nest
+	for i, p := range placements {
+		items[i].Mesh = parts[i].Transform(p)
+		res.Height = math.Max(res.Height, items[i].Mesh.Bounds().Max.Z)
+		res.Placements = append(res.Placements, domain.NestedModel{
+			ModelID: modelIDs[i],
+			Matrix:  toMatrix(p.Mul(scales[i])),
+		})
+	}
+	if res.Height > 0 {
+		res.Density = volume / (container.Volume() * res.Height / req.BuildZ)
+	}
+
+	var buf bytes.Buffer
+	err = mesh.Write3MFPackage(&buf, &mesh.ThreeMF{Items: items})
+	if err != nil {
+		return domain.Nest{}, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = defaultNestName
+	}
+	res.Model = domain.Model{Unit: string(mesh.UnitMillimeter)}
+	err = m.Store(ctx, &res.Model, &buf, name+mesh.Format3MF.Extension(), userID)
+	if err != nil {
+		return domain.Nest{}, err
+	}
+
+	return res, nil
+}
+
+// GetThumbnail returns a PNG preview of a model. Thumbnails are cached in the filestore per size,
+// and the default size is rendered as soon as a model is uploaded.
+func (m *modelService) GetThumbnail(c context.Context, id int64, userID int64, size int) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	if size < minThumbnailSize || size > maxThumbnailSize {
+		return nil, domain.ErrBadParamInput
+	}
+
+	model, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := m.download(ctx, thumbnailFileID(model.DownloadID, size))
+	if err == nil {
+		return data, nil
+	}
+	if err != domain.ErrNotFound {
+		return nil, err
+	}
+
+	parsed, err := m.loadMesh(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.storeThumbnail(ctx, model.DownloadID, parsed, size)
+}
+
+// GetSlices returns the cross sections of a model. With a layer height the whole model is cut into
+// layers of that height, otherwise the single plane at height z is returned.
+func (m *modelService) GetSlices(c context.Context, id int64, userID int64, z float64, layerHeight float64) ([]domain.Layer, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	if layerHeight < 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	model, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := m.loadMesh(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	// the height is taken from the mesh because models stored before bounding boxes were tracked
+	// have an empty one
+	bounds := parsed.Bounds()
+	if layerHeight > 0 && (bounds.Max.Z-bounds.Min.Z)/layerHeight > maxLayers {
+		return nil, domain.ErrBadParamInput
+	}
+
+	var layers []mesh.Layer
+	if layerHeight > 0 {
+		layers = parsed.Slices(layerHeight)
+	} else {
+		layers = []mesh.Layer{parsed.Slice(z)}
+	}
+
+	res := make([]domain.Layer, len(layers))
+	for i, l := range layers {
+		res[i] = toLayer(l)
+	}
+	return res, nil
+}
+
+// Slice generates the G-code to print a model with a profile and stores it as a new model that is
+// linked to the original
+func (m *modelService) Slice(c context.Context, id int64, userID int64, profile domain.PrintProfile) (domain.Model, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	source, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	parsed, err := m.loadMesh(ctx, source)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	// printers work in mm
+	if f := unitOf(source).Millimeters(); f != 1 {
+		parsed = parsed.Transform(mesh.Scaling(f, f, f))
+	}
+
+	var buf bytes.Buffer
+	_, err = slicer.Slice(&buf, parsed, toSlicerProfile(profile))
+	if err == slicer.ErrInvalidProfile || err == slicer.ErrNothingToPrint {
+		return domain.Model{}, domain.ErrBadParamInput
+	}
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	options := gcode.DefaultOptions
+	options.FilamentDiameter = profile.FilamentDiameter
+	analysis, err := gcode.Analyze(bytes.NewReader(buf.Bytes()), options)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	filename := strings.TrimSuffix(source.Name, filepath.Ext(source.Name)) + gcodeExt
+	downloadID, err := m.filestore.Upload(ctx, &buf, filename)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	parentID := source.ID
+	model := domain.Model{
+		Name:       filename,
+		UserID:     source.UserID,
+		DownloadID: downloadID,
+		Format:     domain.GCodeFormat,
+		Unit:       string(mesh.UnitMillimeter),
+		ParentID:   &parentID,
+	}
+	setPrintEstimates(&model, analysis)
+	err = m.modelRepo.Store(ctx, &model)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	return model, nil
+}
+
 func (m *modelService) GetByName(c context.Context, name string) (res domain.Model, err error) {
 	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
 	defer cancel()
@@ -78,18 +893,89 @@ func (m *modelService) Store(c context.Context, model *domain.Model, file io.Rea
 	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
 	defer cancel()
 
-	downloadID, err := m.filestore.Upload(ctx, file, filename)
+	// the file is read into memory so that it can be parsed before being uploaded
+	data, err := ioutil.ReadAll(file)
 	if err != nil {
 		return err
 	}
-	model.DownloadID = downloadID
+
+	if strings.EqualFold(filepath.Ext(filename), gcodeExt) {
+		return m.storeGCode(ctx, model, data, filename, userID)
+	}
+
+	format, ok := mesh.FormatFromFilename(filename)
+	if !ok {
+		return domain.ErrBadParamInput
+	}
+
+	// a unit that is given on upload overrides the one that is declared in or detected from the file
+	if model.Unit != "" {
+		if _, ok := mesh.ParseUnit(model.Unit); !ok {
+			return domain.ErrBadParamInput
+		}
+	}
 
 	model.Name = filename
+	model.Format = string(format)
+
+	// reject any file that doesnt contain a valid mesh
+	parsed, err := readMesh(model, data, format)
+	if err != nil || len(parsed.Triangles) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	if model.Unit == "" {
+		model.Unit = string(mesh.DetectUnit(parsed.Bounds()))
+	}
+
+	downloadID, err := m.filestore.Upload(ctx, bytes.NewReader(data), filename)
+	if err != nil {
+		return err
+	}
+	model.DownloadID = downloadID
+
+	setMassProperties(model, parsed.Properties())
+
+	// the model is usable without its thumbnail so a failure to generate it is only logged
+	_, err = m.storeThumbnail(ctx, model.DownloadID, parsed, DefaultThumbnailSize)
+	if err != nil {
+		logrus.Error(err)
+	}
+
 	model.UserID = userID
 	err = m.modelRepo.Store(ctx, model)
+	if err != nil {
+		return
+	}
+
+	setThumbnailURL(model)
 	return
 }
 
+// storeGCode stores an uploaded G-code file with the estimates of how long it takes to print and
+// how much filament it uses. G-code does not have a mesh so no derived files are generated for it.
+func (m *modelService) storeGCode(ctx context.Context, model *domain.Model, data []byte, filename string, userID int64) error {
+	analysis, err := gcode.Analyze(bytes.NewReader(data), gcode.DefaultOptions)
+	if err != nil {
+		return domain.ErrBadParamInput
+	}
+
+	downloadID, err := m.filestore.Upload(ctx, bytes.NewReader(data), filename)
+	if err != nil {
+		return err
+	}
+
+	model.Name = filename
+	model.Format = domain.GCodeFormat
+	// the moves are converted to mm when the file is analyzed
+	model.Unit = string(mesh.UnitMillimeter)
+	model.DownloadID = downloadID
+	model.UserID = userID
+	setPrintEstimates(model, analysis)
+
+	return m.modelRepo.Store(ctx, model)
+}
+
 func (m *modelService) Delete(c context.Context, id int64, userID int64) (err error) {
 	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
 	defer cancel()
@@ -102,3 +988,318 @@ func (m *modelService) Delete(c context.Context, id int64, userID int64) (err er
 	}
 	return m.modelRepo.Delete(ctx, id)
 }
+
+// storeContent writes the mesh of a model in a format and caches it in the filestore
+func (m *modelService) storeContent(ctx context.Context, model domain.Model, parsed *mesh.Mesh, format mesh.Format, lod string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := writeMesh(&buf, parsed, format, model.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.filestore.UploadWithID(ctx, bytes.NewReader(buf.Bytes()), contentFileID(model.DownloadID, format, lod))
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeMesh writes a mesh in a format. 3MF is the only format that declares the unit of the mesh, so
+// it is written with the unit of the model that the mesh belongs to.
+func writeMesh(w io.Writer, parsed *mesh.Mesh, format mesh.Format, unit string) error {
+	if format != mesh.Format3MF {
+		return mesh.Write(w, parsed, format)
+	}
+
+	u, _ := mesh.ParseUnit(unit)
+	return mesh.Write3MFPackage(w, &mesh.ThreeMF{
+		Unit:  u.ThreeMFName(),
+		Items: []mesh.BuildItem{{Mesh: parsed}},
+	})
+}
+
+// storeThumbnail renders a mesh as a PNG and caches it in the filestore
+func (m *modelService) storeThumbnail(ctx context.Context, downloadID string, parsed *mesh.Mesh, size int) ([]byte, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, parsed.Render(size))
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.filestore.UploadWithID(ctx, bytes.NewReader(buf.Bytes()), thumbnailFileID(downloadID, size))
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// storeDerived stores a mesh that was generated from another model as a new model owned by the same
+// user. It is written in the format of the source model and named after it with a suffix.
+func (m *modelService) storeDerived(ctx context.Context, source domain.Model, derived *mesh.Mesh, suffix string) (domain.Model, error) {
+	format := mesh.Format(source.Format)
+
+	var buf bytes.Buffer
+	err := writeMesh(&buf, derived, format, source.Unit)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	name := strings.TrimSuffix(source.Name, filepath.Ext(source.Name))
+	filename := name + "-" + suffix + format.Extension()
+
+	parentID := source.ID
+	model := domain.Model{ParentID: &parentID, Unit: source.Unit}
+	err = m.Store(ctx, &model, &buf, filename, source.UserID)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	return model, nil
+}
+
+// loadMillimeters loads the mesh of a model that is owned by a user and converts it into mm. The
+// transform that converts it is returned with it.
+func (m *modelService) loadMillimeters(ctx context.Context, id int64, userID int64) (domain.Model, *mesh.Mesh, mesh.Matrix, error) {
+	model, err := m.modelRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return domain.Model{}, nil, mesh.Matrix{}, err
+	}
+
+	parsed, err := m.loadMesh(ctx, model)
+	if err != nil {
+		return domain.Model{}, nil, mesh.Matrix{}, err
+	}
+
+	f := unitOf(model).Millimeters()
+	scale := mesh.Scaling(f, f, f)
+	return model, parsed.Transform(scale), scale, nil
+}
+
+// validCopies reports whether every item asks for at least one copy and there are few enough copies
+// to arrange at once
+func validCopies(items []domain.PlateItem) bool {
+	copies := 0
+	for _, item := range items {
+		if item.Quantity < 1 {
+			return false
+		}
+		copies += item.Quantity
+	}
+	return copies > 0 && copies <= maxPlateCopies
+}
+
+func (m *modelService) loadMesh(ctx context.Context, model domain.Model) (*mesh.Mesh, error) {
+	return LoadMesh(ctx, m.filestore, model)
+}
+
+// LoadMesh downloads the original file of a model and parses it into a mesh. Models that are not
+// meshes, such as G-code, can not be loaded.
+func LoadMesh(ctx context.Context, s domain.Filestore, model domain.Model) (*mesh.Mesh, error) {
+	if model.Format == domain.GCodeFormat {
+		return nil, domain.ErrBadParamInput
+	}
+
+	data, err := download(ctx, s, model.DownloadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mesh.Read(bytes.NewReader(data), mesh.Format(model.Format))
+}
+
+func (m *modelService) download(ctx context.Context, id string) ([]byte, error) {
+	return download(ctx, m.filestore, id)
+}
+
+func download(ctx context.Context, s domain.Filestore, id string) ([]byte, error) {
+	file, err := s.Download(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ioutil.ReadAll(file)
+}
+
+// derivedFileID is the filestore id of a file that is generated from the original file of a model
+func derivedFileID(downloadID string, suffix string) string {
+	return downloadID + "." + suffix
+}
+
+// contentFileID is the filestore id of a model converted to a format at a level of detail. An empty
+// level of detail is the full mesh.
+func contentFileID(downloadID string, format mesh.Format, lod string) string {
+	if lod == "" {
+		return derivedFileID(downloadID, string(format))
+	}
+	return derivedFileID(downloadID, "lod-"+lod+"."+string(format))
+}
+
+func thumbnailFileID(downloadID string, size int) string {
+	return derivedFileID(downloadID, "thumbnail-"+strconv.Itoa(size)+".png")
+}
+
+// decimateLOD reduces a mesh to a level of detail. A level that is reduced from another level
+// reduces the mesh to that level first, which is cached in the format of the web viewer on the way.
+func (m *modelService) decimateLOD(ctx context.Context, model domain.Model, parsed *mesh.Mesh, lod string) (*mesh.Mesh, error) {
+	level := lods[lod]
+	target := int(float64(len(parsed.Triangles)) * level.fraction)
+	if target < minLODTriangles {
+		target = minLODTriangles
+	}
+
+	source := parsed
+	if level.from != "" {
+		var err error
+		source, err = m.decimateLOD(ctx, model, parsed, level.from)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = m.storeContent(ctx, model, source, mesh.FormatGLB, level.from)
+		if err != nil {
+			logrus.Error(err)
+		}
+	}
+
+	return source.Decimate(ctx, target)
+}
+
+// setThumbnailURL points a model at the endpoint that serves its thumbnail. Only meshes have one.
+func setThumbnailURL(model *domain.Model) {
+	if model.Format == domain.GCodeFormat {
+		return
+	}
+	model.ThumbnailURL = fmt.Sprintf("/models/%d/thumbnail", model.ID)
+}
+
+// readMesh parses an uploaded file into a mesh. Formats that carry document metadata also fill in
+// the name and description of the model from it.
+func readMesh(model *domain.Model, data []byte, format mesh.Format) (*mesh.Mesh, error) {
+	if format != mesh.Format3MF {
+		return mesh.Read(bytes.NewReader(data), format)
+	}
+
+	pkg, err := mesh.Read3MFPackage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if title := pkg.Metadata["Title"]; title != "" {
+		model.Name = title
+	}
+	model.Description = pkg.Metadata["Description"]
+	model.Designer = pkg.Metadata["Designer"]
+	if unit, ok := mesh.Parse3MFUnit(pkg.Unit); ok && model.Unit == "" {
+		model.Unit = string(unit)
+	}
+
+	return pkg.Mesh(), nil
+}
+
+// setMassProperties copies the mass properties computed from a mesh onto a model
+func setMassProperties(model *domain.Model, p mesh.Properties) {
+	model.Volume = p.Volume
+	model.SurfaceArea = p.SurfaceArea
+	model.BoundingBox = domain.BoundingBox{
+		Min: toPoint(p.Bounds.Min),
+		Max: toPoint(p.Bounds.Max),
+	}
+	model.TriangleCount = int64(p.TriangleCount)
+	model.VertexCount = int64(p.VertexCount)
+	model.Centroid = toPoint(p.Centroid)
+}
+
+// unitOf returns the unit of a model. Models that were stored before units were tracked are in mm.
+func unitOf(model domain.Model) mesh.Unit {
+	if unit, ok := mesh.ParseUnit(model.Unit); ok {
+		return unit
+	}
+	return mesh.UnitMillimeter
+}
+
+func setPrintEstimates(model *domain.Model, a gcode.Analysis) {
+	model.PrintTime = a.PrintTime
+	model.FilamentLength = a.FilamentLength
+	model.FilamentWeight = a.FilamentWeight
+	model.LayerCount = int64(a.Layers)
+	model.BoundingBox = domain.BoundingBox{
+		Min: toPoint(a.Bounds.Min),
+		Max: toPoint(a.Bounds.Max),
+	}
+}
+
+// toMatrix converts a transform into the form that the transform endpoint accepts
+func toMatrix(a mesh.Matrix) [][]float64 {
+	matrix := make([][]float64, len(a))
+	for i := range a {
+		matrix[i] = append([]float64(nil), a[i][:]...)
+	}
+	return matrix
+}
+
+func toOrientation(o mesh.Orientation) domain.Orientation {
+	return domain.Orientation{
+		Down:          toPoint(o.Down),
+		Matrix:        toMatrix(o.Rotation),
+		OverhangArea:  o.OverhangArea,
+		SupportVolume: o.SupportVolume,
+		ContactArea:   o.ContactArea,
+		BuildHeight:   o.Height,
+		Score:         o.Score,
+	}
+}
+
+func toSlicerProfile(p domain.PrintProfile) slicer.Profile {
+	return slicer.Profile{
+		LayerHeight:       p.LayerHeight,
+		NozzleDiameter:    p.NozzleDiameter,
+		FilamentDiameter:  p.FilamentDiameter,
+		Perimeters:        p.Perimeters,
+		InfillDensity:     p.InfillDensity,
+		InfillPattern:     slicer.Pattern(p.InfillPattern),
+		TopLayers:         p.TopLayers,
+		BottomLayers:      p.BottomLayers,
+		NozzleTemperature: p.NozzleTemperature,
+		BedTemperature:    p.BedTemperature,
+		PrintSpeed:        p.PrintSpeed,
+		TravelSpeed:       p.TravelSpeed,
+		RetractionLength:  p.RetractionLength,
+		RetractionSpeed:   p.RetractionSpeed,
+		BedCenter:         mesh.Vector2{X: p.BedCenterX, Y: p.BedCenterY},
+	}
+}
+
+func toLayer(l mesh.Layer) domain.Layer {
+	layer := domain.Layer{Z: l.Z, Polygons: make([]domain.Polygon, len(l.Polygons))}
+	for i, p := range l.Polygons {
+		polygon := domain.Polygon{Outline: toPoints2D(p.Outline), Holes: make([][]domain.Point2D, len(p.Holes))}
+		for j, h := range p.Holes {
+			polygon.Holes[j] = toPoints2D(h)
+		}
+		layer.Polygons[i] = polygon
+	}
+	return layer
+}
+
+func toPoints2D(points []mesh.Vector2) []domain.Point2D {
+	res := make([]domain.Point2D, len(points))
+	for i, p := range points {
+		res[i] = domain.Point2D{X: p.X, Y: p.Y}
+	}
+	return res
+}
+
+// finite returns nil for infinite values, which JSON can not represent
+func finite(v float64) *float64 {
+	if math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+func toPoint(v mesh.Vector) domain.Point {
+	return domain.Point{X: v.X, Y: v.Y, Z: v.Z}
+}
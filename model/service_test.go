@@ -1,20 +1,104 @@
 package model_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"errors"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"math"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/rknizzle/rkmesh/domain"
 	"github.com/rknizzle/rkmesh/domain/mocks"
+	"github.com/rknizzle/rkmesh/mesh"
 	"github.com/rknizzle/rkmesh/model"
 )
 
+// a single triangle in the ASCII STL format
+const mockSTL = `solid test
+facet normal 0 0 1
+outer loop
+vertex 0 0 0
+vertex 1 0 0
+vertex 0 1 0
+endloop
+endfacet
+endsolid test
+`
+
+// a closed tetrahedron with its apex 10 units above its base in the ASCII STL format
+const mockTetrahedron = `solid tetrahedron
+facet normal 0 0 -1
+outer loop
+vertex 0 0 0
+vertex 0 10 0
+vertex 10 0 0
+endloop
+endfacet
+facet normal 0 -1 0
+outer loop
+vertex 0 0 0
+vertex 10 0 0
+vertex 0 0 10
+endloop
+endfacet
+facet normal -1 0 0
+outer loop
+vertex 0 0 0
+vertex 0 0 10
+vertex 0 10 0
+endloop
+endfacet
+facet normal 1 1 1
+outer loop
+vertex 10 0 0
+vertex 0 10 0
+vertex 0 0 10
+endloop
+endfacet
+endsolid tetrahedron
+`
+
+// mock3MF creates a 3MF package containing a single triangle
+func mock3MF(t *testing.T) []byte {
+	b := new(bytes.Buffer)
+	w := zip.NewWriter(b)
+	f, err := w.Create("3D/3dmodel.model")
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte(`<model unit="millimeter">
+  <metadata name="Title">Bracket</metadata>
+  <metadata name="Description">Mounting bracket</metadata>
+  <metadata name="Designer">Ryan</metadata>
+  <resources>
+    <object id="1" type="model">
+      <mesh>
+        <vertices><vertex x="0" y="0" z="0" /><vertex x="1" y="0" z="0" /><vertex x="0" y="1" z="0" /></vertices>
+        <triangles><triangle v1="0" v2="1" v3="2" /></triangles>
+      </mesh>
+    </object>
+  </resources>
+  <build><item objectid="1" /></build>
+</model>`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+// expectDerivedFiles expects the files that are generated after a model is uploaded
+func expectDerivedFiles(mockFilestore *mocks.Filestore, downloadID string) {
+	mockFilestore.On("UploadWithID", mock.Anything, mock.Anything, downloadID+".thumbnail-256.png").Return(nil).Once()
+}
+
 func TestServiceGetAll(t *testing.T) {
 	mockModelRepo := new(mocks.ModelRepository)
 	mockFilestore := new(mocks.Filestore)
@@ -98,15 +182,1211 @@ func TestServiceStore(t *testing.T) {
 		tempMockModel.ID = 0
 		mockModelRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Model")).Return(nil).Once()
 		mockFilestore.On("Upload", mock.Anything, mock.Anything, "test.stl").Return("", nil).Once()
+		expectDerivedFiles(mockFilestore, "")
 
 		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
 
-		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader("test"), "test.stl", 1)
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader(mockSTL), "test.stl", 1)
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockModel.Name, tempMockModel.Name)
+		assert.Equal(t, "stl", tempMockModel.Format)
+		assert.Equal(t, int64(1), tempMockModel.TriangleCount)
+		assert.Equal(t, int64(3), tempMockModel.VertexCount)
+		assert.InDelta(t, 0.5, tempMockModel.SurfaceArea, 1e-9)
+		assert.Equal(t, domain.Point{X: 1, Y: 1}, tempMockModel.BoundingBox.Max)
+		assert.Equal(t, "/models/0/thumbnail", tempMockModel.ThumbnailURL)
+		// most meshes are modelled in mm, even ones that are only a single unit across
+		assert.Equal(t, "mm", tempMockModel.Unit)
+		mockModelRepo.AssertExpectations(t)
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("unit-override", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+		tempMockModel.Unit = "cm"
+		mockModelRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Model")).Return(nil).Once()
+		mockFilestore.On("Upload", mock.Anything, mock.Anything, "test.stl").Return("", nil).Once()
+		expectDerivedFiles(mockFilestore, "")
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader(mockSTL), "test.stl", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "cm", tempMockModel.Unit)
+	})
+	t.Run("invalid-unit", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+		tempMockModel.Unit = "furlong"
+		unusedFilestore := new(mocks.Filestore)
+
+		s := model.NewModelService(mockModelRepo, unusedFilestore, time.Second*2)
+
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader(mockSTL), "test.stl", 1)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		unusedFilestore.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, "test.stl")
+	})
+	t.Run("obj-file", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+		mockModelRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Model")).Return(nil).Once()
+		mockFilestore.On("Upload", mock.Anything, mock.Anything, "test.obj").Return("", nil).Once()
+		expectDerivedFiles(mockFilestore, "")
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		obj := "v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\nf 1 2 3 4\n"
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader(obj), "test.obj", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "obj", tempMockModel.Format)
+		assert.Equal(t, int64(2), tempMockModel.TriangleCount)
 		mockModelRepo.AssertExpectations(t)
 	})
+	t.Run("3mf-file", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+		mockModelRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Model")).Return(nil).Once()
+		mockFilestore.On("Upload", mock.Anything, mock.Anything, "test.3mf").Return("", nil).Once()
+		expectDerivedFiles(mockFilestore, "")
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		err := s.Store(context.TODO(), &tempMockModel, bytes.NewReader(mock3MF(t)), "test.3mf", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "3mf", tempMockModel.Format)
+		assert.Equal(t, "Bracket", tempMockModel.Name)
+		assert.Equal(t, "Mounting bracket", tempMockModel.Description)
+		assert.Equal(t, "Ryan", tempMockModel.Designer)
+		assert.Equal(t, "mm", tempMockModel.Unit)
+		mockModelRepo.AssertExpectations(t)
+	})
+	t.Run("ply-file", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+		mockModelRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Model")).Return(nil).Once()
+		mockFilestore.On("Upload", mock.Anything, mock.Anything, "test.ply").Return("", nil).Once()
+		expectDerivedFiles(mockFilestore, "")
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		ply := "ply\nformat ascii 1.0\nelement vertex 3\nproperty float x\nproperty float y\nproperty float z\n" +
+			"element face 1\nproperty list uchar int vertex_indices\nend_header\n0 0 0\n1 0 0\n0 1 0\n3 0 1 2\n"
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader(ply), "test.ply", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ply", tempMockModel.Format)
+		assert.Equal(t, int64(1), tempMockModel.TriangleCount)
+		mockModelRepo.AssertExpectations(t)
+	})
+	t.Run("gcode-file", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+		gcodeStore := new(mocks.Filestore)
+		mockModelRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Model")).Return(nil).Once()
+		gcodeStore.On("Upload", mock.Anything, mock.Anything, "test.gcode").Return("test.gcode-xxx", nil).Once()
+
+		s := model.NewModelService(mockModelRepo, gcodeStore, time.Second*2)
+
+		g := "G28\nG92 E0\nG1 Z0.2 F1200\nG1 X10 E1 F1800\nG1 Y10 E2\nG1 Z0.4\nG1 X0 E3\n"
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader(g), "test.gcode", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "gcode", tempMockModel.Format)
+		assert.Equal(t, "test.gcode-xxx", tempMockModel.DownloadID)
+		assert.Equal(t, int64(2), tempMockModel.LayerCount)
+		assert.InDelta(t, 3, tempMockModel.FilamentLength, 1e-9)
+		assert.Greater(t, tempMockModel.FilamentWeight, 0.0)
+		assert.Greater(t, tempMockModel.PrintTime, 0.0)
+		assert.Equal(t, domain.Point{X: 10, Y: 10, Z: 0.4}, tempMockModel.BoundingBox.Max)
+		assert.Empty(t, tempMockModel.ThumbnailURL)
+		// G-code has no mesh to render or decimate
+		gcodeStore.AssertExpectations(t)
+		gcodeStore.AssertNotCalled(t, "UploadWithID", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("invalid-gcode", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+		unusedFilestore := new(mocks.Filestore)
+
+		s := model.NewModelService(mockModelRepo, unusedFilestore, time.Second*2)
+
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader(mockSTL), "test.gcode", 1)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		unusedFilestore.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, "test.gcode")
+	})
+	t.Run("unsupported-extension", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader(mockSTL), "test.jpg", 1)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+	t.Run("invalid-file", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+		unusedFilestore := new(mocks.Filestore)
+
+		s := model.NewModelService(mockModelRepo, unusedFilestore, time.Second*2)
+
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader("\xff\xd8\xff\xe0 JFIF"), "test.stl", 1)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		unusedFilestore.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, "test.stl")
+	})
+	t.Run("empty-mesh", func(t *testing.T) {
+		tempMockModel := mockModel
+		tempMockModel.ID = 0
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		err := s.Store(context.TODO(), &tempMockModel, strings.NewReader("solid empty\nendsolid empty"), "test.stl", 1)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
+
+func TestServiceGetContent(t *testing.T) {
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl"}
+	var mockUserID int64 = 1
+
+	t.Run("cached", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx.obj").Return(ioutil.NopCloser(strings.NewReader("cached obj")), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		data, err := s.GetContent(context.TODO(), mockModel.ID, mockUserID, "obj", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "cached obj", string(data))
+		mockFilestore.AssertNotCalled(t, "UploadWithID", mock.Anything, mock.Anything, mock.Anything)
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("converted", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx.obj").Return(nil, domain.ErrNotFound).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockSTL)), nil).Once()
+		mockFilestore.On("UploadWithID", mock.Anything, mock.Anything, "test.stl-xxx.obj").Return(nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		data, err := s.GetContent(context.TODO(), mockModel.ID, mockUserID, "obj", "")
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "f 1 2 3")
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("converted-3mf", func(t *testing.T) {
+		// 3MF declares the unit of the model, which is not always mm
+		inchModel := mockModel
+		inchModel.Unit = "inch"
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(inchModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx.3mf").Return(nil, domain.ErrNotFound).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockSTL)), nil).Once()
+		mockFilestore.On("UploadWithID", mock.Anything, mock.Anything, "test.stl-xxx.3mf").Return(nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		data, err := s.GetContent(context.TODO(), mockModel.ID, mockUserID, "3mf", "")
+
+		require.NoError(t, err)
+		pkg, err := mesh.Read3MFPackage(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "inch", pkg.Unit)
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("level-of-detail", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx.lod-low.glb").Return(ioutil.NopCloser(strings.NewReader("cached glb")), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		data, err := s.GetContent(context.TODO(), mockModel.ID, mockUserID, "glb", "low")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "cached glb", string(data))
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("generated-level-of-detail", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx.lod-low.glb").Return(nil, domain.ErrNotFound).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockSTL)), nil).Once()
+		// the low level is reduced from the medium level, which is cached too
+		mockFilestore.On("UploadWithID", mock.Anything, mock.Anything, "test.stl-xxx.lod-medium.glb").Return(nil).Once()
+		mockFilestore.On("UploadWithID", mock.Anything, mock.Anything, "test.stl-xxx.lod-low.glb").Return(nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		data, err := s.GetContent(context.TODO(), mockModel.ID, mockUserID, "glb", "low")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "glTF", string(data[:4]))
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("unknown-level-of-detail", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.GetContent(context.TODO(), mockModel.ID, mockUserID, "glb", "tiny")
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockModelRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("unsupported-format", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.GetContent(context.TODO(), mockModel.ID, mockUserID, "step", "")
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockModelRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServiceGetAnalysis(t *testing.T) {
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl"}
+	var mockUserID int64 = 1
+
+	t.Run("cached", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		cached := domain.MeshAnalysis{ModelID: 1, Shells: 1, Watertight: true}
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockModelRepo.On("GetAnalysis", mock.Anything, int64(1)).Return(cached, nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		a, err := s.GetAnalysis(context.TODO(), mockModel.ID, mockUserID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, cached, a)
+		mockFilestore.AssertNotCalled(t, "Download", mock.Anything, mock.Anything)
+		mockModelRepo.AssertExpectations(t)
+	})
+	t.Run("computed", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockModelRepo.On("GetAnalysis", mock.Anything, int64(1)).Return(domain.MeshAnalysis{}, domain.ErrNotFound).Once()
+		mockModelRepo.On("StoreAnalysis", mock.Anything, mock.AnythingOfType("*domain.MeshAnalysis")).Return(nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockSTL)), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		a, err := s.GetAnalysis(context.TODO(), mockModel.ID, mockUserID)
+
+		// the mock model is a single open triangle
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), a.ModelID)
+		assert.Equal(t, int64(3), a.BoundaryEdges)
+		assert.Equal(t, int64(1), a.Holes)
+		assert.False(t, a.Watertight)
+		mockModelRepo.AssertExpectations(t)
+	})
+	t.Run("model-does-not-exist", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(domain.Model{}, domain.ErrNotFound).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.GetAnalysis(context.TODO(), mockModel.ID, mockUserID)
+
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+}
+
+func TestServiceGetSupportAnalysis(t *testing.T) {
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl", Unit: "mm"}
+	var mockUserID int64 = 1
+
+	t.Run("upside-down", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		a, err := s.GetSupportAnalysis(context.TODO(), mockModel.ID, mockUserID, domain.Point{Z: -2}, 30)
+
+		// standing on its apex, the slanted face of the tetrahedron hangs over the build plate
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), a.ModelID)
+		assert.Equal(t, domain.Point{Z: -1}, a.Direction)
+		assert.Equal(t, int64(1), a.OverhangFaces)
+		assert.Equal(t, []bool{false, false, false, true}, a.Mask)
+		assert.InDelta(t, 50*math.Sqrt(3), a.OverhangArea, 1e-9)
+		assert.InDelta(t, 50*math.Sqrt(3), a.ContactArea, 1e-9)
+		assert.InDelta(t, 50*20/3.0, a.SupportVolume, 1e-9)
+	})
+	t.Run("invalid-direction", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.GetSupportAnalysis(context.TODO(), mockModel.ID, mockUserID, domain.Point{}, 45)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockModelRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("invalid-angle", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.GetSupportAnalysis(context.TODO(), mockModel.ID, mockUserID, domain.Point{Z: 1}, 90)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
+
+func TestServiceGetThicknessAnalysis(t *testing.T) {
+	var mockUserID int64 = 1
+
+	analyze := func(t *testing.T, unit string, min float64, process string) (domain.ThicknessAnalysis, error) {
+		mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl", Unit: unit}
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+		return s.GetThicknessAnalysis(context.TODO(), 1, mockUserID, min, process)
+	}
+
+	t.Run("process-default", func(t *testing.T) {
+		a, err := analyze(t, "mm", 0, "sla")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), a.ModelID)
+		assert.Equal(t, "sla", a.Process)
+		assert.Equal(t, 0.4, a.MinThickness)
+		assert.Len(t, a.Thickness, 4)
+		require.NotNil(t, a.Thinnest)
+		// the ray from the centre of the bottom face reaches the slanted face a third of the way up
+		assert.LessOrEqual(t, *a.Thinnest, 10/3.0+1e-9)
+	})
+	t.Run("thin-regions-in-mm", func(t *testing.T) {
+		mm, err := analyze(t, "mm", 5, "")
+		require.NoError(t, err)
+		cm, err := analyze(t, "cm", 5, "")
+		require.NoError(t, err)
+
+		// the tetrahedron is thinner than 5mm close to its corners
+		assert.Equal(t, "fdm", mm.Process)
+		require.NotEmpty(t, mm.Regions)
+		assert.Less(t, mm.Regions[0].Thinnest, 5.0)
+		// in cm the tetrahedron is ten times as thick
+		assert.InDelta(t, *mm.Thinnest*10, *cm.Thinnest, 1e-9)
+		assert.Empty(t, cm.Regions)
+	})
+	t.Run("unknown-process", func(t *testing.T) {
+		s := model.NewModelService(new(mocks.ModelRepository), new(mocks.Filestore), time.Second*2)
+
+		_, err := s.GetThicknessAnalysis(context.TODO(), 1, mockUserID, 0, "cnc")
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
+
+func TestServiceGetDiff(t *testing.T) {
+	var mockUserID int64 = 1
+
+	// the other model is the tetrahedron moved 2mm along X
+	tetrahedron, err := mesh.Read(strings.NewReader(mockTetrahedron), mesh.FormatSTL)
+	require.NoError(t, err)
+	var moved bytes.Buffer
+	require.NoError(t, mesh.WriteASCIISTL(&moved, tetrahedron.Transform(mesh.Translation(mesh.Vector{X: 2}))))
+
+	diff := func(t *testing.T, align bool) domain.Diff {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(domain.Model{ID: 1, Name: "a.stl", UserID: 1, DownloadID: "a.stl-xxx", Format: "stl", Unit: "mm"}, nil).Once()
+		mockModelRepo.On("GetByID", mock.Anything, int64(2), mockUserID).Return(domain.Model{ID: 2, Name: "b.stl", UserID: 1, DownloadID: "b.stl-xxx", Format: "stl", Unit: "mm"}, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "a.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+		mockFilestore.On("Download", mock.Anything, "b.stl-xxx").Return(ioutil.NopCloser(bytes.NewReader(moved.Bytes())), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+		res, err := s.GetDiff(context.TODO(), 1, 2, mockUserID, align)
+		require.NoError(t, err)
+		mockModelRepo.AssertExpectations(t)
+		mockFilestore.AssertExpectations(t)
+		return res
+	}
+
+	t.Run("as-placed", func(t *testing.T) {
+		d := diff(t, false)
+
+		assert.Equal(t, int64(1), d.ModelID)
+		assert.Equal(t, int64(2), d.OtherID)
+		assert.False(t, d.Aligned)
+		assert.Equal(t, [][]float64{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}, d.Matrix)
+		assert.InDelta(t, 2, d.Hausdorff, 1e-9)
+		assert.Greater(t, d.RMS, 0.0)
+		require.Len(t, d.Deviation, 4)
+		// the corner at the origin lies 2mm outside of the moved tetrahedron
+		assert.InDelta(t, 2, d.Deviation[0], 1e-9)
+	})
+	t.Run("aligned", func(t *testing.T) {
+		d := diff(t, true)
+
+		assert.True(t, d.Aligned)
+		assert.InDelta(t, 2, d.Matrix[0][3], 1e-6)
+		assert.InDelta(t, 0, d.Hausdorff, 1e-6)
+		for _, v := range d.Deviation {
+			assert.InDelta(t, 0, v, 1e-6)
+		}
+	})
+}
+
+func TestServiceRepair(t *testing.T) {
+	mockModelRepo := new(mocks.ModelRepository)
+	mockFilestore := new(mocks.Filestore)
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl"}
+	var mockUserID int64 = 1
+
+	// the same triangle twice, which the repair reduces to one
+	duplicated := strings.Replace(mockSTL, "endsolid", strings.TrimPrefix(mockSTL, "solid test\n")+"endsolid", 1)
+
+	mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+	mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(duplicated)), nil).Once()
+	mockFilestore.On("Upload", mock.Anything, mock.Anything, "test-repaired.stl").Return("test-repaired.stl-yyy", nil).Once()
+	expectDerivedFiles(mockFilestore, "test-repaired.stl-yyy")
+	mockModelRepo.On("Store", mock.Anything, mock.MatchedBy(func(m *domain.Model) bool {
+		return m.ParentID != nil && *m.ParentID == 1
+	})).Return(nil).Once()
+
+	s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+	repaired, err := s.Repair(context.TODO(), mockModel.ID, mockUserID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test-repaired.stl", repaired.Name)
+	assert.Equal(t, int64(1), repaired.TriangleCount)
+	assert.Equal(t, mockUserID, repaired.UserID)
+	mockModelRepo.AssertExpectations(t)
+	mockFilestore.AssertExpectations(t)
+}
+
+func TestServiceSplit(t *testing.T) {
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl", Unit: "inch"}
+	var mockUserID int64 = 1
+
+	t.Run("success", func(t *testing.T) {
+		// two tetrahedra side by side in one file
+		tetrahedron, err := mesh.Read(strings.NewReader(mockTetrahedron), mesh.FormatSTL)
+		require.NoError(t, err)
+		tetrahedron.Append(tetrahedron.Transform(mesh.Translation(mesh.Vector{X: 20})))
+		var twoShells bytes.Buffer
+		require.NoError(t, mesh.WriteASCIISTL(&twoShells, tetrahedron))
+
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(&twoShells), nil).Once()
+		for _, part := range []string{"test-part-1.stl", "test-part-2.stl"} {
+			mockFilestore.On("Upload", mock.Anything, mock.Anything, part).Return(part+"-yyy", nil).Once()
+			expectDerivedFiles(mockFilestore, part+"-yyy")
+		}
+		mockModelRepo.On("Store", mock.Anything, mock.MatchedBy(func(m *domain.Model) bool {
+			return m.ParentID != nil && *m.ParentID == 1 && m.Unit == "inch"
+		})).Return(nil).Twice()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		parts, err := s.Split(context.TODO(), mockModel.ID, mockUserID)
+
+		require.NoError(t, err)
+		require.Len(t, parts, 2)
+		assert.Equal(t, "test-part-1.stl", parts[0].Name)
+		assert.Equal(t, "test-part-2.stl", parts[1].Name)
+		for _, p := range parts {
+			assert.Equal(t, int64(4), p.TriangleCount)
+			assert.Equal(t, mockUserID, p.UserID)
+		}
+		mockModelRepo.AssertExpectations(t)
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("single-shell", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.Split(context.TODO(), mockModel.ID, mockUserID)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockFilestore.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServiceScale(t *testing.T) {
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl", Unit: "inch"}
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name   string
+		factor float64
+		unit   string
+		// size is the expected size of the 10 unit tetrahedron
+		size float64
+		// expectedUnit is the unit of the scaled copy
+		expectedUnit string
+	}{
+		{"factor", 2, "", 20, "inch"},
+		{"convert-to-mm", 0, "mm", 254, "mm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockModelRepo := new(mocks.ModelRepository)
+			mockFilestore := new(mocks.Filestore)
+			mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+			mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+			mockFilestore.On("Upload", mock.Anything, mock.Anything, "test-scaled.stl").Return("test-scaled.stl-yyy", nil).Once()
+			expectDerivedFiles(mockFilestore, "test-scaled.stl-yyy")
+			mockModelRepo.On("Store", mock.Anything, mock.MatchedBy(func(m *domain.Model) bool {
+				return m.ParentID != nil && *m.ParentID == 1
+			})).Return(nil).Once()
+
+			s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+			scaled, err := s.Scale(context.TODO(), mockModel.ID, mockUserID, tt.factor, tt.unit)
+
+			require.NoError(t, err)
+			assert.Equal(t, "test-scaled.stl", scaled.Name)
+			assert.Equal(t, tt.expectedUnit, scaled.Unit)
+			assert.InDelta(t, tt.size, scaled.BoundingBox.Max.X-scaled.BoundingBox.Min.X, 1e-3)
+			mockModelRepo.AssertExpectations(t)
+			mockFilestore.AssertExpectations(t)
+		})
+	}
+
+	invalid := []struct {
+		name   string
+		factor float64
+		unit   string
+	}{
+		{"neither", 0, ""},
+		{"both", 2, "mm"},
+		{"negative-factor", -1, ""},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			mockModelRepo := new(mocks.ModelRepository)
+			s := model.NewModelService(mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+			_, err := s.Scale(context.TODO(), mockModel.ID, mockUserID, tt.factor, tt.unit)
+
+			assert.Equal(t, domain.ErrBadParamInput, err)
+		})
+	}
+	t.Run("unknown-unit", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		s := model.NewModelService(mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		_, err := s.Scale(context.TODO(), mockModel.ID, mockUserID, 0, "furlong")
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
+
+func TestServiceTransform(t *testing.T) {
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl", Unit: "mm"}
+	var mockUserID int64 = 1
+
+	tests := []struct {
+		name string
+		req  domain.TransformRequest
+		// bounds are the expected bounds of the transformed tetrahedron
+		bounds domain.BoundingBox
+	}{
+		{
+			"mirror-and-drop",
+			domain.TransformRequest{
+				Operations: []domain.TransformOperation{
+					{Type: "mirror", Axis: "z"},
+					{Type: "translate", X: 5},
+				},
+				DropToPlate: true,
+			},
+			domain.BoundingBox{Min: domain.Point{X: 5}, Max: domain.Point{X: 15, Y: 10, Z: 10}},
+		},
+		{
+			"rotate-and-scale",
+			domain.TransformRequest{
+				Operations: []domain.TransformOperation{
+					{Type: "scale", Factor: 2},
+					{Type: "rotate", Axis: "z", Angle: 90},
+				},
+			},
+			domain.BoundingBox{Min: domain.Point{X: -20}, Max: domain.Point{Y: 20, Z: 20}},
+		},
+		{
+			"matrix",
+			domain.TransformRequest{
+				Matrix: [][]float64{{1, 0, 0, 1}, {0, 1, 0, 2}, {0, 0, 1, 3}, {0, 0, 0, 1}},
+			},
+			domain.BoundingBox{Min: domain.Point{X: 1, Y: 2, Z: 3}, Max: domain.Point{X: 11, Y: 12, Z: 13}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockModelRepo := new(mocks.ModelRepository)
+			mockFilestore := new(mocks.Filestore)
+			mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+			mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+			mockFilestore.On("Upload", mock.Anything, mock.Anything, "test-transformed.stl").Return("test-transformed.stl-yyy", nil).Once()
+			expectDerivedFiles(mockFilestore, "test-transformed.stl-yyy")
+			mockModelRepo.On("Store", mock.Anything, mock.MatchedBy(func(m *domain.Model) bool {
+				return m.ParentID != nil && *m.ParentID == 1
+			})).Return(nil).Once()
+
+			s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+			transformed, err := s.Transform(context.TODO(), mockModel.ID, mockUserID, tt.req)
+
+			require.NoError(t, err)
+			assert.Equal(t, "test-transformed.stl", transformed.Name)
+			assert.Equal(t, "mm", transformed.Unit)
+			for _, p := range [][2]domain.Point{
+				{tt.bounds.Min, transformed.BoundingBox.Min},
+				{tt.bounds.Max, transformed.BoundingBox.Max},
+			} {
+				assert.InDelta(t, p[0].X, p[1].X, 1e-4)
+				assert.InDelta(t, p[0].Y, p[1].Y, 1e-4)
+				assert.InDelta(t, p[0].Z, p[1].Z, 1e-4)
+			}
+			// mirrored copies are not turned inside out
+			assert.Greater(t, transformed.Volume, 0.0)
+			mockModelRepo.AssertExpectations(t)
+			mockFilestore.AssertExpectations(t)
+		})
+	}
+
+	invalid := []struct {
+		name string
+		req  domain.TransformRequest
+	}{
+		{"empty", domain.TransformRequest{}},
+		{"operations-and-matrix", domain.TransformRequest{
+			Operations: []domain.TransformOperation{{Type: "translate", X: 1}},
+			Matrix:     [][]float64{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}},
+		}},
+		{"flattening-scale", domain.TransformRequest{
+			Operations: []domain.TransformOperation{{Type: "scale", X: 1, Y: 1}},
+		}},
+		{"missing-axis", domain.TransformRequest{
+			Operations: []domain.TransformOperation{{Type: "rotate", Angle: 90}},
+		}},
+		{"perspective-matrix", domain.TransformRequest{
+			Matrix: [][]float64{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 1, 1}},
+		}},
+		{"short-matrix", domain.TransformRequest{
+			Matrix: [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+		}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			mockModelRepo := new(mocks.ModelRepository)
+			s := model.NewModelService(mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+			_, err := s.Transform(context.TODO(), mockModel.ID, mockUserID, tt.req)
+
+			assert.Equal(t, domain.ErrBadParamInput, err)
+			mockModelRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestServiceOrient(t *testing.T) {
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl", Unit: "mm"}
+	var mockUserID int64 = 1
+
+	t.Run("ranked", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		res, err := s.Orient(context.TODO(), mockModel.ID, mockUserID, domain.OrientRequest{OverhangAngle: 45})
+
+		require.NoError(t, err)
+		require.Len(t, res.Candidates, 10)
+		assert.Nil(t, res.Model)
+		for i := 1; i < len(res.Candidates); i++ {
+			assert.LessOrEqual(t, res.Candidates[i-1].Score, res.Candidates[i].Score)
+		}
+		// the tetrahedron already stands on one of its faces without any overhangs
+		assert.Equal(t, 0.0, res.Candidates[0].SupportVolume)
+		assert.Len(t, res.Candidates[0].Matrix, 4)
+		mockFilestore.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("store", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+		mockFilestore.On("Upload", mock.Anything, mock.Anything, "test-oriented.stl").Return("test-oriented.stl-yyy", nil).Once()
+		expectDerivedFiles(mockFilestore, "test-oriented.stl-yyy")
+		mockModelRepo.On("Store", mock.Anything, mock.MatchedBy(func(m *domain.Model) bool {
+			return m.ParentID != nil && *m.ParentID == 1
+		})).Return(nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		res, err := s.Orient(context.TODO(), mockModel.ID, mockUserID, domain.OrientRequest{OverhangAngle: 45, Store: true})
+
+		require.NoError(t, err)
+		require.NotNil(t, res.Model)
+		assert.Equal(t, "test-oriented.stl", res.Model.Name)
+		assert.InDelta(t, 0, res.Model.BoundingBox.Min.Z, 1e-4)
+		assert.InDelta(t, res.Candidates[0].BuildHeight, res.Model.BoundingBox.Max.Z, 1e-4)
+		mockModelRepo.AssertExpectations(t)
+		mockFilestore.AssertExpectations(t)
+	})
+}
+
+func TestServiceBoolean(t *testing.T) {
+	var mockUserID int64 = 1
+	// the second tetrahedron is in cm, so it is ten times the size of the first once converted and
+	// shares three of its faces
+	mockSmall := domain.Model{ID: 1, Name: "small.stl", UserID: 1, DownloadID: "small.stl-xxx", Format: "stl", Unit: "mm"}
+	mockLarge := domain.Model{ID: 2, Name: "large.stl", UserID: 1, DownloadID: "large.stl-xxx", Format: "stl", Unit: "cm"}
+
+	expect := func(mockModelRepo *mocks.ModelRepository, mockFilestore *mocks.Filestore) {
+		for _, m := range []domain.Model{mockSmall, mockLarge} {
+			mockModelRepo.On("GetByID", mock.Anything, m.ID, mockUserID).Return(m, nil).Once()
+			mockFilestore.On("Download", mock.Anything, m.DownloadID).Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+		}
+	}
+
+	tests := []struct {
+		op     string
+		volume float64
+	}{
+		{"union", 1e6 / 6},
+		{"intersection", 1e3 / 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.op, func(t *testing.T) {
+			mockModelRepo := new(mocks.ModelRepository)
+			mockFilestore := new(mocks.Filestore)
+			expect(mockModelRepo, mockFilestore)
+			mockFilestore.On("Upload", mock.Anything, mock.Anything, "small-"+tt.op+".stl").Return("small-"+tt.op+".stl-yyy", nil).Once()
+			expectDerivedFiles(mockFilestore, "small-"+tt.op+".stl-yyy")
+			mockModelRepo.On("Store", mock.Anything, mock.MatchedBy(func(m *domain.Model) bool {
+				return m.ParentID != nil && *m.ParentID == 1
+			})).Return(nil).Once()
+
+			s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+			res, err := s.Boolean(context.TODO(), mockUserID, domain.BooleanRequest{ModelIDs: []int64{1, 2}, Operation: tt.op})
+
+			require.NoError(t, err)
+			assert.Equal(t, "small-"+tt.op+".stl", res.Name)
+			assert.Equal(t, "mm", res.Unit)
+			assert.InDelta(t, tt.volume, res.Volume, 1e-3)
+			mockModelRepo.AssertExpectations(t)
+			mockFilestore.AssertExpectations(t)
+		})
+	}
+	t.Run("nothing-left", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		expect(mockModelRepo, mockFilestore)
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.Boolean(context.TODO(), mockUserID, domain.BooleanRequest{ModelIDs: []int64{1, 2}, Operation: "difference"})
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockFilestore.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("not-found", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(domain.Model{}, domain.ErrNotFound).Once()
+
+		s := model.NewModelService(mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		_, err := s.Boolean(context.TODO(), mockUserID, domain.BooleanRequest{ModelIDs: []int64{1, 2}, Operation: "union"})
+
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+}
+
+func TestServiceArrangePlate(t *testing.T) {
+	var mockUserID int64 = 1
+	mockSmall := domain.Model{ID: 1, Name: "small.stl", UserID: 1, DownloadID: "small.stl-xxx", Format: "stl", Unit: "mm"}
+	mockLarge := domain.Model{ID: 2, Name: "large.stl", UserID: 1, DownloadID: "large.stl-xxx", Format: "stl", Unit: "cm"}
+	req := domain.PlateRequest{
+		Items:   []domain.PlateItem{{ModelID: 1, Quantity: 3}, {ModelID: 2, Quantity: 1}},
+		BedX:    250,
+		BedY:    200,
+		Spacing: 2,
+	}
+
+	expect := func(mockModelRepo *mocks.ModelRepository, mockFilestore *mocks.Filestore) {
+		for _, m := range []domain.Model{mockSmall, mockLarge} {
+			mockModelRepo.On("GetByID", mock.Anything, m.ID, mockUserID).Return(m, nil).Once()
+			mockFilestore.On("Download", mock.Anything, m.DownloadID).Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		expect(mockModelRepo, mockFilestore)
+		var uploaded []byte
+		mockFilestore.On("Upload", mock.Anything, mock.Anything, "plate.3mf").Return("plate.3mf-yyy", nil).Run(func(args mock.Arguments) {
+			uploaded, _ = ioutil.ReadAll(args.Get(1).(io.Reader))
+		}).Once()
+		expectDerivedFiles(mockFilestore, "plate.3mf-yyy")
+		mockModelRepo.On("Store", mock.Anything, mock.MatchedBy(func(m *domain.Model) bool {
+			return m.ParentID == nil
+		})).Return(nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		plate, err := s.ArrangePlate(context.TODO(), mockUserID, req)
+
+		require.NoError(t, err)
+		require.Len(t, plate.Placements, 4)
+		assert.Equal(t, "plate.3mf", plate.Model.Name)
+		assert.Equal(t, "3mf", plate.Model.Format)
+		assert.Equal(t, "mm", plate.Model.Unit)
+		// three small and one large right triangle
+		assert.InDelta(t, (3*50+5000)/(250*200.0), plate.Density, 1e-9)
+		assert.InDelta(t, 3*1e3/6+1e6/6, plate.Model.Volume, 1e-3)
+		assert.GreaterOrEqual(t, plate.Model.BoundingBox.Min.X, 1-1e-9)
+		assert.GreaterOrEqual(t, plate.Model.BoundingBox.Min.Y, 1-1e-9)
+		assert.InDelta(t, 0, plate.Model.BoundingBox.Min.Z, 1e-9)
+		assert.LessOrEqual(t, plate.Model.BoundingBox.Max.X, 249+1e-9)
+		assert.LessOrEqual(t, plate.Model.BoundingBox.Max.Y, 199+1e-9)
+
+		// every copy is its own build item
+		pkg, err := mesh.Read3MFPackage(bytes.NewReader(uploaded))
+		require.NoError(t, err)
+		require.Len(t, pkg.Items, 4)
+		for i, p := range plate.Placements {
+			var matrix mesh.Matrix
+			for r := range matrix {
+				copy(matrix[r][:], p.Matrix[r])
+			}
+			parsed, err := mesh.Read(strings.NewReader(mockTetrahedron), mesh.FormatSTL)
+			require.NoError(t, err)
+			assert.Equal(t, pkg.Items[i].Mesh.Bounds(), parsed.Transform(matrix).Bounds())
+		}
+		mockModelRepo.AssertExpectations(t)
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("does-not-fit", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		expect(mockModelRepo, mockFilestore)
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		small := req
+		small.BedX, small.BedY = 100, 100
+		_, err := s.ArrangePlate(context.TODO(), mockUserID, small)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockFilestore.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("too-many-copies", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+
+		s := model.NewModelService(mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		many := req
+		many.Items = []domain.PlateItem{{ModelID: 1, Quantity: 100000}}
+		_, err := s.ArrangePlate(context.TODO(), mockUserID, many)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockModelRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServiceNest(t *testing.T) {
+	var mockUserID int64 = 1
+	mockSmall := domain.Model{ID: 1, Name: "small.stl", UserID: 1, DownloadID: "small.stl-xxx", Format: "stl", Unit: "mm"}
+	mockLarge := domain.Model{ID: 2, Name: "large.stl", UserID: 1, DownloadID: "large.stl-xxx", Format: "stl", Unit: "cm"}
+	req := domain.NestRequest{
+		Items:   []domain.PlateItem{{ModelID: 1, Quantity: 4}, {ModelID: 2, Quantity: 1}},
+		BuildX:  120,
+		BuildY:  120,
+		BuildZ:  120,
+		Spacing: 2,
+	}
+
+	expect := func(mockModelRepo *mocks.ModelRepository, mockFilestore *mocks.Filestore) {
+		for _, m := range []domain.Model{mockSmall, mockLarge} {
+			mockModelRepo.On("GetByID", mock.Anything, m.ID, mockUserID).Return(m, nil).Once()
+			mockFilestore.On("Download", mock.Anything, m.DownloadID).Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		expect(mockModelRepo, mockFilestore)
+		var uploaded []byte
+		mockFilestore.On("Upload", mock.Anything, mock.Anything, "build.3mf").Return("build.3mf-yyy", nil).Run(func(args mock.Arguments) {
+			uploaded, _ = ioutil.ReadAll(args.Get(1).(io.Reader))
+		}).Once()
+		expectDerivedFiles(mockFilestore, "build.3mf-yyy")
+		mockModelRepo.On("Store", mock.Anything, mock.Anything).Return(nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		nest, err := s.Nest(context.TODO(), mockUserID, req)
+
+		require.NoError(t, err)
+		require.Len(t, nest.Placements, 5)
+		assert.Equal(t, "build.3mf", nest.Model.Name)
+		assert.Equal(t, "mm", nest.Model.Unit)
+		assert.InDelta(t, 4*1e3/6+1e6/6, nest.Model.Volume, 1e-3)
+		assert.InDelta(t, nest.Model.BoundingBox.Max.Z, nest.Height, 1e-9)
+		assert.InDelta(t, nest.Model.Volume/(120*120*nest.Height), nest.Density, 1e-9)
+		for _, v := range []float64{nest.Model.BoundingBox.Min.X, nest.Model.BoundingBox.Min.Y, nest.Model.BoundingBox.Min.Z} {
+			assert.GreaterOrEqual(t, v, 1-1e-9)
+		}
+		for _, v := range []float64{nest.Model.BoundingBox.Max.X, nest.Model.BoundingBox.Max.Y, nest.Model.BoundingBox.Max.Z} {
+			assert.LessOrEqual(t, v, 119+1e-9)
+		}
+
+		// every copy is its own build item
+		pkg, err := mesh.Read3MFPackage(bytes.NewReader(uploaded))
+		require.NoError(t, err)
+		require.Len(t, pkg.Items, 5)
+		mockModelRepo.AssertExpectations(t)
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("does-not-fit", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		expect(mockModelRepo, mockFilestore)
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		cylinder := req
+		cylinder.Shape, cylinder.BuildX, cylinder.BuildY = "cylinder", 80, 0
+		_, err := s.Nest(context.TODO(), mockUserID, cylinder)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockFilestore.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("box-without-depth", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+
+		s := model.NewModelService(mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		flat := req
+		flat.BuildY = 0
+		_, err := s.Nest(context.TODO(), mockUserID, flat)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockModelRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServiceGetThumbnail(t *testing.T) {
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl"}
+	var mockUserID int64 = 1
+
+	t.Run("cached", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx.thumbnail-256.png").Return(ioutil.NopCloser(strings.NewReader("cached png")), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		data, err := s.GetThumbnail(context.TODO(), mockModel.ID, mockUserID, 256)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "cached png", string(data))
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("rendered", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx.thumbnail-64.png").Return(nil, domain.ErrNotFound).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockSTL)), nil).Once()
+		mockFilestore.On("UploadWithID", mock.Anything, mock.Anything, "test.stl-xxx.thumbnail-64.png").Return(nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		data, err := s.GetThumbnail(context.TODO(), mockModel.ID, mockUserID, 64)
+
+		require.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, 64, img.Bounds().Dx())
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("invalid-size", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.GetThumbnail(context.TODO(), mockModel.ID, mockUserID, 100000)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockModelRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServiceGetSlices(t *testing.T) {
+	mockModel := domain.Model{
+		ID:          1,
+		Name:        "test.stl",
+		UserID:      1,
+		DownloadID:  "test.stl-xxx",
+		Format:      "stl",
+		BoundingBox: domain.BoundingBox{Max: domain.Point{X: 10, Y: 10, Z: 10}},
+	}
+	var mockUserID int64 = 1
+
+	t.Run("single-plane", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		layers, err := s.GetSlices(context.TODO(), mockModel.ID, mockUserID, 5, 0)
+
+		require.NoError(t, err)
+		require.Len(t, layers, 1)
+		assert.Equal(t, 5.0, layers[0].Z)
+		require.Len(t, layers[0].Polygons, 1)
+		assert.Len(t, layers[0].Polygons[0].Outline, 3)
+	})
+	t.Run("every-layer", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		layers, err := s.GetSlices(context.TODO(), mockModel.ID, mockUserID, 0, 2.5)
+
+		require.NoError(t, err)
+		assert.Len(t, layers, 4)
+	})
+	t.Run("too-many-layers", func(t *testing.T) {
+		// models stored before bounding boxes were tracked have an empty one
+		unmeasured := mockModel
+		unmeasured.BoundingBox = domain.BoundingBox{}
+
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(unmeasured, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.GetSlices(context.TODO(), mockModel.ID, mockUserID, 0, 0.0001)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockFilestore.AssertExpectations(t)
+	})
+}
+
+func TestServiceSlice(t *testing.T) {
+	mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl"}
+	var mockUserID int64 = 1
+
+	t.Run("success", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+		mockFilestore.On("Upload", mock.Anything, mock.Anything, "test.gcode").Return("test.gcode-yyy", nil).Once()
+		mockModelRepo.On("Store", mock.Anything, mock.MatchedBy(func(m *domain.Model) bool {
+			return m.Format == "gcode" && m.ParentID != nil && *m.ParentID == 1
+		})).Return(nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		gcode, err := s.Slice(context.TODO(), mockModel.ID, mockUserID, domain.DefaultPrintProfile())
+
+		require.NoError(t, err)
+		assert.Equal(t, "test.gcode", gcode.Name)
+		assert.Equal(t, "test.gcode-yyy", gcode.DownloadID)
+		assert.Empty(t, gcode.ThumbnailURL)
+		// the tip of the tetrahedron is too thin to print
+		assert.InDelta(t, 9.5, gcode.BoundingBox.Max.Z, 0.5)
+		assert.Greater(t, gcode.PrintTime, 0.0)
+		assert.Greater(t, gcode.FilamentLength, 0.0)
+		assert.Greater(t, gcode.FilamentWeight, 0.0)
+		assert.Greater(t, gcode.LayerCount, int64(0))
+		mockModelRepo.AssertExpectations(t)
+		mockFilestore.AssertExpectations(t)
+	})
+	t.Run("invalid-profile", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		profile := domain.DefaultPrintProfile()
+		profile.InfillPattern = "honeycomb"
+		_, err := s.Slice(context.TODO(), mockModel.ID, mockUserID, profile)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+	t.Run("gcode-model", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockFilestore := new(mocks.Filestore)
+		gcodeModel := domain.Model{ID: 2, Name: "test.gcode", UserID: 1, DownloadID: "test.gcode-yyy", Format: "gcode"}
+		mockModelRepo.On("GetByID", mock.Anything, int64(2), mockUserID).Return(gcodeModel, nil).Once()
+
+		s := model.NewModelService(mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.Slice(context.TODO(), gcodeModel.ID, mockUserID, domain.DefaultPrintProfile())
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockFilestore.AssertNotCalled(t, "Download", mock.Anything, mock.Anything)
+	})
 }
 
 func TestServiceDelete(t *testing.T) {
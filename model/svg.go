@@ -0,0 +1,77 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/rknizzle/rkmesh/domain"
+)
+
+// svgLayerGap is the space left between layers that are drawn below each other, as a fraction of
+// the size of the layers
+const svgLayerGap = 0.1
+
+// writeLayersSVG draws layers below each other in an SVG document, with the lowest layer at the top.
+// Holes are cut out of their polygons with the even-odd fill rule.
+func writeLayersSVG(w io.Writer, layers []domain.Layer) error {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, l := range layers {
+		for _, p := range l.Polygons {
+			for _, point := range p.Outline {
+				minX, maxX = math.Min(minX, point.X), math.Max(maxX, point.X)
+				minY, maxY = math.Min(minY, point.Y), math.Max(maxY, point.Y)
+			}
+		}
+	}
+	if minX > maxX {
+		minX, minY, maxX, maxY = 0, 0, 1, 1
+	}
+
+	width, height := maxX-minX, maxY-minY
+	step := height * (1 + svgLayerGap)
+	total := step*float64(len(layers)) - height*svgLayerGap
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s %s %s %s">`+"\n",
+		formatSVG(minX), formatSVG(0), formatSVG(width), formatSVG(math.Max(total, height)))
+
+	for i, l := range layers {
+		// SVG coordinates point down so the layers are mirrored to be seen from above
+		top := float64(i)*step + maxY
+		fmt.Fprintf(bw, `  <g id="layer-%d" data-z="%s">`+"\n", i, formatSVG(l.Z))
+		for _, p := range l.Polygons {
+			bw.WriteString(`    <path fill="#6f9fd8" fill-rule="evenodd" stroke="#1f3f68" vector-effect="non-scaling-stroke" d="`)
+			writeSVGPath(bw, p.Outline, top)
+			for _, h := range p.Holes {
+				bw.WriteString(" ")
+				writeSVGPath(bw, h, top)
+			}
+			bw.WriteString("\"/>\n")
+		}
+		bw.WriteString("  </g>\n")
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+func writeSVGPath(w *bufio.Writer, points []domain.Point2D, top float64) {
+	for i, p := range points {
+		command := "L"
+		if i == 0 {
+			command = "M"
+		}
+		fmt.Fprintf(w, "%s%s %s ", command, formatSVG(p.X), formatSVG(top-p.Y))
+	}
+	w.WriteString("Z")
+}
+
+// formatSVG rounds coordinates to a ten thousandth of a unit, which is far below the resolution of
+// any printer, to keep the document small
+func formatSVG(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"math"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+var axes = map[string]mesh.Vector{
+	"x": {X: 1},
+	"y": {Y: 1},
+	"z": {Z: 1},
+}
+
+// transformMatrix combines the operations or the matrix of a transform request into a single
+// transform. Transforms that flatten the model are rejected.
+func transformMatrix(req domain.TransformRequest) (mesh.Matrix, error) {
+	if (len(req.Operations) == 0) == (len(req.Matrix) == 0) {
+		return mesh.Matrix{}, domain.ErrBadParamInput
+	}
+
+	var res mesh.Matrix
+	if len(req.Matrix) > 0 {
+		m, err := parseMatrix(req.Matrix)
+		if err != nil {
+			return mesh.Matrix{}, err
+		}
+		res = m
+	} else {
+		res = mesh.Identity()
+		for _, op := range req.Operations {
+			m, err := operationMatrix(op)
+			if err != nil {
+				return mesh.Matrix{}, err
+			}
+			// every operation is applied after the ones before it
+			res = m.Mul(res)
+		}
+	}
+
+	if math.Abs(res.Determinant()) < 1e-12 {
+		return mesh.Matrix{}, domain.ErrBadParamInput
+	}
+	return res, nil
+}
+
+func operationMatrix(op domain.TransformOperation) (mesh.Matrix, error) {
+	switch op.Type {
+	case "rotate":
+		axis, ok := axes[op.Axis]
+		if !ok {
+			return mesh.Matrix{}, domain.ErrBadParamInput
+		}
+		return mesh.Rotation(axis, op.Angle*math.Pi/180), nil
+	case "translate":
+		return mesh.Translation(mesh.Vector{X: op.X, Y: op.Y, Z: op.Z}), nil
+	case "scale":
+		if op.Factor != 0 {
+			return mesh.Scaling(op.Factor, op.Factor, op.Factor), nil
+		}
+		return mesh.Scaling(op.X, op.Y, op.Z), nil
+	case "mirror":
+		axis, ok := axes[op.Axis]
+		if !ok {
+			return mesh.Matrix{}, domain.ErrBadParamInput
+		}
+		// mirroring across the plane through the origin that is perpendicular to the axis
+		return mesh.Scaling(1-2*axis.X, 1-2*axis.Y, 1-2*axis.Z), nil
+	default:
+		return mesh.Matrix{}, domain.ErrBadParamInput
+	}
+}
+
+// parseMatrix reads a 4x4 affine matrix. The last row has to be 0 0 0 1 because perspective
+// transforms do not keep triangles flat.
+func parseMatrix(rows [][]float64) (mesh.Matrix, error) {
+	var m mesh.Matrix
+	if len(rows) != 4 {
+		return m, domain.ErrBadParamInput
+	}
+	for i, row := range rows {
+		if len(row) != 4 {
+			return m, domain.ErrBadParamInput
+		}
+		for j, v := range row {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return m, domain.ErrBadParamInput
+			}
+			m[i][j] = v
+		}
+	}
+	if m[3] != [4]float64{0, 0, 0, 1} {
+		return m, domain.ErrBadParamInput
+	}
+	return m, nil
+}
@@ -0,0 +1,166 @@
+package printer
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/rknizzle/rkmesh/domain"
+)
+
+type responseError struct {
+	Message string `json:"message"`
+}
+
+type PrinterHandler struct {
+	Service domain.PrinterService
+}
+
+// NewPrinterHandler will initialize the /printers resources endpoints
+func NewPrinterHandler(e *echo.Group, s domain.PrinterService) {
+	handler := &PrinterHandler{
+		Service: s,
+	}
+
+	// /printers...
+	e.GET("", handler.GetAll)
+	e.POST("", handler.Store)
+	e.GET("/:id", handler.GetByID)
+	e.DELETE("/:id", handler.Delete)
+}
+
+// NewFitHandler will initialize the endpoint that checks whether models fit on a printer. It is
+// registered on the /models resources.
+func NewFitHandler(e *echo.Group, s domain.PrinterService) {
+	handler := &PrinterHandler{
+		Service: s,
+	}
+
+	// /models...
+	e.GET("/:id/fits", handler.Fit)
+}
+
+func (p *PrinterHandler) GetAll(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	list, err := p.Service.GetAllUserPrinters(ctx, userID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+func (p *PrinterHandler) GetByID(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	printer, err := p.Service.GetByID(ctx, id, userID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, printer)
+}
+
+func (p *PrinterHandler) Store(c echo.Context) error {
+	var printer domain.Printer
+	err := c.Bind(&printer)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	err = validator.New().Struct(printer)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	printer.UserID = getUserIDFromRequest(c)
+
+	err = p.Service.Store(ctx, &printer)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, printer)
+}
+
+func (p *PrinterHandler) Delete(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	err = p.Service.Delete(ctx, id, userID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Fit checks whether a model fits on the printer that the printer query param selects
+func (p *PrinterHandler) Fit(c echo.Context) error {
+	// convert the url param 'id' from a string to int64
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	printerID, err := strconv.ParseInt(c.QueryParam("printer"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseError{Message: domain.ErrBadParamInput.Error()})
+	}
+
+	ctx := c.Request().Context()
+	userID := getUserIDFromRequest(c)
+
+	fit, err := p.Service.Fit(ctx, id, printerID, userID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), responseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, fit)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func getUserIDFromRequest(c echo.Context) int64 {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	return int64(claims["user_id"].(float64))
+}
@@ -0,0 +1,137 @@
+package printer_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/domain/mocks"
+	"github.com/rknizzle/rkmesh/printer"
+)
+
+func TestHandlerGetAll(t *testing.T) {
+	mockService := new(mocks.PrinterService)
+	mockService.On("GetAllUserPrinters", mock.Anything, int64(1)).Return([]domain.Printer{mockPrinter}, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/printers", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user", mockTokenWithUserID(1))
+
+	handler := printer.PrinterHandler{
+		Service: mockService,
+	}
+	err = handler.GetAll(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"Prusa MK3S"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerStore(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"valid", `{"name":"Prusa MK3S","technology":"fdm","build_x":250,"build_y":210,"build_z":210,"nozzle_sizes":[0.4]}`, http.StatusCreated},
+		{"missing-build-volume", `{"name":"Prusa MK3S","technology":"fdm","build_x":250}`, http.StatusBadRequest},
+		{"invalid-nozzle", `{"name":"Prusa MK3S","technology":"fdm","build_x":250,"build_y":210,"build_z":210,"nozzle_sizes":[0]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.PrinterService)
+			mockService.On("Store", mock.Anything, mock.MatchedBy(func(p *domain.Printer) bool {
+				return p.UserID == 1
+			})).Return(nil)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/printers", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user", mockTokenWithUserID(1))
+
+			handler := printer.PrinterHandler{
+				Service: mockService,
+			}
+			err = handler.Store(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
+
+func TestHandlerFit(t *testing.T) {
+	mockService := new(mocks.PrinterService)
+	mockFit := domain.Fit{ModelID: 1, PrinterID: 2, Fits: true, FitsAsIs: true, Scale: 1}
+	mockService.On("Fit", mock.Anything, int64(1), int64(2), int64(1)).Return(mockFit, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/fits?printer=2", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/fits")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(1))
+
+	handler := printer.PrinterHandler{
+		Service: mockService,
+	}
+	err = handler.Fit(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"fits":true`)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlerFitMissingPrinter(t *testing.T) {
+	mockService := new(mocks.PrinterService)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/models/1/fits", nil)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("models/:id/fits")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", mockTokenWithUserID(1))
+
+	handler := printer.PrinterHandler{
+		Service: mockService,
+	}
+	err = handler.Fit(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func mockTokenWithUserID(mockUserID int64) *jwt.Token {
+	// Echo's JWT middleware gives the user_id as a float64 value
+	return &jwt.Token{
+		Claims: jwt.MapClaims{
+			"user_id": float64(mockUserID),
+		},
+	}
+}
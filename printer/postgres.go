@@ -0,0 +1,132 @@
+package printer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rknizzle/rkmesh/domain"
+)
+
+type postgresPrinterRepository struct {
+	Conn *sql.DB
+}
+
+// NewPostgresPrinterRepository will create an object that represent the printer.Repository interface
+func NewPostgresPrinterRepository(Conn *sql.DB) domain.PrinterRepository {
+	return &postgresPrinterRepository{Conn}
+}
+
+// gets all rows from the result of a sql query
+func (p *postgresPrinterRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Printer, err error) {
+	rows, err := p.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Printer, 0)
+	for rows.Next() {
+		t := domain.Printer{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.UserID,
+			&t.Technology,
+			&t.BuildX,
+			&t.BuildY,
+			&t.BuildZ,
+			pq.Array(&t.NozzleSizes),
+			pq.Array(&t.MaterialIDs),
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (p *postgresPrinterRepository) GetAllUserPrinters(ctx context.Context, userID int64) ([]domain.Printer, error) {
+	query := `SELECT * FROM printers WHERE user_id = $1 ORDER BY created_at`
+
+	return p.fetch(ctx, query, userID)
+}
+
+func (p *postgresPrinterRepository) GetByID(ctx context.Context, id int64, userID int64) (res domain.Printer, err error) {
+	query := `SELECT * FROM printers WHERE id = $1 AND user_id = $2`
+
+	list, err := p.fetch(ctx, query, id, userID)
+	if err != nil {
+		return domain.Printer{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return domain.Printer{}, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (p *postgresPrinterRepository) Store(ctx context.Context, printer *domain.Printer) (err error) {
+	query := `INSERT INTO printers (name, user_id, technology, build_x, build_y, build_z, nozzle_sizes, material_ids, updated_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING id`
+	stmt, err := p.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	var ID int64
+	err = stmt.QueryRowContext(ctx, printer.Name, printer.UserID, printer.Technology, printer.BuildX, printer.BuildY,
+		printer.BuildZ, pq.Array(printer.NozzleSizes), pq.Array(printer.MaterialIDs)).Scan(&ID)
+	if err != nil {
+		return
+	}
+
+	printer.ID = ID
+	return
+}
+
+func (p *postgresPrinterRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := `DELETE FROM printers WHERE id = $1`
+
+	stmt, err := p.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", rowsAfected)
+		return
+	}
+
+	return
+}
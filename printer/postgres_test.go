@@ -0,0 +1,87 @@
+package printer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/printer"
+)
+
+var printerColumns = []string{
+	"id", "name", "user_id", "technology", "build_x", "build_y", "build_z", "nozzle_sizes", "material_ids",
+	"updated_at", "created_at",
+}
+
+func TestPostgresGetAllUserPrinters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows(printerColumns).
+		AddRow(1, "Prusa MK3S", 1, "fdm", 250.0, 210.0, 210.0, "{0.4,0.6}", "{1,2}", time.Now(), time.Now()).
+		AddRow(2, "Form 3", 1, "sla", 145.0, 145.0, 185.0, "{}", "{4}", time.Now(), time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM printers WHERE user_id").WithArgs(1).WillReturnRows(rows)
+
+	p := printer.NewPostgresPrinterRepository(db)
+
+	list, err := p.GetAllUserPrinters(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "Prusa MK3S", list[0].Name)
+	assert.Equal(t, []float64{0.4, 0.6}, list[0].NozzleSizes)
+	assert.Equal(t, []int64{1, 2}, list[0].MaterialIDs)
+	assert.Equal(t, 185.0, list[1].BuildZ)
+}
+
+func TestPostgresGetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM printers WHERE id").WithArgs(3, 1).WillReturnRows(sqlmock.NewRows(printerColumns))
+
+	p := printer.NewPostgresPrinterRepository(db)
+
+	_, err = p.GetByID(context.TODO(), 3, 1)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestPostgresStore(t *testing.T) {
+	pr := &domain.Printer{Name: "Prusa MK3S", UserID: 1, Technology: "fdm", BuildX: 250, BuildY: 210, BuildZ: 210, NozzleSizes: []float64{0.4}, MaterialIDs: []int64{1}}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	prep := mock.ExpectPrepare("INSERT INTO printers")
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(5)
+	prep.ExpectQuery().WithArgs(pr.Name, pr.UserID, pr.Technology, pr.BuildX, pr.BuildY, pr.BuildZ, "{0.4}", "{1}").WillReturnRows(rows)
+
+	p := printer.NewPostgresPrinterRepository(db)
+
+	err = p.Store(context.TODO(), pr)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), pr.ID)
+}
+
+func TestPostgresDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	prep := mock.ExpectPrepare("DELETE FROM printers WHERE id = \\$1")
+	prep.ExpectExec().WithArgs(5).WillReturnResult(sqlmock.NewResult(5, 1))
+
+	p := printer.NewPostgresPrinterRepository(db)
+
+	err = p.Delete(context.TODO(), 5)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,130 @@
+package printer
+
+import (
+	"context"
+	"time"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/mesh"
+	"github.com/rknizzle/rkmesh/model"
+)
+
+type printerService struct {
+	printerRepo    domain.PrinterRepository
+	materialRepo   domain.MaterialRepository
+	modelRepo      domain.ModelRepository
+	filestore      domain.Filestore
+	contextTimeout time.Duration
+}
+
+// NewPrinterService will create a new printerService object representation of domain.PrinterService interface
+func NewPrinterService(pr domain.PrinterRepository, mr domain.MaterialRepository, m domain.ModelRepository, s domain.Filestore, timeout time.Duration) domain.PrinterService {
+	return &printerService{
+		printerRepo:    pr,
+		materialRepo:   mr,
+		modelRepo:      m,
+		filestore:      s,
+		contextTimeout: timeout,
+	}
+}
+
+func (s *printerService) GetAllUserPrinters(c context.Context, userID int64) ([]domain.Printer, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	return s.printerRepo.GetAllUserPrinters(ctx, userID)
+}
+
+func (s *printerService) GetByID(c context.Context, id int64, userID int64) (domain.Printer, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	return s.printerRepo.GetByID(ctx, id, userID)
+}
+
+// Store saves a printer. Every material that the printer supports has to be in the catalog or added
+// by the owner of the printer, and be used with the technology of the printer.
+func (s *printerService) Store(c context.Context, p *domain.Printer) error {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	for _, id := range p.MaterialIDs {
+		material, err := s.materialRepo.GetByID(ctx, id, p.UserID)
+		if err == domain.ErrNotFound {
+			return domain.ErrBadParamInput
+		}
+		if err != nil {
+			return err
+		}
+		if material.Process != p.Technology {
+			return domain.ErrBadParamInput
+		}
+	}
+
+	return s.printerRepo.Store(ctx, p)
+}
+
+func (s *printerService) Delete(c context.Context, id int64, userID int64) error {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	_, err := s.printerRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.printerRepo.Delete(ctx, id)
+}
+
+// Fit checks whether a model fits into the build volume of a printer in any orientation, and how
+// much it has to be scaled down when it does not
+func (s *printerService) Fit(c context.Context, modelID int64, printerID int64, userID int64) (domain.Fit, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	m, err := s.modelRepo.GetByID(ctx, modelID, userID)
+	if err != nil {
+		return domain.Fit{}, err
+	}
+
+	printer, err := s.printerRepo.GetByID(ctx, printerID, userID)
+	// the printer is part of the query so a missing one is a bad request rather than a missing model
+	if err == domain.ErrNotFound {
+		return domain.Fit{}, domain.ErrBadParamInput
+	}
+	if err != nil {
+		return domain.Fit{}, err
+	}
+
+	parsed, err := model.LoadMesh(ctx, s.filestore, m)
+	if err != nil {
+		return domain.Fit{}, err
+	}
+
+	// build volumes are in mm
+	if unit, ok := mesh.ParseUnit(m.Unit); ok && unit.Millimeters() != 1 {
+		f := unit.Millimeters()
+		parsed = parsed.Transform(mesh.Scaling(f, f, f))
+	}
+
+	volume := mesh.Vector{X: printer.BuildX, Y: printer.BuildY, Z: printer.BuildZ}
+	best := parsed.FitInto(volume)
+	size := parsed.Bounds().Size()
+
+	res := domain.Fit{
+		ModelID:   m.ID,
+		PrinterID: printer.ID,
+		Fits:      best.Fits(),
+		FitsAsIs:  size.X <= volume.X && size.Y <= volume.Y && size.Z <= volume.Z,
+		Matrix:    make([][]float64, len(best.Rotation)),
+		Size:      domain.Point{X: best.Size.X, Y: best.Size.Y, Z: best.Size.Z},
+		Scale:     1,
+	}
+	for i := range best.Rotation {
+		res.Matrix[i] = append([]float64(nil), best.Rotation[i][:]...)
+	}
+	if !res.Fits {
+		res.Scale = best.Scale
+	}
+	return res, nil
+}
@@ -0,0 +1,177 @@
+package printer_test
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/domain"
+	"github.com/rknizzle/rkmesh/domain/mocks"
+	"github.com/rknizzle/rkmesh/printer"
+)
+
+// a closed tetrahedron with its apex 10 units above its base in the ASCII STL format
+const mockTetrahedron = `solid tetrahedron
+facet normal 0 0 -1
+outer loop
+vertex 0 0 0
+vertex 0 10 0
+vertex 10 0 0
+endloop
+endfacet
+facet normal 0 -1 0
+outer loop
+vertex 0 0 0
+vertex 10 0 0
+vertex 0 0 10
+endloop
+endfacet
+facet normal -1 0 0
+outer loop
+vertex 0 0 0
+vertex 0 0 10
+vertex 0 10 0
+endloop
+endfacet
+facet normal 1 1 1
+outer loop
+vertex 10 0 0
+vertex 0 10 0
+vertex 0 0 10
+endloop
+endfacet
+endsolid tetrahedron
+`
+
+var mockPrinter = domain.Printer{ID: 2, Name: "Prusa MK3S", UserID: 1, Technology: "fdm", BuildX: 250, BuildY: 210, BuildZ: 210}
+
+func TestServiceStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPrinterRepo := new(mocks.PrinterRepository)
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		p := mockPrinter
+		p.MaterialIDs = []int64{1}
+		mockMaterialRepo.On("GetByID", mock.Anything, int64(1), mockPrinter.UserID).Return(domain.Material{ID: 1, Process: "fdm"}, nil).Once()
+		mockPrinterRepo.On("Store", mock.Anything, &p).Return(nil).Once()
+
+		s := printer.NewPrinterService(mockPrinterRepo, mockMaterialRepo, new(mocks.ModelRepository), new(mocks.Filestore), time.Second*2)
+
+		err := s.Store(context.TODO(), &p)
+
+		assert.NoError(t, err)
+		mockPrinterRepo.AssertExpectations(t)
+	})
+	t.Run("material-of-other-technology", func(t *testing.T) {
+		mockPrinterRepo := new(mocks.PrinterRepository)
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		p := mockPrinter
+		p.MaterialIDs = []int64{4}
+		mockMaterialRepo.On("GetByID", mock.Anything, int64(4), mockPrinter.UserID).Return(domain.Material{ID: 4, Process: "sla"}, nil).Once()
+
+		s := printer.NewPrinterService(mockPrinterRepo, mockMaterialRepo, new(mocks.ModelRepository), new(mocks.Filestore), time.Second*2)
+
+		err := s.Store(context.TODO(), &p)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockPrinterRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+	t.Run("unknown-material", func(t *testing.T) {
+		mockMaterialRepo := new(mocks.MaterialRepository)
+		p := mockPrinter
+		p.MaterialIDs = []int64{9}
+		mockMaterialRepo.On("GetByID", mock.Anything, int64(9), mockPrinter.UserID).Return(domain.Material{}, domain.ErrNotFound).Once()
+
+		s := printer.NewPrinterService(new(mocks.PrinterRepository), mockMaterialRepo, new(mocks.ModelRepository), new(mocks.Filestore), time.Second*2)
+
+		err := s.Store(context.TODO(), &p)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
+
+func TestServiceDelete(t *testing.T) {
+	mockPrinterRepo := new(mocks.PrinterRepository)
+	mockPrinterRepo.On("GetByID", mock.Anything, int64(2), int64(1)).Return(mockPrinter, nil).Once()
+	mockPrinterRepo.On("Delete", mock.Anything, int64(2)).Return(nil).Once()
+
+	s := printer.NewPrinterService(mockPrinterRepo, new(mocks.MaterialRepository), new(mocks.ModelRepository), new(mocks.Filestore), time.Second*2)
+
+	err := s.Delete(context.TODO(), 2, 1)
+
+	assert.NoError(t, err)
+	mockPrinterRepo.AssertExpectations(t)
+}
+
+func TestServiceFit(t *testing.T) {
+	var mockUserID int64 = 1
+
+	fit := func(t *testing.T, unit string, p domain.Printer) (domain.Fit, error) {
+		mockModel := domain.Model{ID: 1, Name: "test.stl", UserID: 1, DownloadID: "test.stl-xxx", Format: "stl", Unit: unit}
+		mockModelRepo := new(mocks.ModelRepository)
+		mockPrinterRepo := new(mocks.PrinterRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(mockModel, nil).Once()
+		mockPrinterRepo.On("GetByID", mock.Anything, p.ID, mockUserID).Return(p, nil).Once()
+		mockFilestore.On("Download", mock.Anything, "test.stl-xxx").Return(ioutil.NopCloser(strings.NewReader(mockTetrahedron)), nil).Once()
+
+		s := printer.NewPrinterService(mockPrinterRepo, new(mocks.MaterialRepository), mockModelRepo, mockFilestore, time.Second*2)
+		return s.Fit(context.TODO(), 1, p.ID, mockUserID)
+	}
+
+	t.Run("fits", func(t *testing.T) {
+		res, err := fit(t, "mm", mockPrinter)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), res.ModelID)
+		assert.Equal(t, int64(2), res.PrinterID)
+		assert.True(t, res.Fits)
+		assert.True(t, res.FitsAsIs)
+		assert.Equal(t, 1.0, res.Scale)
+		assert.Len(t, res.Matrix, 4)
+	})
+	t.Run("too-large", func(t *testing.T) {
+		// in inches the tetrahedron is 254mm along each axis
+		res, err := fit(t, "inch", domain.Printer{ID: 3, BuildX: 100, BuildY: 100, BuildZ: 100})
+
+		require.NoError(t, err)
+		assert.False(t, res.Fits)
+		assert.False(t, res.FitsAsIs)
+		assert.Less(t, res.Scale, 1.0)
+		// no rotation has to be scaled down more than the tetrahedron as it is
+		assert.GreaterOrEqual(t, res.Scale, 100/254.0)
+		assert.InDelta(t, 100, res.Scale*math.Max(res.Size.X, math.Max(res.Size.Y, res.Size.Z)), 1e-6)
+	})
+	t.Run("unknown-printer", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockPrinterRepo := new(mocks.PrinterRepository)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(domain.Model{ID: 1, Format: "stl"}, nil).Once()
+		mockPrinterRepo.On("GetByID", mock.Anything, int64(7), mockUserID).Return(domain.Printer{}, domain.ErrNotFound).Once()
+
+		s := printer.NewPrinterService(mockPrinterRepo, new(mocks.MaterialRepository), mockModelRepo, new(mocks.Filestore), time.Second*2)
+
+		_, err := s.Fit(context.TODO(), 1, 7, mockUserID)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+	t.Run("gcode", func(t *testing.T) {
+		mockModelRepo := new(mocks.ModelRepository)
+		mockPrinterRepo := new(mocks.PrinterRepository)
+		mockFilestore := new(mocks.Filestore)
+		mockModelRepo.On("GetByID", mock.Anything, int64(1), mockUserID).Return(domain.Model{ID: 1, Format: domain.GCodeFormat}, nil).Once()
+		mockPrinterRepo.On("GetByID", mock.Anything, mockPrinter.ID, mockUserID).Return(mockPrinter, nil).Once()
+
+		s := printer.NewPrinterService(mockPrinterRepo, new(mocks.MaterialRepository), mockModelRepo, mockFilestore, time.Second*2)
+
+		_, err := s.Fit(context.TODO(), 1, mockPrinter.ID, mockUserID)
+
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockFilestore.AssertNotCalled(t, "Download", mock.Anything, mock.Anything)
+	})
+}
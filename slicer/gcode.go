@@ -0,0 +1,135 @@
+package slicer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// gcodeWriter writes the moves of a print as G-code and keeps track of the state of the printer
+type gcodeWriter struct {
+	w       *bufio.Writer
+	profile Profile
+	// extrusion is the length of filament that is extruded per mm of a line
+	extrusion float64
+
+	position  mesh.Vector2
+	z         float64
+	e         float64
+	retracted bool
+
+	bounds  mesh.Box
+	printed bool
+}
+
+func newGCodeWriter(w io.Writer, p Profile, width float64) *gcodeWriter {
+	radius := p.FilamentDiameter / 2
+	return &gcodeWriter{
+		w:         bufio.NewWriter(w),
+		profile:   p,
+		extrusion: width * p.LayerHeight / (math.Pi * radius * radius),
+	}
+}
+
+func (g *gcodeWriter) start() {
+	p := g.profile
+	fmt.Fprintln(g.w, "; generated by rkmesh")
+	fmt.Fprintf(g.w, "; layer_height = %g\n", p.LayerHeight)
+	fmt.Fprintf(g.w, "; nozzle_diameter = %g\n", p.NozzleDiameter)
+	fmt.Fprintf(g.w, "; perimeters = %d\n", p.Perimeters)
+	fmt.Fprintf(g.w, "; infill = %g%% %s\n", p.InfillDensity*100, p.InfillPattern)
+	fmt.Fprintf(g.w, "M140 S%g ; set bed temperature\n", p.BedTemperature)
+	fmt.Fprintf(g.w, "M104 S%g ; set nozzle temperature\n", p.NozzleTemperature)
+	fmt.Fprintf(g.w, "M190 S%g ; wait for bed temperature\n", p.BedTemperature)
+	fmt.Fprintf(g.w, "M109 S%g ; wait for nozzle temperature\n", p.NozzleTemperature)
+	fmt.Fprintln(g.w, "G28 ; home all axes")
+	fmt.Fprintln(g.w, "G90 ; absolute positioning")
+	fmt.Fprintln(g.w, "M82 ; absolute extrusion")
+	fmt.Fprintln(g.w, "G92 E0")
+	fmt.Fprintln(g.w, "M107")
+}
+
+func (g *gcodeWriter) layer(i int, z float64) {
+	fmt.Fprintf(g.w, ";LAYER:%d\n", i)
+	if i == 1 {
+		fmt.Fprintln(g.w, "M106 S255 ; turn the part cooling fan on")
+	}
+	g.z = z
+	fmt.Fprintf(g.w, "G0 Z%.3f F%.0f\n", z, g.profile.TravelSpeed*60)
+}
+
+// loop prints a closed loop starting from its point that is closest to the nozzle
+func (g *gcodeWriter) loop(points []mesh.Vector2, speed float64) {
+	first := 0
+	for i, p := range points {
+		if distance(p, g.position) < distance(points[first], g.position) {
+			first = i
+		}
+	}
+
+	g.travel(points[first])
+	for i := 1; i <= len(points); i++ {
+		g.extrude(points[(first+i)%len(points)], speed)
+	}
+}
+
+func (g *gcodeWriter) travel(to mesh.Vector2) {
+	d := distance(g.position, to)
+	if d == 0 {
+		return
+	}
+
+	if d > minRetractDistance {
+		g.retract()
+	}
+	fmt.Fprintf(g.w, "G0 X%.3f Y%.3f F%.0f\n", to.X, to.Y, g.profile.TravelSpeed*60)
+	g.position = to
+}
+
+func (g *gcodeWriter) extrude(to mesh.Vector2, speed float64) {
+	if g.retracted {
+		fmt.Fprintf(g.w, "G1 E%.5f F%.0f\n", g.e, g.profile.RetractionSpeed*60)
+		g.retracted = false
+	}
+
+	g.e += distance(g.position, to) * g.extrusion
+	fmt.Fprintf(g.w, "G1 X%.3f Y%.3f E%.5f F%.0f\n", to.X, to.Y, g.e, speed*60)
+
+	for _, p := range []mesh.Vector2{g.position, to} {
+		v := mesh.Vector{X: p.X, Y: p.Y, Z: g.z}
+		if !g.printed {
+			g.bounds = mesh.Box{Min: v, Max: v}
+			g.printed = true
+		}
+		g.bounds.Min = g.bounds.Min.Min(v)
+		g.bounds.Max = g.bounds.Max.Max(v)
+	}
+	g.position = to
+}
+
+// retract pulls the filament back before a travel move so that it does not ooze
+func (g *gcodeWriter) retract() {
+	if g.retracted || g.profile.RetractionLength == 0 {
+		return
+	}
+	fmt.Fprintf(g.w, "G1 E%.5f F%.0f\n", g.e-g.profile.RetractionLength, g.profile.RetractionSpeed*60)
+	g.retracted = true
+}
+
+func (g *gcodeWriter) end() {
+	g.retract()
+	fmt.Fprintln(g.w, "M107")
+	fmt.Fprintln(g.w, "M104 S0 ; turn the nozzle heater off")
+	fmt.Fprintln(g.w, "M140 S0 ; turn the bed heater off")
+	fmt.Fprintln(g.w, "G91")
+	fmt.Fprintf(g.w, "G0 Z10 F%.0f ; move the nozzle away from the print\n", g.profile.TravelSpeed*60)
+	fmt.Fprintln(g.w, "G90")
+	fmt.Fprintln(g.w, "M84 ; disable the motors")
+}
+
+func (g *gcodeWriter) flush() error {
+	return g.w.Flush()
+}
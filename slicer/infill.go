@@ -0,0 +1,103 @@
+package slicer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// interval is a range along a scanline
+type interval struct {
+	lo, hi float64
+}
+
+// frame rotates the plane of a layer so that infill lines at its angle run along the U axis
+type frame struct {
+	cos, sin float64
+}
+
+func newFrame(angle float64) frame {
+	return frame{cos: math.Cos(angle), sin: math.Sin(angle)}
+}
+
+// toFrame returns the position of a point along (u) and across (v) the lines of the frame
+func (f frame) toFrame(p mesh.Vector2) mesh.Vector2 {
+	return mesh.Vector2{X: p.X*f.cos + p.Y*f.sin, Y: -p.X*f.sin + p.Y*f.cos}
+}
+
+func (f frame) fromFrame(u, v float64) mesh.Vector2 {
+	return mesh.Vector2{X: u*f.cos - v*f.sin, Y: u*f.sin + v*f.cos}
+}
+
+func (f frame) rotate(loops [][]mesh.Vector2) [][]mesh.Vector2 {
+	rotated := make([][]mesh.Vector2, len(loops))
+	for i, loop := range loops {
+		rotated[i] = make([]mesh.Vector2, len(loop))
+		for j, p := range loop {
+			rotated[i][j] = f.toFrame(p)
+		}
+	}
+	return rotated
+}
+
+// scanline returns the parts of the line at height v that are inside of the loops by the even-odd
+// rule
+func scanline(loops [][]mesh.Vector2, v float64) []interval {
+	var crossings []float64
+	for _, loop := range loops {
+		for i, a := range loop {
+			b := loop[(i+1)%len(loop)]
+			if (a.Y > v) != (b.Y > v) {
+				crossings = append(crossings, a.X+(v-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+			}
+		}
+	}
+	sort.Float64s(crossings)
+
+	intervals := make([]interval, 0, len(crossings)/2)
+	for i := 0; i+1 < len(crossings); i += 2 {
+		intervals = append(intervals, interval{crossings[i], crossings[i+1]})
+	}
+	return intervals
+}
+
+// intersectIntervals returns the ranges covered by both sorted lists of intervals
+func intersectIntervals(a, b []interval) []interval {
+	var res []interval
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		lo, hi := math.Max(a[i].lo, b[j].lo), math.Min(a[i].hi, b[j].hi)
+		if lo < hi {
+			res = append(res, interval{lo, hi})
+		}
+		if a[i].hi < b[j].hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
+
+// subtractIntervals returns the ranges of a that are not covered by b. Both lists are sorted.
+func subtractIntervals(a, b []interval) []interval {
+	var res []interval
+	j := 0
+	for _, in := range a {
+		lo := in.lo
+		for j < len(b) && b[j].hi <= lo {
+			j++
+		}
+		for k := j; k < len(b) && b[k].lo < in.hi; k++ {
+			if b[k].lo > lo {
+				res = append(res, interval{lo, b[k].lo})
+			}
+			lo = math.Max(lo, b[k].hi)
+		}
+		if lo < in.hi {
+			res = append(res, interval{lo, in.hi})
+		}
+	}
+	return res
+}
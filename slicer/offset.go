@@ -0,0 +1,114 @@
+package slicer
+
+import (
+	"math"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+// miterLimit is how far, as a multiple of the offset distance, a sharp corner may extend before it
+// is cut off
+const miterLimit = 3
+
+// line is an edge of a loop moved sideways by the offset distance
+type line struct {
+	point     mesh.Vector2
+	direction mesh.Vector2
+}
+
+// offsetLoop moves every edge of a closed loop a distance d to its left and joins neighboring edges
+// where they intersect. Since outlines are counter-clockwise and holes clockwise, a positive
+// distance shrinks the material of a polygon. Edges that reverse because the loop is narrower than
+// the offset are removed, and nil is returned when the whole loop collapses.
+func offsetLoop(loop []mesh.Vector2, d float64) []mesh.Vector2 {
+	var lines []line
+	for i, a := range loop {
+		b := loop[(i+1)%len(loop)]
+		dir := sub(b, a)
+		length := math.Hypot(dir.X, dir.Y)
+		if length == 0 {
+			continue
+		}
+		dir = scale(dir, 1/length)
+		normal := mesh.Vector2{X: -dir.Y, Y: dir.X}
+		lines = append(lines, line{point: add(a, scale(normal, d)), direction: dir})
+	}
+
+	for len(lines) >= 3 {
+		points := make([]mesh.Vector2, len(lines))
+		for i := range lines {
+			points[i] = join(lines[(i+len(lines)-1)%len(lines)], lines[i], d)
+		}
+
+		// drop the edges that point backwards after the offset, their neighbors meet directly
+		kept := lines[:0]
+		for i, l := range lines {
+			edge := sub(points[(i+1)%len(points)], points[i])
+			if dot(edge, l.direction) > 0 {
+				kept = append(kept, l)
+			}
+		}
+		if len(kept) == len(lines) {
+			if area(points)*area(loop) <= 0 {
+				return nil
+			}
+			return points
+		}
+		lines = kept
+	}
+
+	return nil
+}
+
+// join returns the corner between two consecutive offset edges
+func join(a, b line, d float64) mesh.Vector2 {
+	denominator := cross(a.direction, b.direction)
+	if math.Abs(denominator) < 1e-9 {
+		return b.point
+	}
+
+	t := cross(sub(b.point, a.point), b.direction) / denominator
+	p := add(a.point, scale(a.direction, t))
+
+	// cut off long spikes at sharp corners by pulling the corner back towards the edges
+	corner := sub(b.point, scale(mesh.Vector2{X: -b.direction.Y, Y: b.direction.X}, d))
+	offset := sub(p, corner)
+	if length := math.Hypot(offset.X, offset.Y); length > miterLimit*math.Abs(d) {
+		p = add(corner, scale(offset, miterLimit*math.Abs(d)/length))
+	}
+	return p
+}
+
+// area returns the signed area of a loop, which is positive when it is counter-clockwise
+func area(loop []mesh.Vector2) float64 {
+	var a float64
+	for i, p := range loop {
+		q := loop[(i+1)%len(loop)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+	return a / 2
+}
+
+func add(a, b mesh.Vector2) mesh.Vector2 {
+	return mesh.Vector2{X: a.X + b.X, Y: a.Y + b.Y}
+}
+
+func sub(a, b mesh.Vector2) mesh.Vector2 {
+	return mesh.Vector2{X: a.X - b.X, Y: a.Y - b.Y}
+}
+
+func scale(a mesh.Vector2, s float64) mesh.Vector2 {
+	return mesh.Vector2{X: a.X * s, Y: a.Y * s}
+}
+
+func dot(a, b mesh.Vector2) float64 {
+	return a.X*b.X + a.Y*b.Y
+}
+
+func cross(a, b mesh.Vector2) float64 {
+	return a.X*b.Y - a.Y*b.X
+}
+
+func distance(a, b mesh.Vector2) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
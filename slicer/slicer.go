@@ -0,0 +1,295 @@
+// Package slicer turns meshes into G-code for FDM 3D printers. Every layer is printed as a number of
+// perimeters along its outlines, solid infill where the surface of the model is near and sparse
+// rectilinear infill everywhere else.
+package slicer
+
+import (
+	"errors"
+	"io"
+	"math"
+
+	"github.com/rknizzle/rkmesh/mesh"
+)
+
+var (
+	// ErrInvalidProfile will throw if a print profile has settings that can not be printed
+	ErrInvalidProfile = errors.New("Print profile is not valid")
+	// ErrNothingToPrint will throw if a mesh is too flat to have a single layer
+	ErrNothingToPrint = errors.New("Model does not have any layers to print")
+)
+
+// Pattern is the arrangement of the sparse infill lines
+type Pattern string
+
+const (
+	// PatternRectilinear is parallel lines that turn by 90 degrees on every layer
+	PatternRectilinear Pattern = "rectilinear"
+	// PatternGrid is lines in both directions on every layer
+	PatternGrid Pattern = "grid"
+)
+
+const (
+	// firstLayerSpeedFactor slows the first layer down so that it sticks to the bed
+	firstLayerSpeedFactor = 0.5
+	// minRetractDistance is the shortest travel move in mm that the filament is retracted for
+	minRetractDistance = 1.0
+)
+
+// Profile holds the settings that a model is printed with. Lengths are in mm, speeds in mm/s and
+// temperatures in degrees Celsius.
+type Profile struct {
+	LayerHeight      float64
+	NozzleDiameter   float64
+	FilamentDiameter float64
+	// Perimeters is the number of walls printed along every outline
+	Perimeters int
+	// InfillDensity is the fraction of the inside of the model that is filled, from 0 to 1
+	InfillDensity float64
+	InfillPattern Pattern
+	// TopLayers and BottomLayers are the number of solid layers below upwards facing surfaces and
+	// above downwards facing surfaces
+	TopLayers         int
+	BottomLayers      int
+	NozzleTemperature float64
+	BedTemperature    float64
+	PrintSpeed        float64
+	TravelSpeed       float64
+	RetractionLength  float64
+	RetractionSpeed   float64
+	// BedCenter is where the center of the model is placed on the bed
+	BedCenter mesh.Vector2
+}
+
+func (p Profile) validate() error {
+	valid := p.LayerHeight > 0 && p.NozzleDiameter > 0 && p.FilamentDiameter > 0 &&
+		p.Perimeters >= 0 && p.InfillDensity >= 0 && p.InfillDensity <= 1 &&
+		(p.InfillPattern == PatternRectilinear || p.InfillPattern == PatternGrid) &&
+		p.TopLayers >= 0 && p.BottomLayers >= 0 &&
+		p.PrintSpeed > 0 && p.TravelSpeed > 0 &&
+		p.RetractionLength >= 0 && (p.RetractionLength == 0 || p.RetractionSpeed > 0)
+	if !valid {
+		return ErrInvalidProfile
+	}
+	return nil
+}
+
+// Result describes the G-code that a mesh was sliced into
+type Result struct {
+	Layers int
+	// FilamentLength is the length of filament in mm that the print uses
+	FilamentLength float64
+	// Bounds encloses everything that is printed
+	Bounds mesh.Box
+}
+
+// Slice writes the G-code to print a mesh with a profile. The mesh is centered on the bed and
+// placed with its lowest point on it.
+func Slice(w io.Writer, m *mesh.Mesh, p Profile) (Result, error) {
+	err := p.validate()
+	if err != nil {
+		return Result{}, err
+	}
+
+	layers := place(m, p.BedCenter).Slices(p.LayerHeight)
+	if len(layers) == 0 {
+		return Result{}, ErrNothingToPrint
+	}
+
+	s := &slicer{
+		profile: p,
+		layers:  layers,
+		width:   p.NozzleDiameter,
+		frames:  [2]frame{newFrame(math.Pi / 4), newFrame(3 * math.Pi / 4)},
+		regions: make(map[[2]int][][]mesh.Vector2),
+	}
+
+	g := newGCodeWriter(w, p, s.width)
+	g.start()
+	for i := range layers {
+		s.layer(g, i)
+	}
+	g.end()
+
+	err = g.flush()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Layers: len(layers), FilamentLength: g.e, Bounds: g.bounds}, nil
+}
+
+// place returns a copy of the mesh that is centered on a point with its lowest point at zero
+func place(m *mesh.Mesh, center mesh.Vector2) *mesh.Mesh {
+	bounds := m.Bounds()
+	offset := mesh.Vector{
+		X: center.X - (bounds.Min.X+bounds.Max.X)/2,
+		Y: center.Y - (bounds.Min.Y+bounds.Max.Y)/2,
+		Z: -bounds.Min.Z,
+	}
+
+	placed := &mesh.Mesh{Vertices: make([]mesh.Vector, len(m.Vertices)), Triangles: m.Triangles}
+	for i, v := range m.Vertices {
+		placed.Vertices[i] = v.Add(offset)
+	}
+	return placed
+}
+
+type slicer struct {
+	profile Profile
+	layers  []mesh.Layer
+	// width is the width of an extruded line
+	width  float64
+	frames [2]frame
+	// regions caches the loops of a layer rotated into the frame of an infill direction, keyed by
+	// the layer and the index of the frame
+	regions map[[2]int][][]mesh.Vector2
+}
+
+func (s *slicer) layer(g *gcodeWriter, i int) {
+	p := s.profile
+	g.layer(i, float64(i+1)*p.LayerHeight)
+
+	speed := p.PrintSpeed
+	if i == 0 {
+		speed *= firstLayerSpeedFactor
+	}
+
+	// the infill ends on the inner edge of the innermost perimeter so that the two bond
+	inset := math.Max(float64(p.Perimeters)*s.width, s.width/2)
+
+	var infill [][]mesh.Vector2
+	for _, polygon := range s.layers[i].Polygons {
+		loops := append([][]mesh.Vector2{polygon.Outline}, polygon.Holes...)
+
+		// perimeters are printed from the inside out so that the outer wall has something to lean on
+		for k := p.Perimeters - 1; k >= 0; k-- {
+			for _, loop := range loops {
+				if wall := offsetLoop(loop, s.width/2+float64(k)*s.width); wall != nil {
+					g.loop(wall, speed)
+				}
+			}
+		}
+
+		for _, loop := range loops {
+			if inner := offsetLoop(loop, inset); inner != nil {
+				infill = append(infill, inner)
+			}
+		}
+	}
+
+	n := 0
+	if p.InfillDensity > 0 {
+		n = int(math.Round(1 / p.InfillDensity))
+	}
+
+	direction := i % 2
+	var lines [][2]mesh.Vector2
+	switch p.InfillPattern {
+	case PatternRectilinear:
+		lines = s.infill(i, infill, direction, n, true)
+	case PatternGrid:
+		// the lines are split between both directions so the density stays the same
+		lines = s.infill(i, infill, direction, 2*n, true)
+		lines = append(lines, s.infill(i, infill, 1-direction, 2*n, false)...)
+	}
+
+	for _, l := range lines {
+		g.travel(l[0])
+		g.extrude(l[1], speed)
+	}
+}
+
+// infill returns the infill lines of a layer in one direction, in the order that they are printed.
+// The lines lie on a grid with the spacing of the line width so they line up between layers.
+// Every line is solid infill where the layer is close to the top or bottom of the model, and every
+// n-th line is also sparse infill everywhere else. With solid false only the sparse infill is
+// returned.
+func (s *slicer) infill(i int, loops [][]mesh.Vector2, direction int, n int, solid bool) [][2]mesh.Vector2 {
+	f := s.frames[direction]
+	rotated := f.rotate(loops)
+
+	minV, maxV := math.Inf(1), math.Inf(-1)
+	for _, loop := range rotated {
+		for _, p := range loop {
+			minV, maxV = math.Min(minV, p.Y), math.Max(maxV, p.Y)
+		}
+	}
+
+	var lines [][2]mesh.Vector2
+	forward := true
+	for k := int(math.Ceil(minV / s.width)); float64(k)*s.width <= maxV; k++ {
+		v := float64(k) * s.width
+		inside := scanline(rotated, v)
+		if len(inside) == 0 {
+			continue
+		}
+
+		sparse := n > 0 && ((k%n)+n)%n == 0
+		var parts []interval
+		switch {
+		case solid && sparse:
+			parts = inside
+		case solid:
+			parts = subtractIntervals(inside, s.covered(i, direction, v))
+		case sparse:
+			parts = intersectIntervals(inside, s.covered(i, direction, v))
+		}
+
+		// alternate the direction of every line so that the nozzle zigzags across the layer
+		var printed [][2]mesh.Vector2
+		for _, part := range parts {
+			if part.hi-part.lo < s.width {
+				continue
+			}
+			a, b := f.fromFrame(part.lo, v), f.fromFrame(part.hi, v)
+			if forward {
+				printed = append(printed, [2]mesh.Vector2{a, b})
+			} else {
+				printed = append([][2]mesh.Vector2{{b, a}}, printed...)
+			}
+		}
+		if len(printed) > 0 {
+			lines = append(lines, printed...)
+			forward = !forward
+		}
+	}
+	return lines
+}
+
+// covered returns the parts of an infill line that are inside of every layer within the number of
+// top and bottom layers of layer i. Those parts are far enough away from the surface of the model
+// to be filled with sparse infill.
+func (s *slicer) covered(i int, direction int, v float64) []interval {
+	res := []interval{{math.Inf(-1), math.Inf(1)}}
+	for j := i - s.profile.BottomLayers; j <= i+s.profile.TopLayers; j++ {
+		if j == i {
+			continue
+		}
+		if j < 0 || j >= len(s.layers) {
+			return nil
+		}
+
+		res = intersectIntervals(res, scanline(s.region(j, direction), v))
+		if len(res) == 0 {
+			return nil
+		}
+	}
+	return res
+}
+
+// region returns all loops of a layer rotated into the frame of an infill direction
+func (s *slicer) region(j int, direction int) [][]mesh.Vector2 {
+	key := [2]int{j, direction}
+	if r, ok := s.regions[key]; ok {
+		return r
+	}
+
+	var loops [][]mesh.Vector2
+	for _, polygon := range s.layers[j].Polygons {
+		loops = append(loops, polygon.Outline)
+		loops = append(loops, polygon.Holes...)
+	}
+	r := s.frames[direction].rotate(loops)
+	s.regions[key] = r
+	return r
+}
@@ -0,0 +1,145 @@
+package slicer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rknizzle/rkmesh/mesh"
+	"github.com/rknizzle/rkmesh/slicer"
+)
+
+// cube returns a closed cube with its minimum corner at the origin
+func cube(size float64) *mesh.Mesh {
+	m := &mesh.Mesh{}
+	for i := 0; i < 8; i++ {
+		m.Vertices = append(m.Vertices, mesh.Vector{
+			X: float64(i&1) * size,
+			Y: float64(i>>1&1) * size,
+			Z: float64(i>>2&1) * size,
+		})
+	}
+	m.Triangles = []mesh.Triangle{
+		{0, 2, 1}, {1, 2, 3},
+		{4, 5, 6}, {5, 7, 6},
+		{0, 1, 4}, {1, 5, 4},
+		{2, 6, 3}, {3, 6, 7},
+		{0, 4, 2}, {2, 4, 6},
+		{1, 3, 5}, {3, 7, 5},
+	}
+	return m
+}
+
+func profile() slicer.Profile {
+	return slicer.Profile{
+		LayerHeight:       0.2,
+		NozzleDiameter:    0.4,
+		FilamentDiameter:  1.75,
+		Perimeters:        2,
+		InfillDensity:     0.2,
+		InfillPattern:     slicer.PatternRectilinear,
+		TopLayers:         3,
+		BottomLayers:      3,
+		NozzleTemperature: 210,
+		BedTemperature:    60,
+		PrintSpeed:        50,
+		TravelSpeed:       150,
+		RetractionLength:  1,
+		RetractionSpeed:   40,
+		BedCenter:         mesh.Vector2{X: 100, Y: 100},
+	}
+}
+
+// layerMoves counts the extruding moves of every layer in a G-code file
+func layerMoves(gcode string) []int {
+	var moves []int
+	for _, line := range strings.Split(gcode, "\n") {
+		if strings.HasPrefix(line, ";LAYER:") {
+			moves = append(moves, 0)
+		} else if strings.HasPrefix(line, "G1 X") && len(moves) > 0 {
+			moves[len(moves)-1]++
+		}
+	}
+	return moves
+}
+
+func TestSlice(t *testing.T) {
+	var buf bytes.Buffer
+	res, err := slicer.Slice(&buf, cube(10), profile())
+	require.NoError(t, err)
+
+	gcode := buf.String()
+	assert.Equal(t, 50, res.Layers)
+	assert.Contains(t, gcode, "M104 S210")
+	assert.Contains(t, gcode, "M140 S60")
+	assert.Contains(t, gcode, ";LAYER:49\n")
+
+	// the outer wall is half a line width inside of the cube, which is centered on the bed
+	assert.InDelta(t, 95.2, res.Bounds.Min.X, 1e-9)
+	assert.InDelta(t, 104.8, res.Bounds.Max.Y, 1e-9)
+	assert.InDelta(t, 0.2, res.Bounds.Min.Z, 1e-9)
+	assert.InDelta(t, 10, res.Bounds.Max.Z, 1e-9)
+	assert.Contains(t, gcode, "G1 X104.800 Y104.800")
+
+	// a solid 10x10x10 cube needs roughly its volume in filament
+	filamentVolume := res.FilamentLength * 3.14159 * 0.875 * 0.875
+	assert.InEpsilon(t, 1000*0.4, filamentVolume, 0.5)
+
+	// the bottom and top layers are solid while the middle only has sparse infill
+	moves := layerMoves(gcode)
+	require.Len(t, moves, 50)
+	assert.Greater(t, moves[0], 2*moves[25])
+	assert.Greater(t, moves[49], 2*moves[25])
+	assert.Equal(t, moves[0], moves[2])
+}
+
+func TestSliceRetraction(t *testing.T) {
+	// two cubes side by side, the nozzle has to travel between them on every layer
+	m := cube(5)
+	other := cube(5)
+	for i := range other.Vertices {
+		other.Vertices[i].X += 20
+	}
+	m.Append(other)
+
+	var buf bytes.Buffer
+	_, err := slicer.Slice(&buf, m, profile())
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "F2400\nG0 X")
+}
+
+func TestSliceGrid(t *testing.T) {
+	p := profile()
+	p.InfillPattern = slicer.PatternGrid
+
+	var buf bytes.Buffer
+	_, err := slicer.Slice(&buf, cube(10), p)
+	require.NoError(t, err)
+
+	moves := layerMoves(buf.String())
+	assert.NotZero(t, moves[25])
+}
+
+func TestSliceInvalidProfile(t *testing.T) {
+	p := profile()
+	p.InfillDensity = 2
+
+	_, err := slicer.Slice(&bytes.Buffer{}, cube(10), p)
+
+	assert.Equal(t, slicer.ErrInvalidProfile, err)
+}
+
+func TestSliceFlatMesh(t *testing.T) {
+	m := &mesh.Mesh{
+		Vertices:  []mesh.Vector{{}, {X: 1}, {Y: 1}},
+		Triangles: []mesh.Triangle{{0, 1, 2}},
+	}
+
+	_, err := slicer.Slice(&bytes.Buffer{}, m, profile())
+
+	assert.Equal(t, slicer.ErrNothingToPrint, err)
+}
@@ -38,18 +38,25 @@ func Open() (TestDB, *sql.DB, error) {
 	return tdb, dbConn, nil
 }
 
-// Truncate removes all seed data from the test database
+// Truncate removes all seed data from the test database. Every table that references users is
+// emptied with them, except for the catalog of materials that the migrations seed.
 func (t *TestDB) Truncate() error {
-	query := "TRUNCATE TABLE models, users;"
-
-	stmt, err := t.Conn.PrepareContext(context.TODO(), query)
-	if err != nil {
-		return err
+	queries := []string{
+		"TRUNCATE TABLE printers, model_analyses, models;",
+		"DELETE FROM materials WHERE user_id IS NOT NULL;",
+		"DELETE FROM users;",
 	}
 
-	_, err = stmt.ExecContext(context.TODO())
-	if err != nil {
-		return err
+	for _, query := range queries {
+		stmt, err := t.Conn.PrepareContext(context.TODO(), query)
+		if err != nil {
+			return err
+		}
+
+		_, err = stmt.ExecContext(context.TODO())
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil